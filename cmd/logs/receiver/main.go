@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"oldfartscounter/internal/logparser"
+	"oldfartscounter/internal/output"
+	"oldfartscounter/internal/receiver"
+	"oldfartscounter/internal/stats"
+)
+
+var (
+	addrFlag        = flag.String("addr", ":8080", "Адрес HTTP сервера приёма логов (logaddress_add_http \"http://<хост>:8080/logs/<сервер>\")")
+	outHTML         = flag.String("html", "", "Перегенерировать HTML по принятым матчам после каждого матча (пусто = не писать)")
+	idleFlag        = flag.Duration("idle", 10*time.Minute, "Забыть сервер (табло и незавершённый матч), если от него нет строк дольше этого")
+	maxServersFlag  = flag.Int("max-servers", 64, "Сколько серверов принимать одновременно: пачки от новых сверх этого отклоняются")
+	highlightPlayer = flag.String("highlight", "maslina420", "Игрок для золотой подсветки в табе 'Сорян, Братан'")
+	replayFlag      = flag.String("replay", "", "Вместо приёма отправить записанный лог на -url, как это делает сервер CS2")
+	urlFlag         = flag.String("url", "http://localhost:8080/logs/local", "Куда -replay отправляет строки")
+	batchFlag       = flag.Int("batch", 100, "Строк в одном запросе -replay")
+	delayFlag       = flag.Duration("delay", 0, "Пауза между запросами -replay")
+)
+
+func main() {
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *replayFlag != "" {
+		if err := replay(ctx, *replayFlag); err != nil {
+			log.Fatalf("ошибка отправки лога: %v", err)
+		}
+		return
+	}
+
+	if err := serve(ctx); err != nil {
+		log.Fatalf("ошибка HTTP сервера: %v", err)
+	}
+}
+
+// serve принимает логи до Ctrl+C
+func serve(ctx context.Context) error {
+	processor := stats.New()
+	htmlGenerator := output.NewHTMLGenerator()
+
+	rcv := receiver.New(logparser.New())
+	rcv.SetSourceLimits(*idleFlag, *maxServersFlag)
+	rcv.SetOnMatch(func(match, total *logparser.ParseResult) {
+		m := match.Matches[0]
+		fmt.Printf("Матч завершён: %s %d:%d (%s)\n", m.Map, m.ScoreCT, m.ScoreT, m.ID)
+		if *outHTML == "" {
+			return
+		}
+
+		statsData := processor.Process(total)
+		statsData.HighlightedPlayer = *highlightPlayer
+		if err := htmlGenerator.Generate(*outHTML, statsData); err != nil {
+			log.Printf("ошибка записи HTML: %v", err)
+			return
+		}
+		fmt.Printf("HTML обновлён: %s\n", *outHTML)
+	})
+	defer rcv.Close()
+
+	server := &http.Server{
+		Addr:              *addrFlag,
+		Handler:           rcv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Принимаю логи на %s: POST /logs/<сервер>, табло GET /scoreboard, поток GET /events (Ctrl+C — выход)\n", *addrFlag)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// replay отправляет записанный лог path на -url
+func replay(ctx context.Context, path string) error {
+	f, err := os.Open(path) // #nosec G304 - path is controlled by user input for log replay
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if err := receiver.Replay(ctx, *urlFlag, f, receiver.ReplayOptions{Batch: *batchFlag, Delay: *delayFlag}); err != nil {
+		return err
+	}
+	fmt.Printf("Лог отправлен: %s -> %s\n", path, *urlFlag)
+	return nil
+}
@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
 
 	"oldfartscounter/internal/logparser"
 	"oldfartscounter/internal/output"
@@ -11,27 +17,71 @@ import (
 )
 
 var (
-	dirFlag         = flag.String("dir", "logs", "Папка с логами (рекурсивно)")
-	extFlag         = flag.String("ext", "", "Фильтр по расширению (например, .log). Пусто = все файлы")
+	dirFlag         = flag.String("dir", "logs", "Папка с логами (рекурсивно), если не указаны пути аргументами")
+	extFlag         = flag.String("ext", "", "Фильтр по расширению (например, .log). Пусто = все файлы. Для архивов применяется к их содержимому")
 	outCSV          = flag.String("out", "", "Сохранить CSV для матрицы убийств (опционально)")
+	outPositions    = flag.String("positions", "", "Папка для CSV с позициями убийств по картам (опционально)")
+	outRatings      = flag.String("ratings", "", "Сохранить CSV с рейтингами игроков, KAST и выживаемостью (опционально)")
 	outHTML         = flag.String("html", "cs2_stats.html", "Путь к HTML (всегда пишется)")
 	highlightPlayer = flag.String("highlight", "maslina420", "Игрок для золотой подсветки в табе 'Сорян, Братан'")
+	workersFlag     = flag.Int("workers", runtime.NumCPU(), "Сколько файлов парсить параллельно")
+	diagnosticsFlag = flag.String("diagnostics", "", "Записать диагностику парсинга в JSON файл (\"-\" — вывести в консоль)")
+	cacheFlag       = flag.String("cache", "", "Директория кэша результатов парсинга: неизменённые файлы не парсятся заново (пусто = без кэша)")
+	partialFlag     = flag.Bool("partial", false, "Сохранять матчи без Game Over (рестарт, смена карты, обрыв лога) с пометкой Partial")
+	followFlag      = flag.Bool("follow", false, "После разбора следить за самым новым логом в разобранной директории (-dir или единственный аргумент) и обновлять HTML после каждого матча (Ctrl+C — выход)")
+	teamKillsFlag   = flag.Bool("teamkills", false, "Считать тимкиллы обычными убийствами (по умолчанию они только в табе 'Позор')")
+	clutchEPIFlag   = flag.Bool("clutch-epi", false, "Клатч-бонус EPI по реальному клатчу 1vN из смертей раунда, а не по итоговому составу команд")
+	tradeWindowFlag = flag.Duration("trade-window", stats.DefaultTradeWindow, "Окно размена: убийство убийцы союзника не позже этого времени после его смерти")
+	ratingModelFlag = flag.String("rating-model", stats.DefaultRatingModel, "Модель рейтинга, выбранная в табе рейтингов: epi|adrkd|hltv2")
 )
 
 func main() {
 	flag.Parse()
+	if *followFlag && *partialFlag {
+		log.Fatal("флаги -follow и -partial несовместимы: оборванный матч активного лога задвоится")
+	}
 
 	// Создание компонентов
 	parser := logparser.New()
+	parser.SetWorkers(*workersFlag)
+	parser.SetKeepPartial(*partialFlag)
+	parser.SetCacheDir(*cacheFlag)
+	parser.SetEventClutchEPI(*clutchEPIFlag)
 	processor := stats.New()
+	processor.SetCountTeamKills(*teamKillsFlag)
+	processor.SetTradeWindow(*tradeWindowFlag)
+	if err := processor.SetRatingModel(*ratingModelFlag); err != nil {
+		log.Fatal(err)
+	}
 	csvExporter := output.NewCSVExporter()
 	htmlGenerator := output.NewHTMLGenerator()
 
+	// Источники логов: позиционные аргументы (директории, файлы, архивы) или -dir
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{*dirFlag}
+	}
+	// -follow следит за той же директорией, по которой разобрана история
+	followDir := paths[0]
+	if *followFlag {
+		if info, err := os.Stat(followDir); len(paths) != 1 || err != nil || !info.IsDir() {
+			log.Fatal("с -follow можно указать только одну директорию с логами: за ней и будет слежение")
+		}
+	}
+
 	// Парсинг логов
-	parseResult, err := parser.ParseDirectory(*dirFlag, *extFlag)
+	parseResult, err := parser.ParsePaths(paths, *extFlag)
 	if err != nil {
 		log.Fatalf("ошибка парсинга логов: %v", err)
 	}
+	printDroppedMatches(parseResult.DroppedMatches)
+
+	// Диагностика парсинга (опционально)
+	if *diagnosticsFlag != "" {
+		if err := writeDiagnostics(*diagnosticsFlag, parseResult.Diagnostics); err != nil {
+			log.Fatalf("не удалось записать диагностику: %v", err)
+		}
+	}
 
 	// Обработка статистики (всегда группируем по SteamID)
 	statsData := processor.Process(parseResult)
@@ -45,9 +95,112 @@ func main() {
 		fmt.Printf("CSV сохранён: %s\n", *outCSV)
 	}
 
+	// Экспорт рейтингов (опционально)
+	if *outRatings != "" {
+		if err := csvExporter.WriteRatings(*outRatings, statsData); err != nil {
+			log.Fatalf("не удалось записать рейтинги: %v", err)
+		}
+		fmt.Printf("Рейтинги сохранены: %s\n", *outRatings)
+	}
+
+	// Экспорт позиций убийств по картам (опционально)
+	if *outPositions != "" {
+		files, err := csvExporter.WriteKillPositions(*outPositions, statsData)
+		if err != nil {
+			log.Fatalf("не удалось записать позиции: %v", err)
+		}
+		fmt.Printf("Позиции сохранены: %d карт(ы) в %s\n", len(files), *outPositions)
+	}
+
 	// Генерация HTML
 	if err = htmlGenerator.Generate(*outHTML, statsData); err != nil {
 		log.Fatalf("ошибка записи HTML: %v", err)
 	}
 	fmt.Printf("HTML сохранён: %s\n", *outHTML)
+
+	// Слежение за активным логом сервера (опционально)
+	if *followFlag {
+		if err := followLogs(followDir, parser, processor, htmlGenerator, parseResult, statsData); err != nil {
+			log.Fatalf("ошибка слежения за логами: %v", err)
+		}
+		// Диагностика дополнена логами, прочитанными при слежении
+		if *diagnosticsFlag != "" {
+			if err := writeDiagnostics(*diagnosticsFlag, parseResult.Diagnostics); err != nil {
+				log.Fatalf("не удалось записать диагностику: %v", err)
+			}
+		}
+	}
+}
+
+// followLogs следит за самым новым логом в dir до Ctrl+C: печатает счёт после каждого раунда,
+// дописывает каждый завершённый матч в statsData (Processor.Apply) и перегенерирует HTML
+func followLogs(dir string, parser *logparser.Parser, processor *stats.Processor, htmlGenerator *output.HTMLGenerator, parseResult *logparser.ParseResult, statsData *stats.StatsData) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Printf("Слежу за логами в %s (Ctrl+C — выход)\n", dir)
+	return parser.Follow(ctx, dir, parseResult, logparser.FollowOptions{StreamHooks: logparser.StreamHooks{
+		OnRound: func(round logparser.RoundStats) {
+			fmt.Printf("%s раунд %d: CT %d — T %d\n", round.Map, round.RoundNumber, round.ScoreCT, round.ScoreT)
+		},
+		OnMatch: func(match *logparser.ParseResult) {
+			m := match.Matches[0]
+			fmt.Printf("Матч завершён: %s %d:%d (%s)\n", m.Map, m.ScoreCT, m.ScoreT, m.ID)
+
+			processor.Apply(statsData, match)
+			if err := htmlGenerator.Generate(*outHTML, statsData); err != nil {
+				log.Printf("ошибка записи HTML: %v", err)
+				return
+			}
+			fmt.Printf("HTML обновлён: %s\n", *outHTML)
+		},
+	}})
+}
+
+// printDroppedMatches выводит, сколько незавершённых матчей отброшено и почему
+func printDroppedMatches(dropped []logparser.DroppedMatch) {
+	if len(dropped) == 0 {
+		return
+	}
+
+	byReason := make(map[string]int)
+	var reasons []string
+	for _, match := range dropped {
+		if byReason[match.Reason] == 0 {
+			reasons = append(reasons, match.Reason)
+		}
+		byReason[match.Reason]++
+	}
+
+	parts := make([]string, len(reasons))
+	for i, reason := range reasons {
+		parts[i] = fmt.Sprintf("%s: %d", reason, byReason[reason])
+	}
+	fmt.Printf("Отброшено незавершённых матчей: %d (%s). Флаг -partial сохранит их\n", len(dropped), strings.Join(parts, ", "))
+}
+
+// writeDiagnostics пишет диагностику парсинга в JSON файл path или, если path == "-", в консоль.
+// В консоль дополнительно выводится сводка по всем файлам.
+func writeDiagnostics(path string, diagnostics logparser.Diagnostics) error {
+	data, err := json.MarshalIndent(diagnostics, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	totals := diagnostics.Totals()
+	summary := fmt.Sprintf("Диагностика: файлов %d, строк %d, нераспознано %d, оборванных JSON блоков %d, коротких строк игроков %d, ошибок конвертации %d\n",
+		len(diagnostics.Files), totals.Lines, totals.Unmatched, totals.TruncatedJSON, totals.ShortPlayerRows, totals.ConversionErrors)
+
+	if path == "-" {
+		fmt.Println(string(data))
+		fmt.Print(summary)
+		return nil
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	fmt.Print(summary)
+	fmt.Printf("Диагностика сохранена: %s\n", path)
+	return nil
 }
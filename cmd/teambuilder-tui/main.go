@@ -1,10 +1,12 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"oldfartscounter/internal/environment"
 	"oldfartscounter/internal/notifier"
+	"oldfartscounter/internal/stats"
 	"oldfartscounter/internal/teambuilder"
 	"oldfartscounter/internal/telegram"
 	"oldfartscounter/internal/tui"
@@ -15,9 +17,22 @@ import (
 
 var SorryBro string
 
+var (
+	logsFlag  = flag.String("logs", "", "Считать рейтинги игроков по логам (директория, файл или архив) вместо встроенного списка")
+	modelFlag = flag.String("model", stats.DefaultRatingModel, "Модель рейтинга для -logs: epi|adrkd|hltv2")
+)
+
 func main() {
-	// Создаем репозиторий игроков
+	flag.Parse()
+
+	// Создаем репозиторий игроков: встроенный список или рейтинги выбранной модели по логам
 	repo := teambuilder.NewPlayerRepository()
+	if *logsFlag != "" {
+		var err error
+		if repo, err = teambuilder.LoadRatingsRepository([]string{*logsFlag}, *modelFlag); err != nil {
+			log.Fatalf("Не удалось посчитать рейтинги по логам: %v", err)
+		}
+	}
 
 	// Создаем notifier для Telegram
 	telegramFormatter := telegram.NewTeamTableFormatter()
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"log"
 	"oldfartscounter/internal/notifier"
+	"oldfartscounter/internal/stats"
 	"oldfartscounter/internal/teambuilder"
 	"oldfartscounter/internal/telegram"
 	"os"
@@ -13,6 +14,11 @@ import (
 
 var SorryBro = ""
 
+var (
+	logsFlag  = flag.String("logs", "", "Считать рейтинги игроков по логам (директория, файл или архив) вместо встроенного списка")
+	modelFlag = flag.String("model", stats.DefaultRatingModel, "Модель рейтинга для -logs: epi|adrkd|hltv2")
+)
+
 func main() {
 	c := config()
 	f := telegram.NewTeamTableFormatter()
@@ -21,6 +27,12 @@ func main() {
 		// telegram.NewNotifier(apiHandler(), f),
 	}
 	repo := teambuilder.NewPlayerRepository()
+	if *logsFlag != "" {
+		var err error
+		if repo, err = teambuilder.LoadRatingsRepository([]string{*logsFlag}, *modelFlag); err != nil {
+			log.Fatalf("Failed to load ratings from logs: %v", err)
+		}
+	}
 	teamBuilder := teambuilder.NewTeamBuilder(repo)
 
 	teams := teamBuilder.Build(c)
@@ -0,0 +1,179 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"oldfartscounter/internal/logparser"
+	"oldfartscounter/internal/stats"
+)
+
+// BombTabComponent отвечает за таб "Бомба"
+type BombTabComponent struct{}
+
+// NewBombTab создает новый компонент таба бомбы
+func NewBombTab() *BombTabComponent {
+	return &BombTabComponent{}
+}
+
+// winConditionTitles — подписи условий победы в порядке отображения
+var winConditionTitles = []struct {
+	Key   string
+	Title string
+}{
+	{logparser.WinElimination, "Все убиты"},
+	{logparser.WinBombExploded, "Взрыв"},
+	{logparser.WinBombDefused, "Дефьюз"},
+	{logparser.WinTimeRanOut, "Время вышло"},
+	{logparser.WinHostageRescued, "Заложники"},
+}
+
+// GenerateHTML генерирует HTML для таба бомбы
+func (b *BombTabComponent) GenerateHTML(data *stats.StatsData) string {
+	return `
+<!-- BOMB -->
+<div id="tab-bomb" class="view">
+  <!-- Закладки и исходы раундов по картам -->
+  <h3 style="color:var(--accent);font-size:18px;margin:0 0 0;">💣 Карты: куда ставят и как выигрывают</h3>
+  <div class="toolbar">
+    <input id="qBombMaps" type="search" placeholder="Поиск по картам…">
+    <label class="small"><input id="heatBombMaps" type="checkbox" checked> Heatmap</label>
+  </div>
+  <div class="table-wrap"><table id="gridBombMaps"><thead></thead><tbody></tbody></table></div>
+  <div class="small" style="margin-top:6px">A % и B % — доля закладок на каждый плент. "После закладки" — доля раундов с закладкой, которые выиграли T. Последние столбцы — сколько раундов выиграно каждым способом.</div>
+
+  <!-- Закладчики -->
+  <h3 style="color:var(--accent);font-size:18px;margin:30px 0 0;">🧨 Закладчики</h3>
+  <div class="toolbar">
+    <input id="qBombPlanters" type="search" placeholder="Поиск по именам…">
+    <label class="small"><input id="heatBombPlanters" type="checkbox" checked> Heatmap</label>
+  </div>
+  <div class="table-wrap"><table id="gridBombPlanters"><thead></thead><tbody></tbody></table></div>
+  <div class="small" style="margin-top:6px">"Побед после закладки" — раунды, которые T выиграли после закладки этого игрока.</div>
+</div>`
+}
+
+// GenerateJS генерирует JavaScript для таба бомбы
+func (b *BombTabComponent) GenerateJS(data *stats.StatsData) string {
+	type PlayerMapping struct {
+		Title string
+		Key   string
+	}
+	playerMappings := make([]PlayerMapping, len(data.Players))
+	for i, p := range data.Players {
+		playerMappings[i] = PlayerMapping{Title: p.Title, Key: p.Key}
+	}
+
+	jPlayerMappings, _ := json.Marshal(playerMappings)
+	jWinConditions, _ := json.Marshal(winConditionTitles)
+
+	return fmt.Sprintf(`
+// Init: Бомба
+window.bombTabState = (function() {
+  const playerMappings = %s;
+  const playerTitles = playerMappings.map(p => p.Title);
+  const winConditions = %s;
+
+  const playerIndexMap = {};
+  playerMappings.forEach((p, idx) => {
+    playerIndexMap[p.Title] = idx;
+    playerIndexMap[p.Key] = idx;
+  });
+
+  const pct = (part, total) => total ? Math.round(part * 100 / total) : 0;
+
+  function renderMapsTable(rounds) {
+    const maps = {};
+    const mapOf = name => {
+      if (!maps[name]) maps[name] = {rounds: 0, a: 0, b: 0, postPlantWins: 0, wins: {}};
+      return maps[name];
+    };
+
+    rounds.forEach(r => {
+      [mapOf(r.Map || "—"), mapOf("Все карты")].forEach(m => {
+        m.rounds++;
+        if (r.WinCondition) m.wins[r.WinCondition] = (m.wins[r.WinCondition] || 0) + 1;
+        if (!r.BombSite) return;
+        if (r.BombSite === "A") m.a++;
+        if (r.BombSite === "B") m.b++;
+        if (r.Winner === 2) m.postPlantWins++;
+      });
+    });
+
+    const names = Object.keys(maps).sort();
+    const stats = names.map(n => maps[n]);
+
+    renderColumnTable({
+      rootId: "#gridBombMaps",
+      players: names,
+      rowTitle: "Карта",
+      columns: [
+        {title: "Раунды", data: stats.map(m => m.rounds)},
+        {title: "Закладок", data: stats.map(m => m.a + m.b)},
+        {title: "A %%", data: stats.map(m => pct(m.a, m.a + m.b))},
+        {title: "B %%", data: stats.map(m => pct(m.b, m.a + m.b))},
+        {title: "После закладки %%", data: stats.map(m => pct(m.postPlantWins, m.a + m.b))},
+        ...winConditions.map(c => ({title: c.Title, data: stats.map(m => m.wins[c.Key] || 0)}))
+      ],
+      qInputId: "qBombMaps",
+      heatToggleId: "heatBombMaps"
+    });
+  }
+
+  function renderPlantersTable(rounds, events) {
+    const n = playerMappings.length;
+    const plants = Array(n).fill(0);
+    const plantsA = Array(n).fill(0);
+    const plantsB = Array(n).fill(0);
+    const postPlantWins = Array(n).fill(0);
+    const drops = Array(n).fill(0);
+
+    rounds.forEach(r => {
+      if (!r.BombSite) return;
+      const idx = playerIndexMap[r.PlanterSID];
+      if (idx === undefined) return;
+      plants[idx]++;
+      if (r.BombSite === "A") plantsA[idx]++;
+      if (r.BombSite === "B") plantsB[idx]++;
+      if (r.Winner === 2) postPlantWins[idx]++;
+    });
+    events.forEach(e => {
+      if (e.EventType !== "drop") return;
+      let idx = playerIndexMap[e.PlayerName];
+      if (idx === undefined) idx = playerIndexMap[e.PlayerSID];
+      if (idx !== undefined) drops[idx]++;
+    });
+
+    renderColumnTable({
+      rootId: "#gridBombPlanters",
+      players: playerTitles,
+      columns: [
+        {title: "Закладок", data: plants},
+        {title: "На A", data: plantsA},
+        {title: "На B", data: plantsB},
+        {title: "Побед после закладки", data: postPlantWins},
+        {title: "Побед после закладки %%", data: plants.map((p, i) => pct(postPlantWins[i], p))},
+        {title: "Выбросил бомбу", data: drops}
+      ],
+      qInputId: "qBombPlanters",
+      heatToggleId: "heatBombPlanters"
+    });
+  }
+
+  function renderBombTab() {
+    const rounds = window.filteredRoundStats || [];
+    renderMapsTable(rounds);
+    renderPlantersTable(rounds, window.filteredBombEvents || []);
+  }
+
+  // Переотрисовка при изменении фильтра дат
+  window.addEventListener('dateFilterChanged', renderBombTab);
+
+  return { render: renderBombTab };
+})();
+
+// Начальная отрисовка
+window.bombTabState.render();`,
+		string(jPlayerMappings),
+		string(jWinConditions))
+}
@@ -0,0 +1,105 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"oldfartscounter/internal/logparser"
+	"oldfartscounter/internal/stats"
+)
+
+// ClutchesTabComponent отвечает за таб "Клатчи": ситуации 1v1…1v5 и победы в них
+type ClutchesTabComponent struct{}
+
+// NewClutchesTab создает новый компонент таба клатчей
+func NewClutchesTab() *ClutchesTabComponent {
+	return &ClutchesTabComponent{}
+}
+
+// GenerateHTML генерирует HTML для таба клатчей
+func (c *ClutchesTabComponent) GenerateHTML(data *stats.StatsData) string {
+	return `
+<!-- CLUTCHES -->
+<div id="tab-clutches" class="view">
+  <h3 style="color:var(--accent);font-size:18px;margin:0 0 0;">🥶 Клатчи</h3>
+  <div class="toolbar">
+    <input id="qClutches" type="search" placeholder="Поиск по именам…">
+    <label class="small"><input id="heatClutches" type="checkbox" checked> Heatmap</label>
+  </div>
+  <div class="table-wrap"><table id="gridClutches"><thead></thead><tbody></tbody></table></div>
+  <div class="small" style="margin-top:6px">Клатч — игрок остался последним живым в команде против N противников (смерти раунда проигрываются по порядку). Выигран — команда взяла раунд: убийствами, дефьюзом или по времени.</div>
+</div>`
+}
+
+// GenerateJS генерирует JavaScript для таба клатчей
+func (c *ClutchesTabComponent) GenerateJS(data *stats.StatsData) string {
+	type PlayerMapping struct {
+		Title string
+		Key   string
+	}
+	playerMappings := make([]PlayerMapping, len(data.Players))
+	for i, p := range data.Players {
+		playerMappings[i] = PlayerMapping{Title: p.Title, Key: p.Key}
+	}
+
+	jPlayerMappings, _ := json.Marshal(playerMappings)
+
+	return fmt.Sprintf(`
+// Init: Клатчи
+window.clutchesTabState = (function() {
+  const playerMappings = %s;
+  const playerTitles = playerMappings.map(p => p.Title);
+  const MAX_ENEMIES = %d;
+
+  const playerIndexMap = {};
+  playerMappings.forEach((p, idx) => {
+    playerIndexMap[p.Key] = idx;
+  });
+
+  function renderClutchesTab() {
+    const n = playerMappings.length;
+    const attempts = Array.from({length: MAX_ENEMIES}, () => Array(n).fill(0));
+    const wins = Array.from({length: MAX_ENEMIES}, () => Array(n).fill(0));
+
+    (window.filteredRoundStats || []).forEach(r => {
+      (r.Players || []).forEach(ps => {
+        if (!(ps.ClutchVs >= 1 && ps.ClutchVs <= MAX_ENEMIES)) return;
+        const idx = playerIndexMap["[U:1:" + ps.AccountID + "]"];
+        if (idx === undefined) return;
+        attempts[ps.ClutchVs - 1][idx]++;
+        if (r.Winner === ps.Team) wins[ps.ClutchVs - 1][idx]++;
+      });
+    });
+
+    const totalAttempts = Array(n).fill(0).map((_, i) => attempts.reduce((s, a) => s + a[i], 0));
+    const totalWins = Array(n).fill(0).map((_, i) => wins.reduce((s, w) => s + w[i], 0));
+    const columns = [
+      {title: "Клатчей", data: totalAttempts},
+      {title: "Выиграно", data: totalWins},
+      {title: "Win%%", data: totalAttempts.map((a, i) => a ? Math.round(totalWins[i] * 100 / a) : 0)}
+    ];
+    for (let k = 0; k < MAX_ENEMIES; k++) {
+      columns.push({title: "1v" + (k + 1), data: attempts[k]});
+      columns.push({title: "1v" + (k + 1) + " ✓", data: wins[k]});
+    }
+
+    renderColumnTable({
+      rootId: "#gridClutches",
+      players: playerTitles,
+      columns: columns,
+      qInputId: "qClutches",
+      heatToggleId: "heatClutches"
+    });
+  }
+
+  // Переотрисовка при изменении фильтра дат
+  window.addEventListener('dateFilterChanged', renderClutchesTab);
+
+  return { render: renderClutchesTab };
+})();
+
+// Начальная отрисовка
+window.clutchesTabState.render();`,
+		string(jPlayerMappings),
+		logparser.MaxClutchEnemies)
+}
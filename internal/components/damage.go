@@ -0,0 +1,273 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"oldfartscounter/internal/stats"
+)
+
+// DamageTabComponent отвечает за таб "Урон"
+type DamageTabComponent struct{}
+
+// NewDamageTab создает новый компонент таба урона
+func NewDamageTab() *DamageTabComponent {
+	return &DamageTabComponent{}
+}
+
+// GenerateHTML генерирует HTML для таба урона
+func (d *DamageTabComponent) GenerateHTML(data *stats.StatsData) string {
+	return fmt.Sprintf(`
+<!-- DAMAGE -->
+<div id="tab-damage" class="view">
+  <!-- Нанесённый и полученный урон по игрокам -->
+  <h3 style="color:var(--accent);font-size:18px;margin:0 0 0;">💥 Нанесено и получено</h3>
+  <div class="toolbar">
+    <input id="qDamageTotals" type="search" placeholder="Поиск по именам…">
+    <label class="small"><input id="heatDamageTotals" type="checkbox" checked> Heatmap</label>
+  </div>
+  <div class="table-wrap"><table id="gridDamageTotals"><thead></thead><tbody></tbody></table></div>
+  <div class="small" style="margin-top:6px">Учитывается только снятое здоровье: лишний урон по добитому игроку не считается. "Без убийства" — урон по жертвам, которых в том же раунде добил кто-то другой или не добил никто. "По своим" — урон по союзникам и себе, в остальные столбцы и матрицы он не входит. Части тела — доля попаданий.</div>
+
+  <!-- Матрица урона -->
+  <h3 style="color:var(--accent);font-size:18px;margin:30px 0 0;">🩸 Кто кому сколько снял</h3>
+  <div class="toolbar">
+    <input id="qDamage" type="search" placeholder="Поиск по именам…">
+    <label class="small"><input id="heatDamage" type="checkbox" checked> Heatmap</label>
+    <div class="legend"><div class="swatch"></div><span class="small" id="legendDamage">0 → %d</span></div>
+  </div>
+  <div class="table-wrap"><table id="gridDamage"><thead></thead><tbody></tbody></table></div>
+
+  <!-- Урон по оружию -->
+  <h3 style="color:var(--accent);font-size:18px;margin:30px 0 0;">🔫 Чем наносят урон</h3>
+  <div class="toolbar">
+    <input id="qDamageWeapons" type="search" placeholder="Поиск…">
+    <label class="small"><input id="heatDamageWeapons" type="checkbox" checked> Heatmap</label>
+  </div>
+  <div class="table-wrap"><table id="gridDamageWeapons"><thead></thead><tbody></tbody></table></div>
+
+  <!-- Гранаты -->
+  <h3 style="color:var(--accent);font-size:18px;margin:30px 0 0;">🧨 Гранаты</h3>
+  <div class="toolbar">
+    <input id="qUtility" type="search" placeholder="Поиск по именам…">
+    <label class="small"><input id="heatUtility" type="checkbox" checked> Heatmap</label>
+  </div>
+  <div class="table-wrap"><table id="gridUtility"><thead></thead><tbody></tbody></table></div>
+  <div class="small" style="margin-top:6px">Молотовы включают зажигательные гранаты, их урон — урон от огня. "Урон на гранату" — урон HE и молотовых, делённый на их броски. UD — урон гранатами из статистики раундов сервера, для сверки.</div>
+</div>`,
+		data.DamageData.Max)
+}
+
+// GenerateJS генерирует JavaScript для таба урона
+func (d *DamageTabComponent) GenerateJS(data *stats.StatsData) string {
+	type PlayerMapping struct {
+		Title string
+		Key   string
+	}
+	playerMappings := make([]PlayerMapping, len(data.Players))
+	for i, p := range data.Players {
+		playerMappings[i] = PlayerMapping{Title: p.Title, Key: p.Key}
+	}
+
+	jPlayerMappings, _ := json.Marshal(playerMappings)
+
+	return fmt.Sprintf(`
+// Init: Урон
+window.damageTabState = (function() {
+  const playerMappings = %s;
+  const playerTitles = playerMappings.map(p => p.Title);
+
+  const playerIndexMap = {};
+  playerMappings.forEach((p, idx) => {
+    playerIndexMap[p.Title] = idx;
+    playerIndexMap[p.Key] = idx;
+  });
+
+  function indexOf(name, sid) {
+    const idx = playerIndexMap[name];
+    return idx === undefined ? playerIndexMap[sid] : idx;
+  }
+
+  // Основные части тела в порядке отображения
+  const HITGROUPS = [
+    {title: "Голова %%", groups: ["head"]},
+    {title: "Торс %%", groups: ["chest", "stomach", "neck"]},
+    {title: "Руки %%", groups: ["left arm", "right arm"]},
+    {title: "Ноги %%", groups: ["left leg", "right leg"]}
+  ];
+
+  function recalcDamage(events) {
+    const n = playerMappings.length;
+    const matrix = Array(n).fill(0).map(() => Array(n).fill(0));
+    const given = Array(n).fill(0);
+    const received = Array(n).fill(0);
+    const withoutKill = Array(n).fill(0);
+    const teamDamage = Array(n).fill(0);
+    const hits = Array(n).fill(0);
+    const hitgroupHits = HITGROUPS.map(() => Array(n).fill(0));
+    const weaponDamage = {};
+    let maxDamage = 0;
+
+    events.forEach(e => {
+      const aIdx = indexOf(e.AttackerName, e.AttackerSID);
+      const vIdx = indexOf(e.VictimName, e.VictimSID);
+
+      // Урон по союзникам и себе считается отдельно
+      if (e.Friendly) {
+        if (aIdx !== undefined) teamDamage[aIdx] += e.HealthDamage;
+        return;
+      }
+      if (aIdx !== undefined) {
+        given[aIdx] += e.HealthDamage;
+        hits[aIdx]++;
+        HITGROUPS.forEach((h, g) => { if (h.groups.includes(e.Hitgroup)) hitgroupHits[g][aIdx]++; });
+        if (!weaponDamage[e.Weapon]) weaponDamage[e.Weapon] = Array(n).fill(0);
+        weaponDamage[e.Weapon][aIdx] += e.HealthDamage;
+      }
+      if (vIdx !== undefined) received[vIdx] += e.HealthDamage;
+      if (aIdx !== undefined && vIdx !== undefined) {
+        matrix[aIdx][vIdx] += e.HealthDamage;
+        if (matrix[aIdx][vIdx] > maxDamage) maxDamage = matrix[aIdx][vIdx];
+      }
+    });
+
+    // Урон без убийства: раунд попадания (RoundGroup) размечен в stats.markDamageHits
+    const damage = {};
+    const killed = {};
+    events.forEach(e => {
+      if (e.Friendly) return;
+      const key = e.RoundGroup + "|" + e.AttackerSID + "|" + e.VictimSID;
+      damage[key] = (damage[key] || 0) + e.HealthDamage;
+      if (e.Health === 0) killed[key] = true;
+    });
+    events.forEach(e => {
+      const key = e.RoundGroup + "|" + e.AttackerSID + "|" + e.VictimSID;
+      if (e.Friendly || damage[key] === undefined || killed[key]) return;
+      const aIdx = indexOf(e.AttackerName, e.AttackerSID);
+      if (aIdx !== undefined) withoutKill[aIdx] += damage[key];
+      delete damage[key];
+    });
+
+    return { matrix, given, received, withoutKill, teamDamage, hits, hitgroupHits, weaponDamage, maxDamage: maxDamage || 1 };
+  }
+
+  function renderDamageTab() {
+    const r = recalcDamage(window.filteredDamageEvents || []);
+    const pct = (part, total) => part.map((v, i) => total[i] ? Math.round(v * 100 / total[i]) : 0);
+
+    renderColumnTable({
+      rootId: "#gridDamageTotals",
+      players: playerTitles,
+      columns: [
+        {title: "Нанесено", data: r.given},
+        {title: "Получено", data: r.received},
+        {title: "Без убийства", data: r.withoutKill},
+        {title: "Без убийства %%", data: pct(r.withoutKill, r.given)},
+        {title: "По своим", data: r.teamDamage},
+        {title: "Попаданий", data: r.hits},
+        ...HITGROUPS.map((h, g) => ({title: h.title, data: pct(r.hitgroupHits[g], r.hits)}))
+      ],
+      qInputId: "qDamageTotals",
+      heatToggleId: "heatDamageTotals"
+    });
+
+    const legendEl = document.getElementById('legendDamage');
+    if (legendEl) legendEl.textContent = '0 → ' + r.maxDamage;
+
+    renderMatrix({
+      rootId: "#gridDamage",
+      rowLabels: playerTitles,
+      colLabels: playerTitles,
+      data: r.matrix,
+      maxVal: r.maxDamage,
+      qInputId: "qDamage",
+      heatToggleId: "heatDamage",
+      cornerTitle: "Урон — Атакующие ↓ / Жертвы →"
+    });
+
+    const weapons = Object.keys(r.weaponDamage).sort();
+    const weaponMatrix = playerTitles.map((_, i) => weapons.map(w => r.weaponDamage[w][i]));
+    const maxWeapon = Math.max(1, ...weaponMatrix.map(row => Math.max(0, ...row)));
+    renderMatrix({
+      rootId: "#gridDamageWeapons",
+      rowLabels: playerTitles,
+      colLabels: weapons,
+      data: weaponMatrix,
+      maxVal: maxWeapon,
+      qInputId: "qDamageWeapons",
+      heatToggleId: "heatDamageWeapons",
+      cornerTitle: "Игроки ↓ / Оружие →"
+    });
+
+    renderUtilityTable(r);
+  }
+
+  function renderUtilityTable(r) {
+    const n = playerMappings.length;
+    const throws = {};
+    const damage = {};
+    ["hegrenade", "molotov", "flashbang", "smokegrenade", "decoy"].forEach(g => {
+      throws[g] = Array(n).fill(0);
+      damage[g] = Array(n).fill(0);
+    });
+    const totalThrows = Array(n).fill(0);
+    const rounds = Array(n).fill(0);
+    const reportedUD = Array(n).fill(0);
+
+    (window.filteredThrowEvents || []).forEach(e => {
+      const g = utilityGrenade(e.Grenade);
+      const idx = indexOf(e.ThrowerName, e.ThrowerSID);
+      if (idx === undefined || !throws[g]) return;
+      throws[g][idx]++;
+      totalThrows[idx]++;
+    });
+    (window.filteredDamageEvents || []).forEach(e => {
+      if (e.Friendly) return;
+      const g = utilityGrenade(e.Weapon);
+      const idx = indexOf(e.AttackerName, e.AttackerSID);
+      if (idx !== undefined && damage[g]) damage[g][idx] += e.HealthDamage;
+    });
+    (window.filteredRoundStats || []).forEach(round => {
+      (round.Players || []).forEach(ps => {
+        const idx = playerIndexMap["[U:1:" + ps.AccountID + "]"];
+        if (idx === undefined) return;
+        rounds[idx]++;
+        reportedUD[idx] += ps.UD || 0;
+      });
+    });
+
+    const round2 = v => Math.round(v * 100) / 100;
+    const nadeDamage = totalThrows.map((_, i) => damage.hegrenade[i] + damage.molotov[i]);
+    const nadeThrows = totalThrows.map((_, i) => throws.hegrenade[i] + throws.molotov[i]);
+
+    renderColumnTable({
+      rootId: "#gridUtility",
+      players: playerTitles,
+      columns: [
+        {title: "Бросков", data: totalThrows},
+        {title: "Бросков за раунд", data: totalThrows.map((t, i) => rounds[i] ? round2(t / rounds[i]) : 0)},
+        {title: "HE", data: throws.hegrenade},
+        {title: "Урон HE", data: damage.hegrenade},
+        {title: "Молотовы", data: throws.molotov},
+        {title: "Урон огнём", data: damage.molotov},
+        {title: "Урон на гранату", data: nadeDamage.map((d, i) => nadeThrows[i] ? round2(d / nadeThrows[i]) : 0)},
+        {title: "Флешки", data: throws.flashbang},
+        {title: "Смоки", data: throws.smokegrenade},
+        {title: "Декои", data: throws.decoy},
+        {title: "UD", data: reportedUD}
+      ],
+      qInputId: "qUtility",
+      heatToggleId: "heatUtility"
+    });
+  }
+
+  // Переотрисовка при изменении фильтра дат
+  window.addEventListener('dateFilterChanged', renderDamageTab);
+
+  return { render: renderDamageTab };
+})();
+
+// Начальная отрисовка
+window.damageTabState.render();`,
+		string(jPlayerMappings))
+}
@@ -0,0 +1,178 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"oldfartscounter/internal/stats"
+)
+
+// EconomyTabComponent отвечает за таб "Экономика"
+type EconomyTabComponent struct{}
+
+// NewEconomyTab создает новый компонент таба экономики
+func NewEconomyTab() *EconomyTabComponent {
+	return &EconomyTabComponent{}
+}
+
+// buyTypeTitles — подписи типов закупки
+var buyTypeTitles = map[string]string{
+	stats.BuyEco:   "Эко",
+	stats.BuyForce: "Форс",
+	stats.BuyHalf:  "Полузакупка",
+	stats.BuyFull:  "Фулл",
+}
+
+// GenerateHTML генерирует HTML для таба экономики
+func (e *EconomyTabComponent) GenerateHTML(data *stats.StatsData) string {
+	return fmt.Sprintf(`
+<!-- ECONOMY -->
+<div id="tab-economy" class="view">
+  <!-- Винрейт по типу закупки команды -->
+  <h3 style="color:var(--accent);font-size:18px;margin:0 0 0;">💰 Закупки команд</h3>
+  <div class="toolbar">
+    <input id="qEconomyBuys" type="search" placeholder="Поиск…">
+    <label class="small"><input id="heatEconomyBuys" type="checkbox" checked> Heatmap</label>
+  </div>
+  <div class="table-wrap"><table id="gridEconomyBuys"><thead></thead><tbody></tbody></table></div>
+  <div class="small" style="margin-top:6px">Стоимость снаряжения = покупки в раунде + сохранённое с прошлого раунда оружие, в среднем на игрока команды. Эко — до $%d, фулл — от $%d. Между ними: форс, если после закупки осталось меньше $%d, иначе полузакупка.</div>
+
+  <!-- Экономика игроков -->
+  <h3 style="color:var(--accent);font-size:18px;margin:30px 0 0;">🛒 Кто форсит в поражения</h3>
+  <div class="toolbar">
+    <input id="qEconomyPlayers" type="search" placeholder="Поиск по именам…">
+    <label class="small"><input id="heatEconomyPlayers" type="checkbox" checked> Heatmap</label>
+  </div>
+  <div class="table-wrap"><table id="gridEconomyPlayers"><thead></thead><tbody></tbody></table></div>
+  <div class="small" style="margin-top:6px">Тип закупки игрока считается по его собственному снаряжению и остатку денег. Сейв — проигранный раунд, в котором игрок выжил. "Сохранено $" — стоимость оружия, перенесённого в следующие раунды. Учитываются только раунды из логов со строками покупок.</div>
+</div>`,
+		stats.EcoMaxValue, stats.FullBuyMinValue, stats.ForceMaxLeftover)
+}
+
+// GenerateJS генерирует JavaScript для таба экономики
+func (e *EconomyTabComponent) GenerateJS(data *stats.StatsData) string {
+	type PlayerMapping struct {
+		Title string
+		Key   string
+	}
+	playerMappings := make([]PlayerMapping, len(data.Players))
+	for i, p := range data.Players {
+		playerMappings[i] = PlayerMapping{Title: p.Title, Key: p.Key}
+	}
+
+	buyTitles := make([]string, len(stats.BuyTypes))
+	for i, buy := range stats.BuyTypes {
+		buyTitles[i] = buyTypeTitles[buy]
+	}
+
+	jPlayerMappings, _ := json.Marshal(playerMappings)
+	jBuyTypes, _ := json.Marshal(stats.BuyTypes)
+	jBuyTitles, _ := json.Marshal(buyTitles)
+
+	return fmt.Sprintf(`
+// Init: Экономика
+window.economyTabState = (function() {
+  const playerMappings = %s;
+  const playerTitles = playerMappings.map(p => p.Title);
+  const buyTypes = %s;
+  const buyTitles = %s;
+  const ECO_MAX_VALUE = %d, FULL_BUY_MIN_VALUE = %d, FORCE_MAX_LEFTOVER = %d;
+
+  const playerIndexMap = {};
+  playerMappings.forEach((p, idx) => {
+    playerIndexMap[p.Title] = idx;
+    playerIndexMap[p.Key] = idx;
+  });
+
+  // Повторяет stats.ClassifyBuy
+  function classifyBuy(value, leftover) {
+    if (value < ECO_MAX_VALUE) return 0;
+    if (value >= FULL_BUY_MIN_VALUE) return 3;
+    return leftover < FORCE_MAX_LEFTOVER ? 1 : 2;
+  }
+
+  const pct = (part, total) => total ? Math.round(part * 100 / total) : 0;
+
+  function renderEconomyTab() {
+    const rounds = (window.filteredRoundStats || []).filter(r =>
+      (r.Players || []).some(ps => ps.Spent > 0 || ps.CarriedValue > 0));
+
+    const n = playerMappings.length;
+    const teamRounds = Array(buyTypes.length).fill(0);
+    const teamWins = Array(buyTypes.length).fill(0);
+    const spend = Array(n).fill(0);
+    const played = Array(n).fill(0);
+    const playerRounds = buyTypes.map(() => Array(n).fill(0));
+    const playerWins = buyTypes.map(() => Array(n).fill(0));
+    const forceLosses = Array(n).fill(0);
+    const saves = Array(n).fill(0);
+    const savedValue = Array(n).fill(0);
+
+    rounds.forEach(r => {
+      [2, 3].forEach(team => {
+        const members = r.Players.filter(ps => ps.Team === team);
+        if (!members.length) return;
+        const value = members.reduce((s, ps) => s + ps.Spent + ps.CarriedValue, 0) / members.length;
+        const leftover = members.reduce((s, ps) => s + ps.Money, 0) / members.length;
+        const b = classifyBuy(value, leftover);
+        teamRounds[b]++;
+        if (r.Winner === team) teamWins[b]++;
+      });
+
+      r.Players.forEach(ps => {
+        const idx = playerIndexMap["[U:1:" + ps.AccountID + "]"];
+        if (idx === undefined) return;
+        const b = classifyBuy(ps.Spent + ps.CarriedValue, ps.Money);
+        const won = r.Winner === ps.Team;
+        spend[idx] += ps.Spent;
+        played[idx]++;
+        savedValue[idx] += ps.CarriedValue;
+        playerRounds[b][idx]++;
+        if (won) playerWins[b][idx]++;
+        if (b === 1 && !won && r.Winner) forceLosses[idx]++;
+        if (ps.Survived && !won && r.Winner) saves[idx]++;
+      });
+    });
+
+    renderColumnTable({
+      rootId: "#gridEconomyBuys",
+      players: buyTitles,
+      rowTitle: "Закупка",
+      columns: [
+        {title: "Раунды", data: teamRounds},
+        {title: "Победы", data: teamWins},
+        {title: "Win %%", data: teamRounds.map((r, b) => pct(teamWins[b], r))}
+      ],
+      qInputId: "qEconomyBuys",
+      heatToggleId: "heatEconomyBuys"
+    });
+
+    renderColumnTable({
+      rootId: "#gridEconomyPlayers",
+      players: playerTitles,
+      columns: [
+        {title: "Форс-поражения", data: forceLosses},
+        {title: "Ср. трата $", data: spend.map((s, i) => played[i] ? Math.round(s / played[i]) : 0)},
+        ...buyTitles.map((title, b) => ({title: title, data: playerRounds[b]})),
+        {title: "Win %% на форсе", data: playerRounds[1].map((r, i) => pct(playerWins[1][i], r))},
+        {title: "Сейвы", data: saves},
+        {title: "Сохранено $", data: savedValue}
+      ],
+      qInputId: "qEconomyPlayers",
+      heatToggleId: "heatEconomyPlayers"
+    });
+  }
+
+  // Переотрисовка при изменении фильтра дат
+  window.addEventListener('dateFilterChanged', renderEconomyTab);
+
+  return { render: renderEconomyTab };
+})();
+
+// Начальная отрисовка
+window.economyTabState.render();`,
+		string(jPlayerMappings),
+		string(jBuyTypes),
+		string(jBuyTitles),
+		stats.EcoMaxValue, stats.FullBuyMinValue, stats.ForceMaxLeftover)
+}
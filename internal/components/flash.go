@@ -28,6 +28,15 @@ func (f *FlashTabComponent) GenerateHTML(data *stats.StatsData) string {
   </div>
   <div class="table-wrap"><table id="gridFlash"><thead></thead><tbody></tbody></table></div>
   <div class="small" style="margin-top:6px">Индекс Пирога: сколько секунд кто кого слепил. Клик по столбцам сортирует строки, клик по строкам сортирует столбцы.</div>
+
+  <!-- Эффективность флешек -->
+  <h3 style="color:var(--accent);font-size:18px;margin:30px 0 0;">💡 Брошено флешек и ослеплено врагов</h3>
+  <div class="toolbar">
+    <input id="qFlashEff" type="search" placeholder="Поиск по именам…">
+    <label class="small"><input id="heatFlashEff" type="checkbox" checked> Heatmap</label>
+  </div>
+  <div class="table-wrap"><table id="gridFlashEff"><thead></thead><tbody></tbody></table></div>
+  <div class="small" style="margin-top:6px">"Врагов на флешку" — ослеплённые противники, делённые на брошенные флешки. Своими считаются союзники и сам флешер.</div>
 </div>`
 }
 
@@ -96,6 +105,41 @@ window.flashTabState = (function() {
       cornerTitle: "Флешеры ↓ / Жертвы → (секунды)",
       numFmt: (v) => (typeof v === "number" ? v.toFixed(2) : String(v))
     });
+
+    renderFlashEfficiency();
+  }
+
+  function renderFlashEfficiency() {
+    const n = playerMappings.length;
+    const thrown = Array(n).fill(0);
+    const enemies = Array(n).fill(0);
+    const team = Array(n).fill(0);
+
+    (window.filteredThrowEvents || []).forEach(e => {
+      if (e.Grenade !== "flashbang") return;
+      let idx = playerIndexMap[e.ThrowerName];
+      if (idx === undefined) idx = playerIndexMap[e.ThrowerSID];
+      if (idx !== undefined) thrown[idx]++;
+    });
+    (window.filteredFlashEvents || []).forEach(e => {
+      let idx = playerIndexMap[e.FlasherName];
+      if (idx === undefined) idx = playerIndexMap[e.FlasherSID];
+      if (idx === undefined) return;
+      if (e.TeamFlash) team[idx]++; else enemies[idx]++;
+    });
+
+    renderColumnTable({
+      rootId: "#gridFlashEff",
+      players: playerTitles,
+      columns: [
+        {title: "Брошено", data: thrown},
+        {title: "Врагов ослеплено", data: enemies},
+        {title: "Своих ослеплено", data: team},
+        {title: "Врагов на флешку", data: thrown.map((t, i) => t ? Math.round(enemies[i] * 100 / t) / 100 : 0)}
+      ],
+      qInputId: "qFlashEff",
+      heatToggleId: "heatFlashEff"
+    });
   }
 
   window.addEventListener('dateFilterChanged', renderFlashTab);
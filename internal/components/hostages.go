@@ -0,0 +1,113 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"oldfartscounter/internal/logparser"
+	"oldfartscounter/internal/stats"
+)
+
+// HostagesTabComponent отвечает за таб "Заложники"
+type HostagesTabComponent struct{}
+
+// NewHostagesTab создает новый компонент таба заложников
+func NewHostagesTab() *HostagesTabComponent {
+	return &HostagesTabComponent{}
+}
+
+// GenerateHTML генерирует HTML для таба заложников
+func (h *HostagesTabComponent) GenerateHTML(data *stats.StatsData) string {
+	return `
+<!-- HOSTAGES -->
+<div id="tab-hostages" class="view">
+  <h3 style="color:var(--accent);font-size:18px;margin:0 0 0;">🏢 Офисные вечера: лидерборд спасателей</h3>
+  <div class="toolbar">
+    <input id="qHostages" type="search" placeholder="Поиск по именам…">
+    <label class="small"><input id="heatHostages" type="checkbox" checked> Heatmap</label>
+  </div>
+  <div class="table-wrap"><table id="gridHostages"><thead></thead><tbody></tbody></table></div>
+  <div class="small" style="margin-top:6px">Очки = спасённые заложники минус убитые заложники. Раунды и победы спасением считаются только на картах cs_*.</div>
+</div>`
+}
+
+// GenerateJS генерирует JavaScript для таба заложников
+func (h *HostagesTabComponent) GenerateJS(data *stats.StatsData) string {
+	type PlayerMapping struct {
+		Title string
+		Key   string
+	}
+	playerMappings := make([]PlayerMapping, len(data.Players))
+	for i, p := range data.Players {
+		playerMappings[i] = PlayerMapping{Title: p.Title, Key: p.Key}
+	}
+
+	jPlayerMappings, _ := json.Marshal(playerMappings)
+	jRescueWin, _ := json.Marshal(logparser.WinHostageRescued)
+
+	return fmt.Sprintf(`
+// Init: Заложники
+window.hostagesTabState = (function() {
+  const playerMappings = %s;
+  const playerTitles = playerMappings.map(p => p.Title);
+  const rescueWin = %s;
+
+  const playerIndexMap = {};
+  playerMappings.forEach((p, idx) => {
+    playerIndexMap[p.Title] = idx;
+    playerIndexMap[p.Key] = idx;
+  });
+
+  function renderHostagesTab() {
+    const n = playerMappings.length;
+    const rescues = Array(n).fill(0);
+    const pickups = Array(n).fill(0);
+    const kills = Array(n).fill(0);
+    const rounds = Array(n).fill(0);
+    const rescueWins = Array(n).fill(0);
+
+    (window.filteredHostageEvents || []).forEach(e => {
+      let idx = playerIndexMap[e.PlayerName];
+      if (idx === undefined) idx = playerIndexMap[e.PlayerSID];
+      if (idx === undefined) return;
+      if (e.EventType === "rescue") rescues[idx]++;
+      if (e.EventType === "pickup") pickups[idx]++;
+      if (e.EventType === "kill") kills[idx]++;
+    });
+    (window.filteredRoundStats || []).forEach(round => {
+      if (!round.Map || round.Map.indexOf("cs_") !== 0) return;
+      (round.Players || []).forEach(ps => {
+        const idx = playerIndexMap["[U:1:" + ps.AccountID + "]"];
+        if (idx === undefined) return;
+        rounds[idx]++;
+        if (ps.Team === 3 && round.Winner === 3 && round.WinCondition === rescueWin) rescueWins[idx]++;
+      });
+    });
+
+    renderColumnTable({
+      rootId: "#gridHostages",
+      players: playerTitles,
+      columns: [
+        {title: "Очки", data: rescues.map((r, i) => r - kills[i])},
+        {title: "Спасено", data: rescues},
+        {title: "Подобрано", data: pickups},
+        {title: "Убито заложников", data: kills},
+        {title: "Раунды на cs_*", data: rounds},
+        {title: "Победы спасением", data: rescueWins}
+      ],
+      qInputId: "qHostages",
+      heatToggleId: "heatHostages"
+    });
+  }
+
+  // Переотрисовка при изменении фильтра дат
+  window.addEventListener('dateFilterChanged', renderHostagesTab);
+
+  return { render: renderHostagesTab };
+})();
+
+// Начальная отрисовка
+window.hostagesTabState.render();`,
+		string(jPlayerMappings),
+		string(jRescueWin))
+}
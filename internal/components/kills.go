@@ -69,6 +69,15 @@ func (k *KillsTabComponent) GenerateHTML(data *stats.StatsData) string {
   </div>
   <div class="table-wrap"><table id="gridKills"><thead></thead><tbody></tbody></table></div>
   <div class="small" style="margin-top:6px">Подсказка: <strong>Клик на имя в заголовке (сверху)</strong> сортирует строки по этому столбцу (кто больше убил этого игрока). <strong>Клик на имя слева</strong> сортирует столбцы по этой строке (кого этот игрок больше убивал). <strong>Повторный клик</strong> меняет направление (↓/↑). Клик по левому углу — сброс сортировки. <strong>Золотая подсветка</strong> 🥇 — основная жертва "Сорян, Братан", <strong>серебряная подсветка</strong> 🥈 — специальная цель.</div>
+
+  <!-- Хедшоты, прострелы, ноускоупы по убийцам -->
+  <h3 style="color:var(--accent);font-size:18px;margin:30px 0 0;">🎯 Хедшоты и прострелы</h3>
+  <div class="toolbar">
+    <input id="qKillMods" type="search" placeholder="Поиск по именам…">
+    <label class="small"><input id="heatKillMods" type="checkbox" checked> Heatmap</label>
+  </div>
+  <div class="table-wrap"><table id="gridKillMods"><thead></thead><tbody></tbody></table></div>
+  <div class="small" style="margin-top:6px">Прострел — убийство через стену или объект (penetrated). Вслепую — убийца был ослеплён флешкой.</div>
 </div>`,
 		data.KillMatrix.Max)
 }
@@ -143,6 +152,23 @@ window.killsTabState = (function() {
       highlightedPlayer: highlightedPlayer,
       secondaryTarget: secondaryTarget
     });
+
+    renderKillModsTable();
+  }
+
+  function renderKillModsTable() {
+    const columns = countKillModifiers(window.filteredKillEvents || [], playerMappings.length, e => {
+      const kIdx = playerIndexMap[e.KillerName];
+      return kIdx === undefined ? playerIndexMap[e.KillerSID] : kIdx;
+    });
+
+    renderColumnTable({
+      rootId: "#gridKillMods",
+      players: playerTitles,
+      columns: columns,
+      qInputId: "qKillMods",
+      heatToggleId: "heatKillMods"
+    });
   }
 
   // Переотрисовка при изменении фильтра дат
@@ -45,6 +45,10 @@ func (r *PlayerRatingsTabComponent) GenerateHTML() string {
   </div>
 
   <div class="toolbar">
+    <label class="small">Модель:
+      <select id="ratingModel" style="background:var(--panel);color:var(--text);border:1px solid rgba(124,92,255,0.3);border-radius:6px;padding:6px 10px;font-size:14px;cursor:pointer;"></select>
+    </label>
+    <span class="small" id="ratingModelDescription" style="color:var(--muted);"></span>
     <span class="small" style="margin-left: auto;" id="ratingsCount">Игроков: 0</span>
   </div>
 
@@ -189,12 +193,17 @@ func (r *PlayerRatingsTabComponent) GenerateJS(data *stats.StatsData) string {
 	minRounds := data.MinRoundsForRating
 	averageMu := data.AverageMu
 
+	jModels, _ := json.Marshal(data.ModelRatings)
+	defaultModel, _ := json.Marshal(data.RatingModel)
+
 	return fmt.Sprintf(`
 // Init: Рейтинг игроков
 window.playerRatingsTabState = (function() {
   const originalRatings = %s;
   const K = %v; // Минимальное количество раундов для достоверности
   const AVERAGE_MU = %v; // Средний EPI всех игроков (вычислено из реальных данных)
+  const RATING_MODELS = %s; // Рейтинги всех моделей, посчитанные в Go (stats.ModelRatings)
+  let currentModel = %s;
 
   const ratingsCount = document.getElementById('ratingsCount');
   const ratingsTable = document.getElementById('ratingsTable');
@@ -256,10 +265,80 @@ window.playerRatingsTabState = (function() {
     alert('Правильное решение! Берегите своё эго 😌');
   };
 
+  // Выбор модели рейтинга
+  const modelSelect = document.getElementById('ratingModel');
+  const modelDescription = document.getElementById('ratingModelDescription');
+  RATING_MODELS.forEach(function(m) {
+    const option = document.createElement('option');
+    option.value = m.Model;
+    option.textContent = m.Title;
+    modelSelect.appendChild(option);
+  });
+  modelSelect.value = currentModel;
+  modelSelect.addEventListener('change', function() {
+    currentModel = modelSelect.value;
+    renderRatingsTable();
+  });
+
+  // Модели, кроме EPI, посчитаны в Go за весь период логов — фильтр дат для них скрываем
+  function updateModelDateFilter() {
+    dateFilterHiddenTabs['player-ratings'] = currentModel !== 'epi';
+    updateDateFilterVisibility();
+  }
+
+  function currentModelInfo() {
+    return RATING_MODELS.find(function(m) { return m.Model === currentModel; }) || {Model: 'epi', Title: 'EPI + Байес', Description: '', Mean: AVERAGE_MU};
+  }
+
+  // Рейтинги моделей по игрокам: модель → AccountID → ModelRating
+  const MODEL_RATINGS = {};
+  RATING_MODELS.forEach(function(m) {
+    MODEL_RATINGS[m.Model] = {};
+    (m.Ratings || []).forEach(function(r) {
+      MODEL_RATINGS[m.Model][r.AccountID] = r;
+    });
+  });
+
+  // applyRatingModel проставляет Score выбранной модели и сортирует по нему.
+  // EPI пересчитывается по фильтру дат, остальные модели берутся из Go — они посчитаны за весь период логов,
+  // поэтому для них и строки таблицы считаются за весь период.
+  function applyRatingModel(ratings, model) {
+    if (model !== 'epi') {
+      var modelRatings = MODEL_RATINGS[model] || {};
+      ratings = ratings.filter(function(r) { return modelRatings[r.AccountID]; });
+    }
+    ratings.forEach(function(r) {
+      if (model === 'epi') {
+        r.Score = r.BayesianEPI;
+        r.ScoreRounds = r.RoundsPlayed;
+      } else {
+        r.Score = MODEL_RATINGS[model][r.AccountID].Score;
+        r.ScoreRounds = MODEL_RATINGS[model][r.AccountID].Rounds;
+      }
+    });
+
+    ratings.sort(function(a, b) {
+      return b.Score - a.Score;
+    });
+    return ratings;
+  }
+
+  // accountIDFromSID извлекает Account ID из SteamID "[U:1:N]"
+  function accountIDFromSID(sid) {
+    var m = /^\[U:1:(\d+)\]$/.exec(sid || '');
+    return m ? Number(m[1]) : 0;
+  }
+
   // Функция для расчета рейтингов из раундов
-  function calculateRatings(roundStats) {
+  function calculateRatings(roundStats, killEvents, tradeEvents) {
     const playerData = {};
 
+    // Разменянные смерти по раундам для KAST: MatchID|Round|AccountID
+    var tradedDeaths = {};
+    tradeEvents.forEach(function(e) {
+      tradedDeaths[e.MatchID + '|' + e.Round + '|' + accountIDFromSID(e.VictimSID)] = true;
+    });
+
     // Агрегируем данные по игрокам
     roundStats.forEach(function(round) {
       round.Players.forEach(function(playerStats) {
@@ -276,7 +355,16 @@ window.playerRatingsTabState = (function() {
             TotalDeaths: 0,
             TotalAssists: 0,
             WinRounds: 0,
-            LastPlayed: ''
+            LastPlayed: '',
+            TradesMade: 0,
+            TradedDeaths: 0,
+            UntradedDeaths: 0,
+            AvgTradeTime: 0,
+            KASTRounds: 0,
+            KAST: 0,
+            SurvivedRounds: 0,
+            SurvivalRate: 0,
+            DeathsPerRound: 0
           };
         }
 
@@ -288,6 +376,13 @@ window.playerRatingsTabState = (function() {
         rating.TotalDeaths += playerStats.Deaths;
         rating.TotalAssists += playerStats.Assists;
 
+        // KAST: убийство, ассист, выживание или разменянная смерть
+        if (playerStats.Survived) rating.SurvivedRounds++;
+        if (playerStats.Kills > 0 || playerStats.Assists > 0 || playerStats.Survived ||
+            tradedDeaths[round.MatchID + '|' + round.RoundNumber + '|' + playerStats.AccountID]) {
+          rating.KASTRounds++;
+        }
+
         // Проверяем победу
         if ((playerStats.Team === 3 && round.Winner === 3) || (playerStats.Team === 2 && round.Winner === 2)) {
           rating.WinRounds++;
@@ -300,6 +395,32 @@ window.playerRatingsTabState = (function() {
       });
     });
 
+    // Размены: смерти считаем по убийствам, разменянные — по событиям размена
+    var tradeTime = {};
+    killEvents.forEach(function(e) {
+      var rating = playerData[accountIDFromSID(e.VictimSID)];
+      if (rating) rating.UntradedDeaths++;
+    });
+    tradeEvents.forEach(function(e) {
+      var trader = playerData[accountIDFromSID(e.PlayerSID)];
+      if (trader) {
+        trader.TradesMade++;
+        tradeTime[trader.AccountID] = (tradeTime[trader.AccountID] || 0) + e.Seconds;
+      }
+      var victim = playerData[accountIDFromSID(e.VictimSID)];
+      if (victim) {
+        victim.TradedDeaths++;
+        victim.UntradedDeaths--;
+      }
+    });
+    for (var accountID in playerData) {
+      var rating = playerData[accountID];
+      rating.AvgTradeTime = rating.TradesMade > 0 ? tradeTime[accountID] / rating.TradesMade : 0;
+      rating.KAST = rating.RoundsPlayed > 0 ? rating.KASTRounds * 100 / rating.RoundsPlayed : 0;
+      rating.SurvivalRate = rating.RoundsPlayed > 0 ? rating.SurvivedRounds * 100 / rating.RoundsPlayed : 0;
+      rating.DeathsPerRound = rating.RoundsPlayed > 0 ? rating.TotalDeaths / rating.RoundsPlayed : 0;
+    }
+
     // Находим имена игроков из оригинальных рейтингов
     originalRatings.forEach(function(orig) {
       if (playerData[orig.AccountID]) {
@@ -345,7 +466,33 @@ window.playerRatingsTabState = (function() {
   }
 
   function renderRatingsTable() {
-    var ratings = calculateRatings(window.filteredRoundStats || []);
+    var model = currentModelInfo();
+    var ratings;
+    if (model.Model === 'epi') {
+      ratings = calculateRatings(window.filteredRoundStats || [], window.filteredKillEvents || [], window.filteredTradeEvents || []);
+    } else {
+      ratings = calculateRatings(getAllEvents(DAILY_ROUNDS), getAllEvents(DAILY_KILLS), getAllEvents(DAILY_TRADES));
+    }
+    ratings = applyRatingModel(ratings, model.Model);
+    modelDescription.textContent = model.Description;
+    updateModelDateFilter();
+
+    // Категории EPI считаются от μ, остальных моделей — от среднего по раундам рейтинга показанных игроков
+    var thresholds = THRESHOLDS;
+    if (model.Model !== 'epi') {
+      var totalScore = 0;
+      var scoreRounds = 0;
+      ratings.forEach(function(r) {
+        totalScore += r.Score * r.ScoreRounds;
+        scoreRounds += r.ScoreRounds;
+      });
+      var modelMean = scoreRounds > 0 ? totalScore / scoreRounds : model.Mean;
+      thresholds = {
+        weak: (modelMean * 0.85).toFixed(2),
+        average: (modelMean * 1.05).toFixed(2),
+        monster: (modelMean * 1.25).toFixed(2)
+      };
+    }
 
     if (ratings.length === 0) {
       ratingsTable.innerHTML = '<div class="small" style="padding:20px;text-align:center;color:var(--muted)">Нет данных</div>';
@@ -369,12 +516,18 @@ window.playerRatingsTabState = (function() {
     html += '<thead><tr style="background:var(--sticky);text-align:left;">';
     html += '<th style="padding:12px;text-align:center;width:60px;">#</th>';
     html += '<th style="padding:12px;">Игрок</th>';
-    html += '<th style="padding:12px;text-align:center;">Рейтинг</th>';
+    html += '<th style="padding:12px;text-align:center;" title="' + model.Description + '">' + (model.Model === 'epi' ? 'Рейтинг' : model.Title) + '</th>';
     html += '<th style="padding:12px;text-align:center;">Раундов</th>';
     html += '<th style="padding:12px;text-align:center;">K/D/A</th>';
     html += '<th style="padding:12px;text-align:center;">Урон</th>';
     html += '<th style="padding:12px;text-align:center;">Побед</th>';
     html += '<th style="padding:12px;text-align:center;">Win%%</th>';
+    html += '<th style="padding:12px;text-align:center;" title="Доля раундов с убийством, ассистом, выживанием или разменянной смертью">KAST</th>';
+    html += '<th style="padding:12px;text-align:center;" title="Доля раундов, до конца которых игрок дожил">Выживаемость</th>';
+    html += '<th style="padding:12px;text-align:center;" title="Смертей за раунд">DPR</th>';
+    html += '<th style="padding:12px;text-align:center;" title="Убил убийцу союзника в течение %v с после его смерти">Размены</th>';
+    html += '<th style="padding:12px;text-align:center;" title="Смерти, разменянные союзниками / неразменянные">Разменян / нет</th>';
+    html += '<th style="padding:12px;text-align:center;" title="Среднее время от смерти союзника до размена">Время размена</th>';
     html += '<th style="padding:12px;text-align:center;">Последняя игра</th>';
     html += '</tr></thead>';
     html += '<tbody>';
@@ -386,13 +539,13 @@ window.playerRatingsTabState = (function() {
       // Определяем цвет рейтинга на основе адаптивных границ (цвета предметов CS2)
       let ratingColor = 'var(--text)';
       let playerStatus = '';
-      if (player.BayesianEPI >= parseFloat(THRESHOLDS.monster)) {
+      if (player.Score >= parseFloat(thresholds.monster)) {
         ratingColor = '#cfb53b'; // gold - Гиперебака (Covert)
         playerStatus = 'Гиперебака';
-      } else if (player.BayesianEPI >= parseFloat(THRESHOLDS.average)) {
+      } else if (player.Score >= parseFloat(thresholds.average)) {
         ratingColor = '#ef4444'; // red - Ебака (Classified)
         playerStatus = 'Ебака';
-      } else if (player.BayesianEPI >= parseFloat(THRESHOLDS.weak)) {
+      } else if (player.Score >= parseFloat(thresholds.weak)) {
         ratingColor = '#4b69ff'; // blue - Пердун (Restricted)
         playerStatus = 'Пердун';
       } else {
@@ -423,12 +576,22 @@ window.playerRatingsTabState = (function() {
       html += '<tr style="border-bottom:1px solid var(--grid);">';
       html += '<td style="padding:12px;text-align:center;color:var(--muted);font-weight:bold;">' + (idx + 1) + '</td>';
       html += '<td style="padding:12px;' + nameStyle + '">' + player.Name + unreliableWarning + '</td>';
-      html += '<td class="rating-cell" style="padding:12px;text-align:center;font-weight:bold;font-size:16px;color:' + ratingColor + ';" data-tooltip="' + encodeURIComponent(JSON.stringify(tooltipData)) + '">' + player.BayesianEPI.toFixed(3) + '</td>';
+      if (model.Model === 'epi') {
+        html += '<td class="rating-cell" style="padding:12px;text-align:center;font-weight:bold;font-size:16px;color:' + ratingColor + ';" data-tooltip="' + encodeURIComponent(JSON.stringify(tooltipData)) + '">' + player.BayesianEPI.toFixed(3) + '</td>';
+      } else {
+        html += '<td style="padding:12px;text-align:center;font-weight:bold;font-size:16px;color:' + ratingColor + ';" title="' + playerStatus + '">' + player.Score.toFixed(3) + '</td>';
+      }
       html += '<td style="padding:12px;text-align:center;">' + player.RoundsPlayed + '</td>';
       html += '<td style="padding:12px;text-align:center;">' + player.TotalKills + ' / ' + player.TotalDeaths + ' / ' + player.TotalAssists + '</td>';
       html += '<td style="padding:12px;text-align:center;">' + player.TotalDamage + '</td>';
       html += '<td style="padding:12px;text-align:center;">' + player.WinRounds + '</td>';
       html += '<td style="padding:12px;text-align:center;">' + winRate + '%%</td>';
+      html += '<td style="padding:12px;text-align:center;">' + player.KAST.toFixed(1) + '%%</td>';
+      html += '<td style="padding:12px;text-align:center;">' + player.SurvivalRate.toFixed(1) + '%%</td>';
+      html += '<td style="padding:12px;text-align:center;">' + player.DeathsPerRound.toFixed(2) + '</td>';
+      html += '<td style="padding:12px;text-align:center;">' + player.TradesMade + '</td>';
+      html += '<td style="padding:12px;text-align:center;">' + player.TradedDeaths + ' / ' + player.UntradedDeaths + '</td>';
+      html += '<td style="padding:12px;text-align:center;">' + (player.TradesMade > 0 ? player.AvgTradeTime.toFixed(1) + ' с' : '-') + '</td>';
       html += '<td style="padding:12px;text-align:center;color:var(--muted);font-size:13px;">' + (player.LastPlayed || '-') + '</td>';
       html += '</tr>';
     });
@@ -494,5 +657,5 @@ window.playerRatingsTabState = (function() {
 
 // Начальная отрисовка
 window.playerRatingsTabState.render();
-`, string(jRatings), minRounds, averageMu)
+`, string(jRatings), minRounds, averageMu, string(jModels), string(defaultModel), data.TradeWindow.Seconds())
 }
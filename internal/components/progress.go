@@ -116,19 +116,20 @@ type FlashVictimStat struct {
 
 // PlayerProgress представляет данные прогресса игрока по датам
 type PlayerProgress struct {
-	AccountID     int64              `json:"account_id"`
-	Name          string             `json:"name"`
-	Daily         []DailyPlayerStats `json:"daily"`
-	Totals        PlayerTotalStats   `json:"totals"`
-	ByHour        []TimeSlotStats    `json:"by_hour"`
-	ByDayOfWeek   []TimeSlotStats    `json:"by_day_of_week"`
-	BestTimeSlot  string             `json:"best_time_slot"`
-	WorstTimeSlot string             `json:"worst_time_slot"`
-	TopPartners   []PlayerPairStats  `json:"top_partners"`
-	MapStats      []PlayerMapStats   `json:"map_stats"`   // Статистика игрока по картам
-	TvsCTStats    *PlayerTvsCTStats  `json:"tvsct_stats"` // T vs CT статистика игрока
-	TopWeapons    []WeaponStat       `json:"top_weapons"` // Топ-5 оружий игрока
-	FlashStats    *PlayerFlashStats  `json:"flash_stats"` // Статистика флэшбэнгов
+	AccountID     int64                     `json:"account_id"`
+	Name          string                    `json:"name"`
+	Daily         []DailyPlayerStats        `json:"daily"`
+	Totals        PlayerTotalStats          `json:"totals"`
+	ByHour        []TimeSlotStats           `json:"by_hour"`
+	ByDayOfWeek   []TimeSlotStats           `json:"by_day_of_week"`
+	BestTimeSlot  string                    `json:"best_time_slot"`
+	WorstTimeSlot string                    `json:"worst_time_slot"`
+	TopPartners   []PlayerPairStats         `json:"top_partners"`
+	MapStats      []PlayerMapStats          `json:"map_stats"`   // Статистика игрока по картам
+	TvsCTStats    *PlayerTvsCTStats         `json:"tvsct_stats"` // T vs CT статистика игрока
+	TopWeapons    []WeaponStat              `json:"top_weapons"` // Топ-5 оружий игрока
+	FlashStats    *PlayerFlashStats         `json:"flash_stats"` // Статистика флэшбэнгов
+	Entry         *stats.PlayerOpeningStats `json:"entry"`       // Первые дуэли раундов
 }
 
 // DailyPlayerStats статистика игрока за один день
@@ -160,12 +161,13 @@ type PlayerTotalStats struct {
 
 // ProgressData общие данные для всего таба
 type ProgressData struct {
-	Players       []PlayerProgress   `json:"players"`
-	OverallByHour []TimeSlotStats    `json:"overall_by_hour"`
-	OverallByDay  []TimeSlotStats    `json:"overall_by_day"`
-	TopPairs      []PlayerPairStats  `json:"top_pairs"`
-	MapStats      []MapStats         `json:"map_stats"`   // Общая статистика по картам
-	TvsCTStats    []PlayerTvsCTStats `json:"tvsct_stats"` // T vs CT статистика всех игроков
+	Players       []PlayerProgress           `json:"players"`
+	OverallByHour []TimeSlotStats            `json:"overall_by_hour"`
+	OverallByDay  []TimeSlotStats            `json:"overall_by_day"`
+	TopPairs      []PlayerPairStats          `json:"top_pairs"`
+	MapStats      []MapStats                 `json:"map_stats"`   // Общая статистика по картам
+	TvsCTStats    []PlayerTvsCTStats         `json:"tvsct_stats"` // T vs CT статистика всех игроков
+	Entry         []stats.PlayerOpeningStats `json:"entry"`       // Первые дуэли раундов всех игроков
 }
 
 // GenerateHTML генерирует HTML для таба прогресса
@@ -200,10 +202,19 @@ func (p *ProgressTabComponent) GenerateHTML() string {
       <div id="mapStatsContent"></div>
     </div>
 
-    <div style="background:var(--panel);padding:20px;border-radius:12px;border:1px solid rgba(124,92,255,0.1);">
+    <div style="background:var(--panel);padding:20px;border-radius:12px;margin-bottom:24px;border:1px solid rgba(124,92,255,0.1);">
       <h3 style="margin:0 0 16px;color:var(--accent);font-size:18px;">⚔️ T vs CT Статистика</h3>
       <div id="tvsctStatsContent"></div>
     </div>
+
+    <div style="background:var(--panel);padding:20px;border-radius:12px;border:1px solid rgba(124,92,255,0.1);">
+      <h3 style="margin:0 0 8px;color:var(--accent);font-size:18px;">🚪 Entry: первые дуэли</h3>
+      <div style="font-size:12px;color:var(--muted);margin-bottom:16px;padding:12px;background:rgba(124,92,255,0.05);border-radius:6px;border-left:3px solid rgba(124,92,255,0.3);">
+        <strong>Как считается:</strong> первое убийство раунда — это первая дуэль. Открывший раунд выиграл дуэль, убитый — умер первым.
+        «WR после» — как часто команда игрока выигрывала раунд после его выигранной или проигранной первой дуэли.
+      </div>
+      <div id="entryStatsContent"></div>
+    </div>
   </div>
 
   <!-- Статистика конкретного игрока -->
@@ -276,6 +287,12 @@ func (p *ProgressTabComponent) GenerateHTML() string {
       <div id="playerTvsCTContent"></div>
     </div>
 
+    <!-- Первые дуэли -->
+    <div style="background:var(--panel);padding:20px;border-radius:12px;margin-bottom:24px;border:1px solid rgba(124,92,255,0.1);">
+      <h3 style="margin:0 0 16px;color:var(--accent);font-size:18px;">🚪 Entry: первые дуэли</h3>
+      <div id="playerEntryContent"></div>
+    </div>
+
     <!-- Топ оружий -->
     <div style="background:var(--panel);padding:20px;border-radius:12px;margin-bottom:24px;border:1px solid rgba(124,92,255,0.1);">
       <h3 style="margin:0 0 16px;color:var(--accent);font-size:18px;">🔫 Топ оружий</h3>
@@ -368,6 +385,9 @@ func (p *ProgressTabComponent) GenerateJS(data *stats.StatsData) string {
 
     // T vs CT статистика
     renderTvsCTStats();
+
+    // Первые дуэли
+    renderEntryStats();
   }
 
   function showPlayerStats(player) {
@@ -471,6 +491,9 @@ func (p *ProgressTabComponent) GenerateJS(data *stats.StatsData) string {
     // T vs CT статистика игрока
     renderPlayerTvsCT(player);
 
+    // Первые дуэли игрока
+    renderPlayerEntry(player);
+
     // Топ оружий игрока
     renderPlayerWeapons(player);
 
@@ -697,6 +720,65 @@ func (p *ProgressTabComponent) GenerateJS(data *stats.StatsData) string {
     div.innerHTML = html;
   }
 
+  // entryCells форматирует ячейки первых дуэлей: дуэлей, побед, первых смертей, успех, WR после победы и смерти
+  function entryCells(s) {
+    const successColor = s.SuccessRate >= 50 ? '#22c55e' : '#ef4444';
+    const afterWin = s.Wins > 0 ? s.WinRateAfterWin.toFixed(1) + '%%' : '-';
+    const afterFD = s.FirstDeaths > 0 ? s.WinRateAfterFD.toFixed(1) + '%%' : '-';
+    return '<td>' + s.Attempts + '</td>' +
+      '<td style="color:#22c55e;">' + s.Wins + '</td>' +
+      '<td style="color:#ef4444;">' + s.FirstDeaths + '</td>' +
+      '<td style="color:' + successColor + ';font-weight:bold;">' + s.SuccessRate.toFixed(1) + '%%</td>' +
+      '<td>' + afterWin + '</td>' +
+      '<td>' + afterFD + '</td>';
+  }
+
+  const entryHeader = '<th>Дуэлей</th><th>Открыл</th><th>Умер первым</th><th>Успех</th><th>WR после победы</th><th>WR после смерти</th>';
+
+  function renderEntryStats() {
+    const div = document.getElementById('entryStatsContent');
+    if (!data.entry || data.entry.length === 0) {
+      div.innerHTML = '<div style="text-align:center;padding:40px;color:var(--muted);">Недостаточно данных</div>';
+      return;
+    }
+
+    let html = '<table style="width:100%%;"><thead><tr>' +
+      '<th>Игрок</th>' + entryHeader +
+      '<th style="color:#f59e0b;">T успех</th>' +
+      '<th style="color:#3b82f6;">CT успех</th>' +
+    '</tr></thead><tbody>';
+
+    data.entry.forEach(e => {
+      html += '<tr>' +
+        '<td>' + e.Name + '</td>' +
+        entryCells(e.Total) +
+        '<td style="color:#f59e0b;">' + (e.T.Attempts > 0 ? e.T.SuccessRate.toFixed(1) + '%%' : '-') + '</td>' +
+        '<td style="color:#3b82f6;">' + (e.CT.Attempts > 0 ? e.CT.SuccessRate.toFixed(1) + '%%' : '-') + '</td>' +
+      '</tr>';
+    });
+    html += '</tbody></table>';
+    div.innerHTML = html;
+  }
+
+  function renderPlayerEntry(player) {
+    const div = document.getElementById('playerEntryContent');
+    if (!player.entry || player.entry.Total.Attempts === 0) {
+      div.innerHTML = '<div style="text-align:center;padding:20px;color:var(--muted);">Недостаточно данных</div>';
+      return;
+    }
+
+    const e = player.entry;
+    let html = '<table style="width:100%%;"><thead><tr><th></th>' + entryHeader + '</tr></thead><tbody>' +
+      '<tr style="font-weight:bold;"><td>Всего</td>' + entryCells(e.Total) + '</tr>' +
+      '<tr><td style="color:#f59e0b;">T</td>' + entryCells(e.T) + '</tr>' +
+      '<tr><td style="color:#3b82f6;">CT</td>' + entryCells(e.CT) + '</tr>';
+    (e.ByMap || []).forEach(m => {
+      html += '<tr><td>' + m.Map + '</td>' + entryCells(m) + '</tr>';
+    });
+    html += '</tbody></table>';
+    div.innerHTML = html;
+  }
+
   function renderPlayerWeapons(player) {
     const div = document.getElementById('playerWeaponsContent');
     if (!player.top_weapons || player.top_weapons.length === 0) {
@@ -1492,6 +1574,17 @@ func (p *ProgressTabComponent) buildProgressData(data *stats.StatsData) *Progres
 		}
 	}
 
+	// Первые дуэли: имена берём из рейтингов, как и во всём табе
+	for _, entry := range data.OpeningStats {
+		if name, ok := playerNames[entry.AccountID]; ok {
+			entry.Name = name
+		}
+		result.Entry = append(result.Entry, entry)
+		if player := playerMap[entry.AccountID]; player != nil {
+			player.Entry = &entry
+		}
+	}
+
 	// Вычисляем метрики для карт
 	for _, mapStat := range mapStatsMap {
 		if mapStat.TotalRounds > 0 {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"oldfartscounter/internal/logparser"
 	"oldfartscounter/internal/stats"
 )
 
@@ -47,50 +48,29 @@ func (r *RoundsTabComponent) GenerateJS(data *stats.StatsData) string {
 
 	// Парсим из событий убийств
 	for _, event := range data.KillEvents {
-		// Парсим SteamID для получения AccountID
-		// Формат: [U:1:26840160] -> 26840160
-		if len(event.KillerSID) > 6 {
-			var accountID int64
-			_, _ = fmt.Sscanf(event.KillerSID[5:len(event.KillerSID)-1], "%d", &accountID)
-			if accountID > 0 {
-				playerMap[accountID] = event.KillerName
-			}
+		// Формат SteamID: [U:1:26840160] -> 26840160
+		if accountID, ok := logparser.AccountIDFromSID(event.KillerSID); ok {
+			playerMap[accountID] = event.KillerName
 		}
-		if len(event.VictimSID) > 6 {
-			var accountID int64
-			_, _ = fmt.Sscanf(event.VictimSID[5:len(event.VictimSID)-1], "%d", &accountID)
-			if accountID > 0 {
-				playerMap[accountID] = event.VictimName
-			}
+		if accountID, ok := logparser.AccountIDFromSID(event.VictimSID); ok {
+			playerMap[accountID] = event.VictimName
 		}
 	}
 
 	// Парсим из событий флешек
 	for _, event := range data.FlashEvents {
-		if len(event.FlasherSID) > 6 {
-			var accountID int64
-			_, _ = fmt.Sscanf(event.FlasherSID[5:len(event.FlasherSID)-1], "%d", &accountID)
-			if accountID > 0 && playerMap[accountID] == "" {
-				playerMap[accountID] = event.FlasherName
-			}
+		if accountID, ok := logparser.AccountIDFromSID(event.FlasherSID); ok && playerMap[accountID] == "" {
+			playerMap[accountID] = event.FlasherName
 		}
-		if len(event.VictimSID) > 6 {
-			var accountID int64
-			_, _ = fmt.Sscanf(event.VictimSID[5:len(event.VictimSID)-1], "%d", &accountID)
-			if accountID > 0 && playerMap[accountID] == "" {
-				playerMap[accountID] = event.VictimName
-			}
+		if accountID, ok := logparser.AccountIDFromSID(event.VictimSID); ok && playerMap[accountID] == "" {
+			playerMap[accountID] = event.VictimName
 		}
 	}
 
 	// Парсим из событий дефьюза
 	for _, event := range data.DefuseEvents {
-		if len(event.PlayerSID) > 6 {
-			var accountID int64
-			_, _ = fmt.Sscanf(event.PlayerSID[5:len(event.PlayerSID)-1], "%d", &accountID)
-			if accountID > 0 && playerMap[accountID] == "" {
-				playerMap[accountID] = event.PlayerName
-			}
+		if accountID, ok := logparser.AccountIDFromSID(event.PlayerSID); ok && playerMap[accountID] == "" {
+			playerMap[accountID] = event.PlayerName
 		}
 	}
 
@@ -157,18 +137,19 @@ func (r *RoundsTabComponent) GenerateJS(data *stats.StatsData) string {
   const roundsList = document.getElementById('roundsList');
   const roundsCount = document.getElementById('roundsCount');
 
-  // Группируем раунды: Дата → Карта → Раунды
+  // Группируем раунды: Дата → Матч → Раунды.
+  // Матч определяется по MatchID, поэтому две игры на одной карте за вечер не сливаются.
   function groupRounds(rounds) {
     const grouped = {};
 
     rounds.forEach(round => {
       const date = round.Date || 'Unknown';
-      const map = round.Map || 'Unknown';
+      const match = round.MatchID || round.Map || 'Unknown';
 
       if(!grouped[date]) grouped[date] = {};
-      if(!grouped[date][map]) grouped[date][map] = [];
+      if(!grouped[date][match]) grouped[date][match] = [];
 
-      grouped[date][map].push(round);
+      grouped[date][match].push(round);
     });
 
     return grouped;
@@ -271,7 +252,7 @@ func (r *RoundsTabComponent) GenerateJS(data *stats.StatsData) string {
         html += '<div class="map-group" style="margin-bottom:20px;">';
         html += '<div class="map-header" data-map-id="' + date + '-' + map + '" style="padding:10px;background:var(--panel);cursor:pointer;border-radius:8px;display:flex;align-items:center;gap:12px;border-left:4px solid var(--accent);">';
         html += '<span class="expand-indicator" style="flex-shrink:0;">▶</span>';
-        html += '<strong style="flex-shrink:0;">' + map + '</strong>';
+        html += '<strong style="flex-shrink:0;">' + (rounds[0].Map || 'Unknown') + '</strong>';
         html += '<span style="font-size:13px;font-weight:600;background:var(--panel-2);padding:3px 8px;border-radius:5px;">';
         html += '<span style="color:' + (t1Won ? '#22c55e' : '#c8c8c8') + ';">[Team 1] ' + finalScores.t1 + '</span>';
         html += ' : ';
@@ -429,6 +410,7 @@ func (r *RoundsTabComponent) GenerateJS(data *stats.StatsData) string {
     html += '<div>';
     html += '<span class="expand-indicator" style="margin-right:10px;color:var(--accent);">▶</span>';
     html += '<strong>Раунд ' + round.RoundNumber + '</strong>';
+    if(round.Overtime) html += ' <span class="small" style="color:#ffa500;">OT' + round.Overtime + '</span>';
     html += ' — Счёт: <span style="color:#3b82f6;">[Team 1] ' + scores.t1 + '</span> : <span style="color:#ef4444;">[Team 2] ' + scores.t2 + '</span>';
     html += '</div>';
     html += '<div class="small">Игроков: ' + round.Players.length + '</div>';
@@ -0,0 +1,112 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"oldfartscounter/internal/logparser"
+	"oldfartscounter/internal/stats"
+)
+
+// ShameTabComponent отвечает за таб "Позор": тимкиллы, самоубийства, падения и смерти от бомбы
+type ShameTabComponent struct{}
+
+// NewShameTab создает новый компонент таба позора
+func NewShameTab() *ShameTabComponent {
+	return &ShameTabComponent{}
+}
+
+// GenerateHTML генерирует HTML для таба позора
+func (s *ShameTabComponent) GenerateHTML(data *stats.StatsData) string {
+	return `
+<!-- SHAME -->
+<div id="tab-shame" class="view">
+  <h3 style="color:var(--accent);font-size:18px;margin:0 0 0;">🤡 Доска позора</h3>
+  <div class="toolbar">
+    <input id="qShame" type="search" placeholder="Поиск по именам…">
+    <label class="small"><input id="heatShame" type="checkbox" checked> Heatmap</label>
+  </div>
+  <div class="table-wrap"><table id="gridShame"><thead></thead><tbody></tbody></table></div>
+  <div class="small" style="margin-top:6px">Позор = убитые союзники + самоубийства + падения + смерти от бомбы. Эти смерти не считаются убийствами в остальных табах. «Убит своими» — не позор, а сочувствие.</div>
+</div>`
+}
+
+// GenerateJS генерирует JavaScript для таба позора
+func (s *ShameTabComponent) GenerateJS(data *stats.StatsData) string {
+	type PlayerMapping struct {
+		Title string
+		Key   string
+	}
+	playerMappings := make([]PlayerMapping, len(data.Players))
+	for i, p := range data.Players {
+		playerMappings[i] = PlayerMapping{Title: p.Title, Key: p.Key}
+	}
+
+	jPlayerMappings, _ := json.Marshal(playerMappings)
+	jKinds, _ := json.Marshal(map[string]string{
+		"teamkill": logparser.ShameTeamKill,
+		"suicide":  logparser.ShameSuicide,
+		"world":    logparser.ShameWorld,
+		"bomb":     logparser.ShameBomb,
+	})
+
+	return fmt.Sprintf(`
+// Init: Позор
+window.shameTabState = (function() {
+  const playerMappings = %s;
+  const playerTitles = playerMappings.map(p => p.Title);
+  const kinds = %s;
+
+  const playerIndexMap = {};
+  playerMappings.forEach((p, idx) => {
+    playerIndexMap[p.Key] = idx;
+  });
+
+  function renderShameTab() {
+    const n = playerMappings.length;
+    const teamKills = Array(n).fill(0);
+    const teamKilled = Array(n).fill(0);
+    const suicides = Array(n).fill(0);
+    const world = Array(n).fill(0);
+    const bomb = Array(n).fill(0);
+
+    (window.filteredShameEvents || []).forEach(e => {
+      const idx = playerIndexMap[e.PlayerSID];
+      if (idx === undefined) return;
+      if (e.Kind === kinds.teamkill) {
+        teamKills[idx]++;
+        const victimIdx = playerIndexMap[e.VictimSID];
+        if (victimIdx !== undefined) teamKilled[victimIdx]++;
+      }
+      if (e.Kind === kinds.suicide) suicides[idx]++;
+      if (e.Kind === kinds.world) world[idx]++;
+      if (e.Kind === kinds.bomb) bomb[idx]++;
+    });
+
+    renderColumnTable({
+      rootId: "#gridShame",
+      players: playerTitles,
+      columns: [
+        {title: "Позор", data: teamKills.map((v, i) => v + suicides[i] + world[i] + bomb[i])},
+        {title: "Убил своих", data: teamKills},
+        {title: "Самоубийства", data: suicides},
+        {title: "Падения", data: world},
+        {title: "Взорван бомбой", data: bomb},
+        {title: "Убит своими", data: teamKilled}
+      ],
+      qInputId: "qShame",
+      heatToggleId: "heatShame"
+    });
+  }
+
+  // Переотрисовка при изменении фильтра дат
+  window.addEventListener('dateFilterChanged', renderShameTab);
+
+  return { render: renderShameTab };
+})();
+
+// Начальная отрисовка
+window.shameTabState.render();`,
+		string(jPlayerMappings),
+		string(jKinds))
+}
@@ -27,6 +27,15 @@ func (w *WeaponsTabComponent) GenerateKillerWeaponHTML(data *stats.StatsData) st
     <div class="legend"><div class="swatch"></div><span class="small">0 → %d</span></div>
   </div>
   <div class="table-wrap"><table id="gridKW"><thead></thead><tbody></tbody></table></div>
+
+  <!-- Хедшоты, прострелы, ноускоупы по оружию -->
+  <h3 style="color:var(--accent);font-size:18px;margin:30px 0 0;">🎯 Хедшоты и прострелы по оружию</h3>
+  <div class="toolbar">
+    <input id="qWeaponMods" type="search" placeholder="Поиск по оружию…">
+    <label class="small"><input id="heatWeaponMods" type="checkbox" checked> Heatmap</label>
+  </div>
+  <div class="table-wrap"><table id="gridWeaponMods"><thead></thead><tbody></tbody></table></div>
+  <div class="small" style="margin-top:6px">Средняя дистанция считается по убийствам, для которых в логе есть координаты обоих игроков.</div>
 </div>`,
 		data.WeaponData.KillerMax)
 }
@@ -115,6 +124,37 @@ window.kwTabState = (function() {
       heatToggleId: "heatKW",
       cornerTitle: "Оружие ↓ / Убийцы →"
     });
+
+    renderWeaponModsTable(weapons);
+  }
+
+  function renderWeaponModsTable(weapons) {
+    const weaponIndex = {};
+    weapons.forEach((w, idx) => { weaponIndex[w] = idx; });
+    const columns = countKillModifiers(window.filteredKillEvents || [], weapons.length, e => weaponIndex[e.Weapon]);
+
+    // Средняя дистанция убийства (юниты → метры, 1 юнит ≈ 2.54 см)
+    const distSum = Array(weapons.length).fill(0);
+    const distCount = Array(weapons.length).fill(0);
+    (window.filteredKillEvents || []).forEach(e => {
+      const idx = weaponIndex[e.Weapon];
+      if (idx === undefined || !e.Distance) return;
+      distSum[idx] += e.Distance;
+      distCount[idx]++;
+    });
+    columns.push({
+      title: "Ср. дистанция, м",
+      data: distSum.map((sum, i) => distCount[i] ? Math.round(sum / distCount[i] * 0.0254 * 10) / 10 : 0)
+    });
+
+    renderColumnTable({
+      rootId: "#gridWeaponMods",
+      players: weapons,
+      rowTitle: "Оружие",
+      columns: columns,
+      qInputId: "qWeaponMods",
+      heatToggleId: "heatWeaponMods"
+    });
   }
 
   window.addEventListener('dateFilterChanged', renderKWTab);
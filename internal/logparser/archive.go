@@ -0,0 +1,190 @@
+package logparser
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// archiveExts — расширения сжатых файлов и архивов, которые парсер открывает сам
+var archiveExts = []string{".tar.gz", ".tgz", ".gz", ".zip"}
+
+// Магические байты для определения формата по содержимому
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+	tarMagic  = []byte("ustar")
+)
+
+// tarMagicOffset — смещение сигнатуры "ustar" в заголовке tar
+const tarMagicOffset = 257
+
+// sourceResult — результат парсинга одного лога: файла на диске или члена архива
+type sourceResult struct {
+	name    string // путь файла; для членов архива — путь архива + имя члена
+	date    time.Time
+	hasDate bool
+	result  *ParseResult
+}
+
+// isArchiveName проверяет, похоже ли имя файла на сжатый файл или архив
+func isArchiveName(name string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range archiveExts {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesExt проверяет фильтр по расширению (пустой фильтр пропускает всё)
+func matchesExt(name, ext string) bool {
+	return ext == "" || strings.HasSuffix(strings.ToLower(name), strings.ToLower(ext))
+}
+
+// parseSource парсит файл с диска. Сжатые файлы и архивы определяются по содержимому,
+// каждый член архива парсится как отдельный лог. ext фильтрует члены архивов.
+func (p *Parser) parseSource(filePath, ext string) ([]sourceResult, error) {
+	f, err := os.Open(filePath) // #nosec G304 - path is controlled by user input for log parsing
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	br := bufio.NewReader(f)
+	magic, _ := br.Peek(len(zipMagic))
+
+	switch {
+	case bytes.HasPrefix(magic, zipMagic):
+		return p.parseZip(f, filePath, ext)
+	case bytes.HasPrefix(magic, gzipMagic):
+		return p.parseGzip(br, filePath, ext)
+	default:
+		src := newSourceResult(filePath, filepath.Base(filePath))
+		return []sourceResult{src}, p.parseStream(br, src.name, src.result)
+	}
+}
+
+// parseGzip парсит gzip файл: либо один сжатый лог, либо tar.gz архив
+func (p *Parser) parseGzip(r io.Reader, filePath, ext string) ([]sourceResult, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = gz.Close()
+	}()
+
+	br := bufio.NewReader(gz)
+	header, _ := br.Peek(tarMagicOffset + len(tarMagic))
+	if len(header) == tarMagicOffset+len(tarMagic) && bytes.Equal(header[tarMagicOffset:], tarMagic) {
+		return p.parseTar(br, filePath, ext)
+	}
+
+	// Одиночный сжатый лог: имя члена — имя файла без .gz
+	member := filepath.Base(filePath)
+	if strings.EqualFold(filepath.Ext(member), ".gz") {
+		member = member[:len(member)-len(".gz")]
+	}
+	if !matchesExt(member, ext) {
+		return nil, nil
+	}
+
+	src := newSourceResult(filepath.Join(filePath, member), member)
+	return []sourceResult{src}, p.parseStream(br, src.name, src.result)
+}
+
+// parseTar парсит все подходящие файлы из tar архива
+func (p *Parser) parseTar(r io.Reader, filePath, ext string) ([]sourceResult, error) {
+	var sources []sourceResult
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return sources, nil
+		}
+		if err != nil {
+			return sources, err
+		}
+		if hdr.Typeflag != tar.TypeReg || !matchesExt(hdr.Name, ext) {
+			continue
+		}
+
+		src := newSourceResult(filepath.Join(filePath, hdr.Name), path.Base(hdr.Name))
+		if err := p.parseStream(tr, src.name, src.result); err != nil {
+			return sources, err
+		}
+		sources = append(sources, src)
+	}
+}
+
+// parseZip парсит все подходящие файлы из zip архива
+func (p *Parser) parseZip(f *os.File, filePath, ext string) ([]sourceResult, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(f, info.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*zip.File, 0, len(zr.File))
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() || !matchesExt(zf.Name, ext) {
+			continue
+		}
+		members = append(members, zf)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Name < members[j].Name
+	})
+
+	sources := make([]sourceResult, 0, len(members))
+	for _, zf := range members {
+		src := newSourceResult(filepath.Join(filePath, zf.Name), path.Base(zf.Name))
+		if err := p.parseZipMember(zf, src.name, src.result); err != nil {
+			return sources, err
+		}
+		sources = append(sources, src)
+	}
+	return sources, nil
+}
+
+// parseZipMember парсит один член zip архива; name — имя источника для матчей
+func (p *Parser) parseZipMember(zf *zip.File, name string, result *ParseResult) error {
+	rc, err := zf.Open()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+
+	return p.parseStream(rc, name, result)
+}
+
+// newSourceResult создает пустой результат для лога; дата берется из имени члена member
+func newSourceResult(name, member string) sourceResult {
+	date, ok := dateFromFileName(member)
+	return sourceResult{
+		name:    name,
+		date:    date,
+		hasDate: ok,
+		result:  newParseResult(),
+	}
+}
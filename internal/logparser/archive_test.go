@@ -0,0 +1,179 @@
+package logparser
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// archiveTestLogs are the member logs used in archive tests, keyed by member name
+var archiveTestLogs = map[string]string{
+	"2025_09_01_180000.log": strings.ReplaceAll(testMatchLog, "m4a1", "ak47"),
+	"2025_09_05_180000.log": testMatchLog,
+	"2025_09_12_180000.log": strings.ReplaceAll(testMatchLog, "m4a1", "famas"),
+}
+
+func writeTestFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// TestParsePaths_Archives checks that gzip, zip and tar.gz members parse like plain files
+// stripSourceHashes removes the "@<hash>" suffix of match IDs (Match.ID and every MatchID field) in place
+func stripSourceHashes(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if (field.Name == "ID" || field.Name == "MatchID") && field.Type.Kind() == reflect.String {
+				if id, _, ok := strings.Cut(v.Field(i).String(), "@"); ok {
+					v.Field(i).SetString(id)
+				}
+				continue
+			}
+			if field.IsExported() {
+				stripSourceHashes(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			stripSourceHashes(v.Index(i))
+		}
+	}
+}
+
+// TestParsePaths_SameNameSources checks that same-named logs from different directories get different match IDs
+func TestParsePaths_SameNameSources(t *testing.T) {
+	root := t.TempDir()
+	for _, server := range []string{"office", "garage"} {
+		writeTestFile(t, filepath.Join(root, server, "2025_09_05_180000.log"), []byte(testMatchLog))
+	}
+
+	result, err := New().ParsePaths([]string{filepath.Join(root, "office"), filepath.Join(root, "garage")}, ".log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ids := make(map[string]bool)
+	for _, match := range result.Matches {
+		if !strings.HasPrefix(match.ID, "2025_09_05_180000.log#") || ids[match.ID] {
+			t.Errorf("Expected a unique match ID for %s, got %q", match.Source, match.ID)
+		}
+		ids[match.ID] = true
+	}
+	if len(ids) != 2 {
+		t.Fatalf("Expected 2 matches from two servers, got %d", len(ids))
+	}
+	rounds := make(map[string]int)
+	for _, round := range result.RoundStats {
+		rounds[round.MatchID]++
+	}
+	for id, n := range rounds {
+		if !ids[id] || n != 1 {
+			t.Errorf("Expected one round per match, got %d rounds for %q", n, id)
+		}
+	}
+}
+
+func TestParsePaths_Archives(t *testing.T) {
+	plainDir := t.TempDir()
+	for name, content := range archiveTestLogs {
+		writeTestFile(t, filepath.Join(plainDir, name), []byte(content))
+	}
+
+	archiveDir := t.TempDir()
+
+	// 2025_09_01 — single gzip file
+	name := "2025_09_01_180000.log"
+	writeTestFile(t, filepath.Join(archiveDir, "old", name+".gz"), gzipBytes(t, []byte(archiveTestLogs[name])))
+
+	// 2025_09_05 — zip bundle with an extra non-log member
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	for member, content := range map[string]string{
+		"logs/2025_09_05_180000.log": archiveTestLogs["2025_09_05_180000.log"],
+		"logs/readme.txt":            testMatchLog,
+	} {
+		w, err := zw.Create(member)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(archiveDir, "bundle.zip"), zipBuf.Bytes())
+
+	// 2025_09_12 — tar.gz archive passed explicitly
+	var tarBuf bytes.Buffer
+	tw := tar.NewWriter(&tarBuf)
+	name = "2025_09_12_180000.log"
+	content := []byte(archiveTestLogs[name])
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tarPath := filepath.Join(t.TempDir(), "season.tar.gz")
+	writeTestFile(t, tarPath, gzipBytes(t, tarBuf.Bytes()))
+
+	want, err := New().ParseDirectory(plainDir, ".log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := New().ParsePaths([]string{archiveDir, tarPath}, ".log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Sources and the path hash in match IDs differ by design; the rest of the IDs comes from member names and must match
+	for _, result := range []*ParseResult{want, got} {
+		stripSourceHashes(reflect.ValueOf(result).Elem())
+		for i := range result.Matches {
+			result.Matches[i].Source = ""
+		}
+		for i := range result.DroppedMatches {
+			result.DroppedMatches[i].Source = ""
+		}
+		for i := range result.Diagnostics.Files {
+			result.Diagnostics.Files[i].Source = ""
+		}
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Archive result differs from plain files: got %d kills, want %d", len(got.KillEvents), len(want.KillEvents))
+	}
+	if got.StartDate != "01-09-2025" || got.EndDate != "12-09-2025" {
+		t.Errorf("Expected dates from member names, got %s — %s", got.StartDate, got.EndDate)
+	}
+}
@@ -0,0 +1,244 @@
+package logparser
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// cacheFormatVersion — версия формата файла кэша
+const cacheFormatVersion = 1
+
+// cacheVersion объединяет версии формата, парсера и формулы EPI:
+// изменение любой из них делает старый кэш недействительным
+var cacheVersion = fmt.Sprintf("%d.%d.%d", cacheFormatVersion, ParserVersion, EPIVersion)
+
+// cacheHeader описывает, из какого файла и с какими настройками получен кэш
+type cacheHeader struct {
+	Version     string
+	Path        string
+	Size        int64
+	ModTime     time.Time
+	Hash        string // SHA-256 содержимого файла
+	Ext         string // Фильтр членов архивов
+	KeepPartial bool
+	Handlers    string // Включённые обработчики строк (Parser.Handlers)
+	ClutchEPI   bool   // SetEventClutchEPI
+}
+
+// cachedSource — sourceResult в виде, пригодном для gob
+type cachedSource struct {
+	Name    string
+	Date    time.Time
+	HasDate bool
+	Result  *ParseResult
+	Weapons []string // WeaponSet: gob не кодирует map[string]struct{}
+}
+
+// SetCacheDir включает кэш результатов парсинга по файлам в директории dir ("" — выключить).
+// Файл берётся из кэша, если совпали путь, размер и время изменения (или SHA-256 содержимого),
+// фильтр ext, SetKeepPartial, SetEventClutchEPI, набор включённых обработчиков строк и версии ParserVersion и EPIVersion.
+// Внешний обработчик, который поменял разбор, должен поменять и имя — иначе кэш его не заметит.
+func (p *Parser) SetCacheDir(dir string) {
+	p.cacheDir = dir
+}
+
+// parseSourceCached парсит файл через кэш. Ошибки кэша не фатальны: файл просто парсится заново.
+func (p *Parser) parseSourceCached(filePath, ext string) ([]sourceResult, error) {
+	if p.cacheDir == "" {
+		return p.parseSource(filePath, ext)
+	}
+
+	header, err := p.cacheHeaderFor(filePath, ext)
+	if err != nil {
+		return p.parseSource(filePath, ext)
+	}
+	cachePath := p.cachePath(header.Path)
+
+	if sources, ok := readCache(cachePath, &header); ok {
+		return sources, nil
+	}
+
+	sources, err := p.parseSource(filePath, ext)
+	if err != nil {
+		return sources, err
+	}
+	if header.Hash == "" {
+		header.Hash, err = hashFile(filePath)
+		if err != nil {
+			return sources, nil
+		}
+	}
+	_ = writeCache(cachePath, header, sources)
+	return sources, nil
+}
+
+// cacheHeaderFor собирает заголовок кэша для файла без чтения его содержимого
+func (p *Parser) cacheHeaderFor(filePath, ext string) (cacheHeader, error) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return cacheHeader{}, err
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return cacheHeader{}, err
+	}
+	return cacheHeader{
+		Version:     cacheVersion,
+		Path:        absPath,
+		Size:        info.Size(),
+		ModTime:     info.ModTime().UTC(),
+		Ext:         ext,
+		KeepPartial: p.keepPartial,
+		Handlers:    p.handlers.fingerprint(),
+		ClutchEPI:   p.clutchEPI,
+	}, nil
+}
+
+// cachePath возвращает путь файла кэша для лога absPath: один файл кэша на лог
+func (p *Parser) cachePath(absPath string) string {
+	sum := sha256.Sum256([]byte(absPath))
+	return filepath.Join(p.cacheDir, hex.EncodeToString(sum[:16])+".cache")
+}
+
+// hashFile считает SHA-256 содержимого файла
+func hashFile(filePath string) (string, error) {
+	f, err := os.Open(filePath) // #nosec G304 - path is controlled by user input for log parsing
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// readCache читает кэш и проверяет, что он получен из того же файла с теми же настройками.
+// Если размер совпал, а время изменения нет (файл скопирован или тронут), сравнивается хэш содержимого;
+// посчитанный хэш сохраняется в want, чтобы не считать его повторно при записи кэша.
+func readCache(cachePath string, want *cacheHeader) ([]sourceResult, bool) {
+	f, err := os.Open(cachePath) // #nosec G304 - path is built from the cache directory
+	if err != nil {
+		return nil, false
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, false
+	}
+	dec := gob.NewDecoder(gz)
+
+	var got cacheHeader
+	if err := dec.Decode(&got); err != nil {
+		return nil, false
+	}
+	if got.Version != want.Version || got.Path != want.Path || got.Size != want.Size ||
+		got.Ext != want.Ext || got.KeepPartial != want.KeepPartial || got.Handlers != want.Handlers ||
+		got.ClutchEPI != want.ClutchEPI {
+		return nil, false
+	}
+	if !got.ModTime.Equal(want.ModTime) {
+		hash, err := hashFile(want.Path)
+		if err != nil {
+			return nil, false
+		}
+		want.Hash = hash
+		if hash != got.Hash {
+			return nil, false
+		}
+	}
+
+	var cached []cachedSource
+	if err := dec.Decode(&cached); err != nil {
+		return nil, false
+	}
+
+	sources := make([]sourceResult, len(cached))
+	for i, src := range cached {
+		result := newParseResult()
+		result.merge(src.Result)
+		result.StartDate, result.EndDate = src.Result.StartDate, src.Result.EndDate
+		for _, weapon := range src.Weapons {
+			result.WeaponSet[weapon] = struct{}{}
+		}
+		sources[i] = sourceResult{
+			name:    src.Name,
+			date:    src.Date,
+			hasDate: src.HasDate,
+			result:  result,
+		}
+	}
+	return sources, true
+}
+
+// writeCache атомарно записывает кэш: заголовок, затем результаты. Файл сжимается gzip.
+func writeCache(cachePath string, header cacheHeader, sources []sourceResult) error {
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err != nil {
+		return err
+	}
+
+	cached := make([]cachedSource, len(sources))
+	for i, src := range sources {
+		result := *src.result
+		result.WeaponSet = nil
+		cached[i] = cachedSource{
+			Name:    src.name,
+			Date:    src.date,
+			HasDate: src.hasDate,
+			Result:  &result,
+			Weapons: sortedWeapons(src.result.WeaponSet),
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(cachePath), ".cache-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	gz := gzip.NewWriter(tmp)
+	enc := gob.NewEncoder(gz)
+	if err := enc.Encode(header); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := enc.Encode(cached); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), cachePath)
+}
+
+// sortedWeapons возвращает оружие из WeaponSet в стабильном порядке
+func sortedWeapons(set map[string]struct{}) []string {
+	weapons := make([]string, 0, len(set))
+	for weapon := range set {
+		weapons = append(weapons, weapon)
+	}
+	sort.Strings(weapons)
+	return weapons
+}
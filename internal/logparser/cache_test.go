@@ -0,0 +1,104 @@
+package logparser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseWithCache parses dir with a fresh parser that uses cacheDir
+func parseWithCache(t *testing.T, dir, cacheDir string) *ParseResult {
+	t.Helper()
+	p := New()
+	p.SetCacheDir(cacheDir)
+	result, err := p.ParseDirectory(dir, ".log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return result
+}
+
+// TestParseDirectory_Cache checks that unchanged files come from the cache and changed ones are reparsed
+func TestParseDirectory_Cache(t *testing.T) {
+	dir := t.TempDir()
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	for name, content := range archiveTestLogs {
+		writeTestFile(t, filepath.Join(dir, name), []byte(content))
+	}
+
+	want, err := New().ParseDirectory(dir, ".log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := parseWithCache(t, dir, cacheDir); !reflect.DeepEqual(want, got) {
+		t.Errorf("First cached run differs from plain parse")
+	}
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil || len(entries) != len(archiveTestLogs) {
+		t.Fatalf("Expected %d cache files, got %d (%v)", len(archiveTestLogs), len(entries), err)
+	}
+	if got := parseWithCache(t, dir, cacheDir); !reflect.DeepEqual(want, got) {
+		t.Errorf("Result loaded from cache differs from plain parse")
+	}
+
+	// Same size and mtime: the file is trusted to be unchanged and the stale result is served from cache
+	path := filepath.Join(dir, "2025_09_05_180000.log")
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, path, []byte(strings.ReplaceAll(testMatchLog, "m4a1", "ak47")))
+	if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if got := parseWithCache(t, dir, cacheDir); !reflect.DeepEqual(want, got) {
+		t.Errorf("Expected cached result for file with unchanged size and mtime")
+	}
+
+	// A changed mtime with changed content forces a reparse
+	later := info.ModTime().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	got := parseWithCache(t, dir, cacheDir)
+	if got.KillEvents[1].Weapon != "ak47" {
+		t.Errorf("Expected reparse of modified file, got kills %+v", got.KillEvents)
+	}
+
+	// A new parser version invalidates the cache even for a file with the same size and mtime
+	writeTestFile(t, path, []byte(testMatchLog))
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	oldVersion := cacheVersion
+	cacheVersion = "test"
+	defer func() {
+		cacheVersion = oldVersion
+	}()
+	if got := parseWithCache(t, dir, cacheDir); !reflect.DeepEqual(want, got) {
+		t.Errorf("Expected reparse after version change, got kills %+v", got.KillEvents)
+	}
+
+	// Switching the event clutch bonus changes ratings, so it invalidates the cache too:
+	// the stale content with the same size and mtime is served until SetEventClutchEPI flips
+	writeTestFile(t, path, []byte(strings.ReplaceAll(testMatchLog, "m4a1", "ak47")))
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got := parseWithCache(t, dir, cacheDir); got.KillEvents[1].Weapon != "m4a1" {
+		t.Fatalf("Expected cached result before the clutch mode switch, got kills %+v", got.KillEvents)
+	}
+	p := New()
+	p.SetCacheDir(cacheDir)
+	p.SetEventClutchEPI(true)
+	got, err = p.ParseDirectory(dir, ".log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.KillEvents[1].Weapon != "ak47" {
+		t.Errorf("Expected reparse after SetEventClutchEPI(true), got kills %+v", got.KillEvents)
+	}
+}
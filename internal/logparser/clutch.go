@@ -0,0 +1,68 @@
+package logparser
+
+// MaxClutchEnemies — клатчи считаются от 1v1 до 1v5; больше противников засчитывается как 1v5
+const MaxClutchEnemies = 5
+
+// closeRoundClutches проигрывает смерти раунда по порядку и проставляет PlayerStats.ClutchVs игроку,
+// который остался последним живым в своей команде, — число живых противников в этот момент.
+// Составы команд берутся из JSON блока; команда, начавшая раунд одним игроком, в клатче не считается.
+// Победу в клатче определяет round.Winner, который проставляется позже.
+func closeRoundClutches(round *RoundStats, kills []KillEvent, shame []ShameEvent) {
+	if round == nil {
+		return
+	}
+
+	alive := make(map[int64]int, len(round.Players)) // AccountID -> индекс в round.Players
+	count := make(map[int]int, 2)                    // команда -> живых игроков
+	for i := range round.Players {
+		ps := &round.Players[i]
+		ps.ClutchVs = 0
+		if ps.Team != 2 && ps.Team != 3 {
+			continue
+		}
+		// Боты (AccountID 0) не отслеживаются по смертям, но держат команду живой — ложного клатча не будет
+		if ps.AccountID != 0 {
+			alive[ps.AccountID] = i
+		}
+		count[ps.Team]++
+	}
+	started := map[int]int{2: count[2], 3: count[3]}
+	clutch := make(map[int]bool, 2)
+
+	die := func(sid string) {
+		id, ok := AccountIDFromSID(sid)
+		if !ok {
+			return
+		}
+		i, ok := alive[id]
+		if !ok {
+			return
+		}
+		delete(alive, id)
+		count[round.Players[i].Team]--
+
+		for _, team := range [...]int{2, 3} {
+			enemy := 5 - team // 2 <-> 3
+			if clutch[team] || started[team] < 2 || count[team] != 1 || count[enemy] == 0 {
+				continue
+			}
+			clutch[team] = true
+			for _, j := range alive {
+				if round.Players[j].Team == team {
+					round.Players[j].ClutchVs = min(count[enemy], MaxClutchEnemies)
+				}
+			}
+		}
+	}
+
+	// Убийства и позорные смерти идут в порядке времени; при равном времени убийства раньше
+	for i, j := 0, 0; i < len(kills) || j < len(shame); {
+		if j < len(shame) && (i == len(kills) || shame[j].Time.Before(kills[i].Time)) {
+			die(shame[j].VictimSID)
+			j++
+			continue
+		}
+		die(kills[i].VictimSID)
+		i++
+	}
+}
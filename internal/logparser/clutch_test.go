@@ -0,0 +1,90 @@
+package logparser
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+// clutchRound builds a round with CT accounts 1..ct and T accounts 11..10+t
+func clutchRound(ct, t int) *RoundStats {
+	round := &RoundStats{}
+	for i := 1; i <= ct; i++ {
+		round.Players = append(round.Players, PlayerStats{AccountID: int64(i), Team: 3})
+	}
+	for i := 11; i <= 10+t; i++ {
+		round.Players = append(round.Players, PlayerStats{AccountID: int64(i), Team: 2})
+	}
+	return round
+}
+
+// deathsAt returns kill events for the given victim account IDs, one second apart
+func deathsAt(start time.Time, victims ...int) []KillEvent {
+	kills := make([]KillEvent, len(victims))
+	for i, v := range victims {
+		kills[i] = KillEvent{VictimSID: fmt.Sprintf("[U:1:%d]", v), Time: start.Add(time.Duration(i) * time.Second)}
+	}
+	return kills
+}
+
+// TestCloseRoundClutches checks which player becomes the clutcher and against how many enemies
+func TestCloseRoundClutches(t *testing.T) {
+	start := time.Date(2025, 9, 5, 18, 0, 0, 0, time.UTC)
+	clutchOf := func(round *RoundStats) map[int64]int {
+		got := make(map[int64]int)
+		for _, ps := range round.Players {
+			if ps.ClutchVs > 0 {
+				got[ps.AccountID] = ps.ClutchVs
+			}
+		}
+		return got
+	}
+
+	// Four CTs die first: CT 5 is left alone against five Ts, then kills two of them.
+	// Later T 13 is alone against CT 5 — a 1v1 for the other side.
+	round := clutchRound(5, 5)
+	closeRoundClutches(round, deathsAt(start, 1, 2, 3, 4, 11, 12, 14, 15), nil)
+	if got := clutchOf(round); len(got) != 2 || got[5] != 5 || got[13] != 1 {
+		t.Errorf("Unexpected clutches: %v", got)
+	}
+
+	// A fall (shame event) between kills also counts as a death: CT 3 ends up 1v2
+	round = clutchRound(3, 3)
+	shame := []ShameEvent{{VictimSID: "[U:1:2]", Time: start.Add(1500 * time.Millisecond)}}
+	closeRoundClutches(round, deathsAt(start, 1, 11), shame)
+	if got := clutchOf(round); len(got) != 1 || got[3] != 2 {
+		t.Errorf("Unexpected clutches with a shame death: %v", got)
+	}
+
+	// The enemy team is already dead when the team is reduced to one: no clutch
+	round = clutchRound(2, 1)
+	closeRoundClutches(round, deathsAt(start, 11, 1), nil)
+	if got := clutchOf(round); len(got) != 0 {
+		t.Errorf("Expected no clutch after the enemy team is dead, got %v", got)
+	}
+
+	// A team that starts alone is not a clutch, but its opponent reduced to one is
+	round = clutchRound(1, 2)
+	closeRoundClutches(round, deathsAt(start, 11), nil)
+	if got := clutchOf(round); len(got) != 1 || got[12] != 1 {
+		t.Errorf("Expected only T 12 in a 1v1, got %v", got)
+	}
+}
+
+// TestCalculateRoundRatings_EventClutch checks that the event clutch mode rewards a real 1v3 win
+func TestCalculateRoundRatings_EventClutch(t *testing.T) {
+	round := clutchRound(5, 5)
+	round.Winner = 3
+	round.Players[4].Damage, round.Players[4].Kills, round.Players[4].ClutchVs = 300, 3, 3
+
+	calculateRoundRatings(round, false)
+	byCount := round.Players[4].Rating
+	calculateRoundRatings(round, true)
+	byEvents := round.Players[4].Rating
+
+	// Final teams are 5v5, so the count mode gives no clutch bonus; the event mode adds 3*5%
+	if math.Abs(byEvents/byCount-(1.0+0.10+0.1+0.15)/(1.0+0.10+0.1)) > 1e-9 {
+		t.Errorf("Expected a 15%% clutch bonus, got %.3f vs %.3f", byEvents, byCount)
+	}
+}
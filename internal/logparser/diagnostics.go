@@ -0,0 +1,123 @@
+package logparser
+
+// maxDiagnosticSamples — сколько примеров проблемных строк хранится на файл
+const maxDiagnosticSamples = 10
+
+// maxSampleLength — длина, до которой обрезаются примеры строк
+const maxSampleLength = 300
+
+// Типы строк лога для счётчиков диагностики
+const (
+	LineOutsideMatch = "outside_match" // Строка вне матча (разминка, между матчами) — не разбирается
+	LineMatchStart   = "match_start"
+	LineGameOver     = "game_over"
+	LineRestart      = "restart"
+	LineMapChange    = "map_change"
+	LineWarmup       = "warmup"
+	LineServerCvar   = "server_cvar"
+	LineJSON         = "json" // Строки JSON_BEGIN … JSON_END блоков
+	LineRoundEnd     = "round_end"
+	LineKill         = "kill" // Включая тимкиллы: они уходят в ShameEvents
+	LineSuicide      = "suicide"
+	LineAttack       = "attack"
+	LineFlash        = "flash"
+	LineGrenade      = "grenade"
+	LineBomb         = "bomb"
+	LinePurchase     = "purchase"
+	LineHostage      = "hostage"
+	LineDefuse       = "defuse"
+)
+
+// playerStatsFields — имена полей строки "player_N" JSON блока в порядке из "fields"
+var playerStatsFields = []string{
+	"accountid", "team", "money", "kills", "deaths", "assists", "dmg", "hsp", "kdr", "adr",
+	"mvp", "ef", "ud", "3k", "4k", "5k", "clutchk", "firstk", "pistolk", "sniperk",
+	"blindk", "bombk", "firedmg", "uniquek", "dinks", "chickenk",
+}
+
+// Diagnostics — отчёт о разборе логов: помогает заметить, что обновление CS2 поменяло формат
+type Diagnostics struct {
+	Files []FileDiagnostics
+}
+
+// FileDiagnostics — счётчики разбора одного лога (файла или члена архива)
+type FileDiagnostics struct {
+	Source            string
+	Lines             int            // Всего строк
+	LineTypes         map[string]int // Распознанные строки по типам: LineKill, LineJSON, ...
+	Unmatched         int            // Строки внутри матча, которые не распознал ни один шаблон
+	UnmatchedSamples  []string       `json:",omitempty"`
+	TruncatedJSON     int            // JSON_BEGIN блоки, оборванные без JSON_END
+	ShortPlayerRows   int            // Строки "player_N" с меньшим числом полей, чем в playerStatsFields
+	ConversionErrors  int            // Поля, которые не удалось преобразовать в число (остались нулевыми)
+	ConversionSamples []string       `json:",omitempty"` // Примеры вида "поле=значение"
+}
+
+// newFileDiagnostics создает пустую диагностику для источника source
+func newFileDiagnostics(source string) *FileDiagnostics {
+	return &FileDiagnostics{
+		Source:    source,
+		LineTypes: make(map[string]int),
+	}
+}
+
+// recognized учитывает распознанную строку типа lineType
+func (d *FileDiagnostics) recognized(lineType string) {
+	if d == nil {
+		return
+	}
+	d.LineTypes[lineType]++
+}
+
+// unmatched учитывает нераспознанную строку
+func (d *FileDiagnostics) unmatched(line string) {
+	if d == nil {
+		return
+	}
+	d.Unmatched++
+	d.UnmatchedSamples = appendSample(d.UnmatchedSamples, line)
+}
+
+// conversionError учитывает поле field со значением value, которое не удалось разобрать
+func (d *FileDiagnostics) conversionError(field, value string) {
+	if d == nil {
+		return
+	}
+	d.ConversionErrors++
+	d.ConversionSamples = appendSample(d.ConversionSamples, field+"="+value)
+}
+
+// shortPlayerRow учитывает слишком короткую строку игрока
+func (d *FileDiagnostics) shortPlayerRow() {
+	if d == nil {
+		return
+	}
+	d.ShortPlayerRows++
+}
+
+// appendSample добавляет пример, пока их меньше maxDiagnosticSamples
+func appendSample(samples []string, sample string) []string {
+	if len(samples) >= maxDiagnosticSamples {
+		return samples
+	}
+	if len(sample) > maxSampleLength {
+		sample = sample[:maxSampleLength] + "…"
+	}
+	return append(samples, sample)
+}
+
+// Totals суммирует счётчики всех файлов
+func (d Diagnostics) Totals() FileDiagnostics {
+	total := FileDiagnostics{LineTypes: make(map[string]int)}
+	for _, file := range d.Files {
+		total.Lines += file.Lines
+		total.Unmatched += file.Unmatched
+		total.TruncatedJSON += file.TruncatedJSON
+		total.ShortPlayerRows += file.ShortPlayerRows
+		total.ConversionErrors += file.ConversionErrors
+		for lineType, n := range file.LineTypes {
+			total.LineTypes[lineType] += n
+		}
+	}
+	return total
+}
@@ -0,0 +1,62 @@
+package logparser
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseReader_Diagnostics checks per-file counters for recognised, unmatched and malformed lines
+func TestParseReader_Diagnostics(t *testing.T) {
+	log := `L 09/05/2025 - 18:00:00: "Ghost<2><[U:1:100]><CT>" say "warmup"
+L 09/05/2025 - 18:01:00: World triggered "Match_Start" on "de_dust2"
+L 09/05/2025 - 18:01:30: "Alice<2><[U:1:100]><CT>" [0 0 0] killed "Bob<3><[U:1:200]><TERRORIST>" [0 0 0] with "ak47"
+L 09/05/2025 - 18:01:40: "Alice<2><[U:1:100]><CT>" did something new in CS2
+L 09/05/2025 - 18:02:00: JSON_BEGIN{
+L 09/05/2025 - 18:02:00: "round_number" : "1",
+L 09/05/2025 - 18:02:00: "score_ct" : "one",
+L 09/05/2025 - 18:02:00: "player_0" : "                   100,      3,   4200,      x,      0,      0,    100,   0.00,   0.00,    100,      1,      0,      0,      0,      0,      0,      0,      1,      0,      0,      0,      0,      0,      1,      0,      0",
+L 09/05/2025 - 18:02:00: "player_1" : "                   200,      2,   3100"
+L 09/05/2025 - 18:02:00: }}
+L 09/05/2025 - 18:02:00: JSON_END
+L 09/05/2025 - 18:03:00: JSON_BEGIN{
+L 09/05/2025 - 18:03:00: "round_number" : "2",
+L 09/05/2025 - 18:30:00: Game Over: competitive de_dust2 score 13:6 after 28 min
+`
+	result, err := New().ParseReader(strings.NewReader(log), "2025_09_05_180000.log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Diagnostics.Files) != 1 {
+		t.Fatalf("Expected diagnostics for 1 file, got %d", len(result.Diagnostics.Files))
+	}
+	diag := result.Diagnostics.Files[0]
+	if diag.Source != "2025_09_05_180000.log" || diag.Lines != 14 {
+		t.Errorf("Unexpected source or line count: %q, %d", diag.Source, diag.Lines)
+	}
+	if diag.LineTypes[LineKill] != 1 || diag.LineTypes[LineJSON] != 9 || diag.LineTypes[LineOutsideMatch] != 1 {
+		t.Errorf("Unexpected line types: %v", diag.LineTypes)
+	}
+	if diag.Unmatched != 1 || len(diag.UnmatchedSamples) != 1 || !strings.Contains(diag.UnmatchedSamples[0], "did something new") {
+		t.Errorf("Unexpected unmatched lines: %d %v", diag.Unmatched, diag.UnmatchedSamples)
+	}
+	if diag.TruncatedJSON != 1 {
+		t.Errorf("Expected 1 truncated JSON block, got %d", diag.TruncatedJSON)
+	}
+	if diag.ShortPlayerRows != 1 {
+		t.Errorf("Expected 1 short player row, got %d", diag.ShortPlayerRows)
+	}
+	if diag.ConversionErrors != 2 || strings.Join(diag.ConversionSamples, ",") != "score_ct=one,kills=x" {
+		t.Errorf("Unexpected conversion errors: %d %v", diag.ConversionErrors, diag.ConversionSamples)
+	}
+
+	// Malformed fields stay zero, the rest of the row is still used
+	if len(result.RoundStats) != 2 || len(result.RoundStats[0].Players) != 1 || result.RoundStats[0].Players[0].Money != 4200 {
+		t.Errorf("Unexpected rounds: %+v", result.RoundStats)
+	}
+
+	totals := result.Diagnostics.Totals()
+	if totals.Lines != diag.Lines || totals.LineTypes[LineKill] != 1 {
+		t.Errorf("Unexpected totals: %+v", totals)
+	}
+}
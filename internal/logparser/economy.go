@@ -0,0 +1,128 @@
+package logparser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// itemInfo — цена предмета в магазине и является ли он основным оружием
+type itemInfo struct {
+	price   int
+	primary bool
+}
+
+// itemPrices — цены магазина CS2. Ключ — название предмета из строки "purchased" без префикса weapon_.
+var itemPrices = map[string]itemInfo{
+	// Пистолеты
+	"glock":        {200, false},
+	"hkp2000":      {200, false},
+	"usp_silencer": {200, false},
+	"p250":         {300, false},
+	"elite":        {300, false},
+	"fiveseven":    {500, false},
+	"tec9":         {500, false},
+	"cz75a":        {500, false},
+	"revolver":     {600, false},
+	"deagle":       {700, false},
+
+	// Пистолеты-пулемёты
+	"mac10": {1050, true},
+	"mp9":   {1250, true},
+	"ump45": {1200, true},
+	"bizon": {1400, true},
+	"mp7":   {1500, true},
+	"mp5sd": {1500, true},
+	"p90":   {2350, true},
+
+	// Тяжёлое оружие
+	"nova":     {1050, true},
+	"sawedoff": {1100, true},
+	"mag7":     {1300, true},
+	"xm1014":   {2000, true},
+	"negev":    {1700, true},
+	"m249":     {5200, true},
+
+	// Винтовки
+	"galilar":       {1800, true},
+	"famas":         {2050, true},
+	"ak47":          {2700, true},
+	"m4a1_silencer": {2900, true},
+	"m4a1":          {3100, true},
+	"sg556":         {3000, true},
+	"aug":           {3300, true},
+	"ssg08":         {1700, true},
+	"awp":           {4750, true},
+	"g3sg1":         {5000, true},
+	"scar20":        {5000, true},
+
+	// Снаряжение
+	"taser":            {200, false},
+	"item_kevlar":      {650, false},
+	"vest":             {650, false},
+	"item_assaultsuit": {1000, false},
+	"vesthelm":         {1000, false},
+	"item_defuser":     {400, false},
+	"defuser":          {400, false},
+
+	// Гранаты
+	"decoy":        {50, false},
+	"flashbang":    {200, false},
+	"hegrenade":    {300, false},
+	"smokegrenade": {300, false},
+	"molotov":      {400, false},
+	"incgrenade":   {500, false},
+}
+
+// lookupItem возвращает цену и признак основного оружия; неизвестные предметы стоят 0
+func lookupItem(item string) itemInfo {
+	return itemPrices[strings.TrimPrefix(item, "weapon_")]
+}
+
+// closeRoundEconomy проставляет игрокам раунда траты, стоимость сохранённого оружия и выживание.
+// purchases, kills и shame — события этого раунда; carried хранит основное оружие игроков между раундами
+// матча и обновляется: купленное оружие запоминается, погибшие (в том числе от своих и от падения) его теряют.
+func closeRoundEconomy(round *RoundStats, purchases []PurchaseEvent, kills []KillEvent, shame []ShameEvent, carried map[string]string) {
+	spent := make(map[int64]int)
+	bought := make(map[string]string) // SID -> купленное в раунде основное оружие
+	for _, event := range purchases {
+		if id, ok := AccountIDFromSID(event.PlayerSID); ok {
+			spent[id] += event.Price
+		}
+		if lookupItem(event.Item).primary {
+			bought[event.PlayerSID] = event.Item
+		}
+	}
+
+	dead := make([]string, 0, len(kills)+len(shame))
+	for _, event := range kills {
+		dead = append(dead, event.VictimSID)
+	}
+	for _, event := range shame {
+		dead = append(dead, event.VictimSID)
+	}
+	killed := make(map[int64]bool)
+	for _, sid := range dead {
+		if id, ok := AccountIDFromSID(sid); ok {
+			killed[id] = true
+		}
+	}
+
+	if round != nil {
+		for i := range round.Players {
+			ps := &round.Players[i]
+			sid := "[U:1:" + strconv.FormatInt(ps.AccountID, 10) + "]"
+			ps.Spent = spent[ps.AccountID]
+			if weapon, ok := carried[sid]; ok && bought[sid] == "" {
+				ps.CarriedValue = lookupItem(weapon).price
+			}
+			ps.Survived = !killed[ps.AccountID]
+		}
+	}
+
+	for sid, weapon := range bought {
+		carried[sid] = weapon
+	}
+	for _, sid := range dead {
+		delete(carried, sid)
+	}
+}
@@ -0,0 +1,61 @@
+package logparser
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseReader_Economy checks per-round spend, carried weapons and survival
+func TestParseReader_Economy(t *testing.T) {
+	jsonBlock := func(round string) string {
+		return `L 09/05/2025 - 18:05:00: JSON_BEGIN{
+L 09/05/2025 - 18:05:00: "name" : "round_stats",
+L 09/05/2025 - 18:05:00: "round_number" : "` + round + `",
+L 09/05/2025 - 18:05:00: "map" : "de_dust2",
+L 09/05/2025 - 18:05:00: "fields" : "             accountid,   team,  money,  kills, deaths,assists,    dmg,    hsp,    kdr,    adr,    mvp,     ef,     ud,     3k,     4k,     5k,clutchk, firstk,pistolk,sniperk, blindk,  bombk,firedmg,uniquek,  dinks,chickenk",
+L 09/05/2025 - 18:05:00: "players" : {
+L 09/05/2025 - 18:05:00: "player_0" : "                   100,      2,   1000,      0,      0,      0,      0,   0.00,   0.00,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0",
+L 09/05/2025 - 18:05:00: "player_1" : "                   200,      3,    400,      0,      0,      0,      0,   0.00,   0.00,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0"
+L 09/05/2025 - 18:05:00: }}
+L 09/05/2025 - 18:05:00: JSON_END
+`
+	}
+	log := `L 09/05/2025 - 18:01:00: World triggered "Match_Start" on "de_dust2"
+L 09/05/2025 - 18:01:05: "Alice<2><[U:1:100]><TERRORIST>" purchased "ak47"
+L 09/05/2025 - 18:01:05: "Alice<2><[U:1:100]><TERRORIST>" purchased "item_assaultsuit"
+L 09/05/2025 - 18:01:06: "Bob<3><[U:1:200]><CT>" purchased "weapon_famas"
+L 09/05/2025 - 18:01:30: "Alice<2><[U:1:100]><TERRORIST>" [0 0 0] killed "Bob<3><[U:1:200]><CT>" [0 0 0] with "ak47"
+` + jsonBlock("1") + `L 09/05/2025 - 18:05:06: "Bob<3><[U:1:200]><CT>" purchased "deagle"
+L 09/05/2025 - 18:05:07: "Alice<2><[U:1:100]><TERRORIST>" purchased "flashbang"
+` + jsonBlock("2") + `L 09/05/2025 - 18:30:00: Game Over: competitive de_dust2 score 13:6 after 28 min
+`
+	result, err := New().ParseReader(strings.NewReader(log), "stdin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.PurchaseEvents) != 5 {
+		t.Fatalf("Expected 5 purchases, got %d", len(result.PurchaseEvents))
+	}
+	if result.PurchaseEvents[2].Price != 2050 || result.PurchaseEvents[3].Round != 2 {
+		t.Errorf("Unexpected purchases: %+v", result.PurchaseEvents)
+	}
+
+	first, second := result.RoundStats[0], result.RoundStats[1]
+	alice, bob := first.Players[0], first.Players[1]
+	if alice.Spent != 3700 || alice.CarriedValue != 0 || !alice.Survived {
+		t.Errorf("Unexpected round 1 economy for Alice: %+v", alice)
+	}
+	if bob.Spent != 2050 || bob.Survived {
+		t.Errorf("Unexpected round 1 economy for Bob: %+v", bob)
+	}
+
+	// Alice keeps her AK, Bob lost his FAMAS when he died
+	alice, bob = second.Players[0], second.Players[1]
+	if alice.Spent != 200 || alice.CarriedValue != 2700 {
+		t.Errorf("Unexpected round 2 economy for Alice: %+v", alice)
+	}
+	if bob.Spent != 700 || bob.CarriedValue != 0 {
+		t.Errorf("Unexpected round 2 economy for Bob: %+v", bob)
+	}
+}
@@ -0,0 +1,218 @@
+package logparser
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultFollowInterval — как часто Follow проверяет новые строки и новые файлы
+const defaultFollowInterval = time.Second
+
+// FollowOptions настраивает режим слежения за логом сервера
+type FollowOptions struct {
+	Interval time.Duration // Период опроса файла (по умолчанию 1 с)
+	StreamHooks
+}
+
+// Follow следит за самым новым логом вида YYYY_MM_DD_HHMMSS в директории dir: дочитывает его,
+// ждёт новых строк и переходит на следующий файл, когда сервер начинает новый лог.
+// Завершённые матчи добавляются в result (обычно результат ParsePaths по истории) и передаются OnMatch.
+// Матчи активного файла, которые уже есть в result, повторно не добавляются. История должна быть
+// разобрана без SetKeepPartial: иначе оборванный концом файла матч задвоится.
+// Блокирует до отмены ctx; отмена не считается ошибкой.
+func (p *Parser) Follow(ctx context.Context, dir string, result *ParseResult, opts FollowOptions) error {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultFollowInterval
+	}
+
+	current, err := waitLatestLogFile(ctx, dir, "", opts.Interval)
+	if err != nil || current == "" {
+		return err
+	}
+	known, knownDropped := forgetUnfinished(result, current)
+
+	for {
+		next, err := p.followFile(ctx, current, known, knownDropped, result, opts)
+		if err != nil || next == "" {
+			return err
+		}
+		current, known, knownDropped = next, 0, 0
+	}
+}
+
+// followFile читает файл path до появления более нового лога в той же директории.
+// known и knownDropped — сколько матчей файла уже есть в result.Matches и result.DroppedMatches.
+// Возвращает путь нового лога или "" после отмены ctx.
+func (p *Parser) followFile(ctx context.Context, path string, known, knownDropped int, result *ParseResult, opts FollowOptions) (string, error) {
+	f, err := os.Open(path) // #nosec G304 - path is controlled by user input for log parsing
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	stream := newMatchStream(p, result, path)
+	stream.skip = known
+	stream.skipDropped = knownDropped
+	stream.hooks = opts.StreamHooks
+	extendDateRange(result, path)
+	defer setFileDiagnostics(result, stream.diag)
+
+	reader := bufio.NewReader(f)
+	var partial strings.Builder // начало строки, которую сервер ещё не дописал
+	readToEOF := func() error {
+		for {
+			line, err := reader.ReadString('\n')
+			partial.WriteString(line)
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			stream.processLine(strings.TrimRight(partial.String(), "\r\n"))
+			partial.Reset()
+		}
+	}
+
+	for {
+		if err := readToEOF(); err != nil {
+			return "", err
+		}
+
+		// Дочитали до конца: новый лог означает, что сервер закрыл этот
+		next, err := latestLogFile(filepath.Dir(path))
+		if err != nil {
+			return "", err
+		}
+		if next != "" && filepath.Base(next) > filepath.Base(path) {
+			// Между чтением и проверкой сервер мог дописать в старый лог последние строки (например, Game Over)
+			if err := readToEOF(); err != nil {
+				return "", err
+			}
+			if partial.Len() > 0 {
+				stream.processLine(partial.String())
+			}
+			stream.abortMatch(AbortLogEnded)
+			return next, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", nil
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// waitLatestLogFile ждёт появления в dir лога новее after (after == "" — любого)
+func waitLatestLogFile(ctx context.Context, dir, after string, interval time.Duration) (string, error) {
+	for {
+		latest, err := latestLogFile(dir)
+		if err != nil {
+			return "", err
+		}
+		if latest != "" && filepath.Base(latest) > after {
+			return latest, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// latestLogFile возвращает самый новый лог YYYY_MM_DD_HHMMSS в dir (без обхода поддиректорий).
+// Имена с такой датой сортируются хронологически. "" — логов нет.
+func latestLogFile(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	latest := ""
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || isArchiveName(name) || !fileDateRegex.MatchString(name) {
+			continue
+		}
+		if name > latest {
+			latest = name
+		}
+	}
+	if latest == "" {
+		return "", nil
+	}
+	return filepath.Join(dir, latest), nil
+}
+
+// forgetUnfinished убирает из result.DroppedMatches матч лога path, оборванный концом файла:
+// он ещё идёт, и Follow его дочитает. Возвращает, сколько матчей лога осталось в Matches и DroppedMatches.
+// Пути сравниваются абсолютными: история могла быть разобрана по относительному пути, а слежение — по абсолютному.
+func forgetUnfinished(result *ParseResult, path string) (known, knownDropped int) {
+	path = cleanAbsPath(path)
+	for _, match := range result.Matches {
+		if cleanAbsPath(match.Source) == path {
+			known++
+		}
+	}
+
+	dropped := result.DroppedMatches[:0]
+	for _, match := range result.DroppedMatches {
+		if cleanAbsPath(match.Source) == path {
+			if match.Reason == AbortLogEnded {
+				continue
+			}
+			knownDropped++
+		}
+		dropped = append(dropped, match)
+	}
+	result.DroppedMatches = dropped
+	return known, knownDropped
+}
+
+// cleanAbsPath возвращает абсолютный путь или, если его не получить, очищенный исходный
+func cleanAbsPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return abs
+}
+
+// setFileDiagnostics добавляет в result диагностику лога, как parseStream. Файл, уже разобранный
+// в истории, Follow перечитывает с начала, поэтому его прежняя диагностика заменяется.
+func setFileDiagnostics(result *ParseResult, diag *FileDiagnostics) {
+	source := cleanAbsPath(diag.Source)
+	for i, file := range result.Diagnostics.Files {
+		if cleanAbsPath(file.Source) == source {
+			result.Diagnostics.Files[i] = *diag
+			return
+		}
+	}
+	result.Diagnostics.Files = append(result.Diagnostics.Files, *diag)
+}
+
+// extendDateRange расширяет диапазон дат result датой из имени лога path
+func extendDateRange(result *ParseResult, path string) {
+	date, ok := dateFromFileName(path)
+	if !ok {
+		return
+	}
+	formatted := date.Format("02-01-2006")
+	if result.StartDate == "" {
+		result.StartDate = formatted
+	}
+	if end, err := time.Parse("02-01-2006", result.EndDate); err != nil || date.After(end) {
+		result.EndDate = formatted
+	}
+}
@@ -0,0 +1,136 @@
+package logparser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// appendTestFile appends data to the log at path, as the server does
+func appendTestFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatalf("append %s: %v", path, err)
+	}
+}
+
+// waitMatch returns the next match passed to OnMatch or fails after a timeout
+func waitMatch(t *testing.T, matches <-chan *ParseResult) *ParseResult {
+	t.Helper()
+	select {
+	case match := <-matches:
+		return match
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a match")
+		return nil
+	}
+}
+
+// TestFollow checks that Follow adds only new matches, handles half-written lines and switches to a newer log
+func TestFollow(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "2025_09_05_180000.log")
+	writeTestFile(t, first, []byte(testMatchLog))
+
+	p := New()
+	result, err := p.ParseDirectory(dir, ".log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Matches) != 1 || len(result.DroppedMatches) != 1 {
+		t.Fatalf("Expected 1 match and 1 dropped in history, got %d and %d", len(result.Matches), len(result.DroppedMatches))
+	}
+
+	matches := make(chan *ParseResult, 4)
+	rounds := make(chan RoundStats, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Follow(ctx, dir, result, FollowOptions{
+			Interval: 10 * time.Millisecond,
+			StreamHooks: StreamHooks{
+				OnRound: func(round RoundStats) { rounds <- round },
+				OnMatch: func(match *ParseResult) { matches <- match },
+			},
+		})
+	}()
+
+	// The mirage match continues; the Game Over line arrives in two writes
+	gameOver := "L 09/05/2025 - 19:00:00: Game Over: competitive de_mirage score 13:11 after 25 min\n"
+	appendTestFile(t, first, gameOver[:30])
+	time.Sleep(50 * time.Millisecond)
+	appendTestFile(t, first, gameOver[30:])
+
+	match := waitMatch(t, matches)
+	if len(match.Matches) != 1 || match.Matches[0].Map != "de_mirage" || len(match.KillEvents) != 1 {
+		t.Fatalf("Expected the mirage match with 1 kill, got %+v", match.Matches)
+	}
+	if match.StartDate != "05-09-2025" || match.EndDate != "05-09-2025" {
+		t.Errorf("Expected the match dated from the log name, got %q — %q", match.StartDate, match.EndDate)
+	}
+
+	// A newer log ends the old one; its round is reported live
+	second := filepath.Join(dir, "2025_09_05_200000.log")
+	writeTestFile(t, second, []byte(testMatchLog))
+
+	match = waitMatch(t, matches)
+	if match.Matches[0].ID != testMatchID(second, 1) || match.Matches[0].Map != "de_dust2" {
+		t.Errorf("Expected the first match of the new log, got %s on %s", match.Matches[0].ID, match.Matches[0].Map)
+	}
+	select {
+	case round := <-rounds:
+		if round.MatchID != testMatchID(second, 1) || round.Winner != 3 {
+			t.Errorf("Expected a CT win in the new log, got %+v", round)
+		}
+	default:
+		t.Error("Expected OnRound for the new log")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Matches) != 3 {
+		t.Errorf("Expected 3 matches in result, got %d", len(result.Matches))
+	}
+	if len(result.DroppedMatches) != 0 {
+		t.Errorf("Expected the unfinished match to be taken over by Follow, got %+v", result.DroppedMatches)
+	}
+	if result.EndDate != "05-09-2025" {
+		t.Errorf("Expected EndDate 05-09-2025, got %s", result.EndDate)
+	}
+
+	// Diagnostics of the reread log replace its history entry, the new log is added
+	files := result.Diagnostics.Files
+	logLines := strings.Count(testMatchLog, "\n")
+	if len(files) != 2 || files[0].Source != first || files[0].Lines != logLines+1 || files[1].Source != second || files[1].Lines != logLines {
+		t.Errorf("Expected diagnostics of both logs with %d and %d lines, got %+v", logLines+1, logLines, files)
+	}
+}
+
+// TestForgetUnfinished_RelativePath checks that history parsed by a relative path is paired with the absolute followed path
+func TestForgetUnfinished_RelativePath(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "2025_09_05_180000.log"), []byte(testMatchLog))
+	t.Chdir(dir)
+
+	result, err := New().ParsePaths([]string{"2025_09_05_180000.log"}, ".log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	known, knownDropped := forgetUnfinished(result, filepath.Join(dir, "2025_09_05_180000.log"))
+	if known != 1 || knownDropped != 0 || len(result.DroppedMatches) != 0 {
+		t.Errorf("Expected 1 known match and the unfinished one forgotten, got %d, %d, %+v", known, knownDropped, result.DroppedMatches)
+	}
+}
@@ -0,0 +1,621 @@
+package logparser
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LogLine — строка лога внутри матча, разобранная токенизатором (tokenizer.go)
+type LogLine struct {
+	Raw         string    // Строка целиком: "L MM/DD/YYYY - HH:MM:SS: ..."
+	Message     string    // Текст события после префикса времени ("" — префикса нет)
+	Date        string    // Дата в формате YYYY-MM-DD
+	Time        time.Time // Время строки (UTC)
+	Subject     string    // Кто совершил событие: SubjectPlayer, SubjectTeam, SubjectWorld или SubjectNone
+	Actor       PlayerRef // Игрок (SubjectPlayer) или команда в Actor.Team (SubjectTeam)
+	ActorPos    Position  // Позиция игрока после ссылки на него, если она есть
+	HasActorPos bool
+	Verb        string // Глагол после субъекта: killed, attacked, blinded, threw, triggered, purchased, ...
+	Args        string // Текст после глагола
+}
+
+// EventSink принимает события, которые обработчики строк извлекли из лога текущего матча.
+// Номер раунда, ID матча и карту событиям проставляет парсер, обработчикам их заполнять не нужно.
+type EventSink interface {
+	AddKill(KillEvent)
+	AddDamage(DamageEvent)
+	AddFlash(FlashEvent)
+	AddGrenade(GrenadeEvent)
+	AddBomb(BombEvent)
+	AddPurchase(PurchaseEvent)
+	AddHostage(HostageEvent)
+	AddDefuse(DefuseEvent)
+	AddCustom(CustomEvent)
+	AddShame(ShameEvent)
+	// EndRound проставляет победителя (2=T, 3=CT) и условие победы последнему закрытому раунду
+	EndRound(winner int, condition string)
+	// LastRound возвращает номер последнего закрытого раунда (0 — раундов ещё нет)
+	LastRound() int
+}
+
+// LineHandler разбирает один вид строк лога внутри матча (семейство событий).
+// Check — дешёвая проверка токенов строки (глагол, субъект), после которой вызывается Parse.
+// Parse возвращает false, если строка всё же не подошла: тогда её получает следующий обработчик.
+type LineHandler struct {
+	Name  string // Тип строки для диагностики и включения/выключения: LineKill, LineFlash, ...
+	Check func(line *LogLine) bool
+	Parse func(line *LogLine, sink EventSink) bool
+}
+
+// HandlerRegistry — упорядоченная цепочка обработчиков строк. Строку разбирает первый
+// включённый обработчик, чьи Check и Parse её приняли. Менять реестр во время разбора нельзя.
+type HandlerRegistry struct {
+	handlers []LineHandler
+	disabled map[string]bool
+	active   []LineHandler // включённые обработчики в порядке регистрации
+}
+
+// NewHandlerRegistry создает реестр с обработчиками handlers
+func NewHandlerRegistry(handlers ...LineHandler) *HandlerRegistry {
+	r := &HandlerRegistry{disabled: make(map[string]bool)}
+	for _, h := range handlers {
+		r.Register(h)
+	}
+	return r
+}
+
+// Register добавляет обработчик в конец цепочки. Обработчик с уже занятым именем заменяет прежний
+// на его месте в цепочке — так можно переопределить встроенное семейство событий.
+func (r *HandlerRegistry) Register(h LineHandler) {
+	replaced := false
+	for i := range r.handlers {
+		if r.handlers[i].Name == h.Name {
+			r.handlers[i] = h
+			replaced = true
+		}
+	}
+	if !replaced {
+		r.handlers = append(r.handlers, h)
+	}
+	r.rebuild()
+}
+
+// Disable выключает обработчики с именами names: их строки считаются нераспознанными
+func (r *HandlerRegistry) Disable(names ...string) {
+	for _, name := range names {
+		r.disabled[name] = true
+	}
+	r.rebuild()
+}
+
+// Enable включает ранее выключенные обработчики
+func (r *HandlerRegistry) Enable(names ...string) {
+	for _, name := range names {
+		delete(r.disabled, name)
+	}
+	r.rebuild()
+}
+
+// Names возвращает имена включённых обработчиков в порядке проверки
+func (r *HandlerRegistry) Names() []string {
+	names := make([]string, len(r.active))
+	for i, h := range r.active {
+		names[i] = h.Name
+	}
+	return names
+}
+
+// rebuild пересобирает цепочку включённых обработчиков
+func (r *HandlerRegistry) rebuild() {
+	r.active = r.active[:0]
+	for _, h := range r.handlers {
+		if !r.disabled[h.Name] {
+			r.active = append(r.active, h)
+		}
+	}
+}
+
+// fingerprint описывает набор включённых обработчиков для заголовка кэша
+func (r *HandlerRegistry) fingerprint() string {
+	names := r.Names()
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// dispatch передаёт строку первому подходящему обработчику и возвращает его имя ("" — никто не принял)
+func (r *HandlerRegistry) dispatch(line *LogLine, sink EventSink) string {
+	for _, h := range r.active {
+		if h.Check != nil && !h.Check(line) {
+			continue
+		}
+		if h.Parse(line, sink) {
+			return h.Name
+		}
+	}
+	return ""
+}
+
+// matchSink складывает события в буфер текущего матча
+type matchSink struct {
+	match *ParseResult
+}
+
+func (s matchSink) AddKill(event KillEvent) {
+	s.match.KillEvents = append(s.match.KillEvents, event)
+	if event.Weapon != "" {
+		s.match.WeaponSet[event.Weapon] = struct{}{}
+	}
+}
+
+func (s matchSink) AddDamage(event DamageEvent) {
+	s.match.DamageEvents = append(s.match.DamageEvents, event)
+}
+
+func (s matchSink) AddFlash(event FlashEvent) {
+	s.match.FlashEvents = append(s.match.FlashEvents, event)
+}
+
+func (s matchSink) AddGrenade(event GrenadeEvent) {
+	s.match.GrenadeEvents = append(s.match.GrenadeEvents, event)
+}
+
+func (s matchSink) AddBomb(event BombEvent) {
+	s.match.BombEvents = append(s.match.BombEvents, event)
+}
+
+func (s matchSink) AddPurchase(event PurchaseEvent) {
+	s.match.PurchaseEvents = append(s.match.PurchaseEvents, event)
+}
+
+func (s matchSink) AddHostage(event HostageEvent) {
+	s.match.HostageEvents = append(s.match.HostageEvents, event)
+}
+
+func (s matchSink) AddDefuse(event DefuseEvent) {
+	s.match.DefuseEvents = append(s.match.DefuseEvents, event)
+}
+
+func (s matchSink) AddCustom(event CustomEvent) {
+	s.match.CustomEvents = append(s.match.CustomEvents, event)
+}
+
+func (s matchSink) AddShame(event ShameEvent) {
+	s.match.ShameEvents = append(s.match.ShameEvents, event)
+}
+
+func (s matchSink) EndRound(winner int, condition string) {
+	if len(s.match.RoundStats) == 0 {
+		return
+	}
+	last := &s.match.RoundStats[len(s.match.RoundStats)-1]
+	last.Winner = winner
+	last.WinCondition = condition
+}
+
+func (s matchSink) LastRound() int {
+	return lastRoundNumber(s.match)
+}
+
+// DefaultHandlers возвращает встроенные обработчики событий CS2 в порядке проверки
+func DefaultHandlers() []LineHandler {
+	return []LineHandler{
+		{
+			// Дефьюз, в том числе строки конца раунда "Bomb_Defused" и "Target_Bombed":
+			// они дополнительно проставляют победителя, как обработчик LineRoundEnd
+			Name: LineDefuse,
+			Check: func(line *LogLine) bool {
+				switch line.Verb {
+				case "triggered":
+					return strings.HasPrefix(line.Args, `"Begin_Bomb_Defuse_`) ||
+						strings.HasPrefix(line.Args, `"SFUI_Notice_Bomb_Defused"`) ||
+						strings.HasPrefix(line.Args, `"SFUI_Notice_Target_Bombed"`)
+				case "stopped":
+					return true
+				}
+				return false
+			},
+			Parse: parseDefuseLine,
+		},
+		{
+			Name:  LineRoundEnd,
+			Check: func(line *LogLine) bool { return line.Subject == SubjectTeam && line.Verb == "triggered" },
+			Parse: parseRoundEndLine,
+		},
+		{
+			Name:  LineKill,
+			Check: func(line *LogLine) bool { return line.Verb == "killed" && line.Subject == SubjectPlayer },
+			Parse: parseKillLine,
+		},
+		{
+			Name: LineSuicide,
+			Check: func(line *LogLine) bool {
+				return line.Subject == SubjectPlayer && (line.Verb == "committed" || line.Verb == "was")
+			},
+			Parse: parseSuicideLine,
+		},
+		{
+			Name:  LineAttack,
+			Check: func(line *LogLine) bool { return line.Verb == "attacked" && line.Subject == SubjectPlayer },
+			Parse: parseAttackLine,
+		},
+		{
+			Name:  LineFlash,
+			Check: func(line *LogLine) bool { return line.Verb == "blinded" && line.Subject == SubjectPlayer },
+			Parse: parseFlashLine,
+		},
+		{
+			Name:  LineGrenade,
+			Check: func(line *LogLine) bool { return line.Verb == "threw" && line.Subject == SubjectPlayer },
+			Parse: parseGrenadeLine,
+		},
+		{
+			Name: LineBomb,
+			Check: func(line *LogLine) bool {
+				return line.Verb == "triggered" && line.Subject == SubjectPlayer && strings.Contains(line.Args, "_The_Bomb")
+			},
+			Parse: parseBombLine,
+		},
+		{
+			Name:  LinePurchase,
+			Check: func(line *LogLine) bool { return line.Verb == "purchased" && line.Subject == SubjectPlayer },
+			Parse: parsePurchaseLine,
+		},
+		{
+			Name: LineHostage,
+			Check: func(line *LogLine) bool {
+				return line.Verb == "triggered" && line.Subject == SubjectPlayer && strings.Contains(line.Args, "_A_Hostage")
+			},
+			Parse: parseHostageLine,
+		},
+	}
+}
+
+// parseRoundEndLine проставляет победителя и условие победы последнему раунду:
+// Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")
+func parseRoundEndLine(line *LogLine, sink EventSink) bool {
+	c := lineCursor{s: line.Args}
+	notice, ok := c.quoted()
+	if !ok || !strings.HasPrefix(notice, "SFUI_Notice_") {
+		return false
+	}
+	if outcome, ok := roundOutcomes[notice]; ok {
+		sink.EndRound(outcome.winner, outcome.condition)
+	}
+	return true
+}
+
+// parseKillLine разбирает убийство:
+// "A<2><[U:1:1]><CT>" [0 0 0] killed "B<3><[U:1:2]><TERRORIST>" [0 0 0] with "ak47" (headshot)
+func parseKillLine(line *LogLine, sink EventSink) bool {
+	c := lineCursor{s: line.Args}
+	victim, ok := c.player()
+	if !ok {
+		return false
+	}
+	victimPos, victimOK := c.position()
+	if !c.literal("with") {
+		return false
+	}
+	weapon, ok := c.quoted()
+	if !ok {
+		return false
+	}
+
+	event := KillEvent{
+		KillerName: line.Actor.Name,
+		KillerSID:  line.Actor.SteamID,
+		KillerTeam: line.Actor.Team,
+		VictimName: victim.Name,
+		VictimSID:  victim.SteamID,
+		VictimTeam: victim.Team,
+		Weapon:     strings.TrimSpace(weapon),
+		Date:       line.Date,
+		Time:       line.Time,
+	}
+	if line.HasActorPos && victimOK {
+		event.KillerPos = line.ActorPos
+		event.VictimPos = victimPos
+		event.Distance = math.Round(line.ActorPos.Distance(victimPos)*10) / 10
+	}
+	applyKillModifiers(&event, c.rest())
+
+	// Убийство своего — не убийство: тимкилл и самоубийство уходят в ShameEvents
+	if kind := shameKind(line.Actor, victim); kind != "" {
+		sink.AddShame(ShameEvent{
+			Kind:       kind,
+			PlayerName: line.Actor.Name,
+			PlayerSID:  line.Actor.SteamID,
+			PlayerTeam: line.Actor.Team,
+			VictimName: victim.Name,
+			VictimSID:  victim.SteamID,
+			Weapon:     event.Weapon,
+			Date:       line.Date,
+			Time:       line.Time,
+		})
+		return true
+	}
+	sink.AddKill(event)
+	return true
+}
+
+// shameKind возвращает ShameSuicide, если игрок убил сам себя, ShameTeamKill — если союзника,
+// и "" для обычного убийства. Команды без стороны (Unassigned, пусто) тимкиллом не считаются.
+func shameKind(killer, victim PlayerRef) string {
+	switch {
+	case killer.SteamID == victim.SteamID && killer.UserID == victim.UserID:
+		return ShameSuicide
+	case killer.Team == victim.Team && (killer.Team == "CT" || killer.Team == "TERRORIST"):
+		return ShameTeamKill
+	}
+	return ""
+}
+
+// parseSuicideLine разбирает смерть без убийцы:
+// "A<2><[U:1:1]><CT>" [0 0 0] committed suicide with "world" — падение или урон от карты;
+// "A<2><[U:1:1]><CT>" [0 0 0] committed suicide with "hegrenade" — своя граната;
+// "A<2><[U:1:1]><CT>" [0 0 0] was killed by the bomb. — взрыв бомбы
+func parseSuicideLine(line *LogLine, sink EventSink) bool {
+	event := ShameEvent{
+		PlayerName: line.Actor.Name,
+		PlayerSID:  line.Actor.SteamID,
+		PlayerTeam: line.Actor.Team,
+		VictimName: line.Actor.Name,
+		VictimSID:  line.Actor.SteamID,
+		Date:       line.Date,
+		Time:       line.Time,
+	}
+	c := lineCursor{s: line.Args}
+	switch {
+	case line.Verb == "committed" && c.literal("suicide with"):
+		weapon, ok := c.quoted()
+		if !ok {
+			return false
+		}
+		event.Weapon = weapon
+		switch weapon {
+		case "world":
+			event.Kind = ShameWorld
+		case "planted_c4":
+			event.Kind = ShameBomb
+		default:
+			event.Kind = ShameSuicide
+		}
+	case line.Verb == "was" && strings.HasPrefix(line.Args, "killed by the bomb"):
+		event.Kind = ShameBomb
+	default:
+		return false
+	}
+	sink.AddShame(event)
+	return true
+}
+
+// parseAttackLine разбирает попадание:
+// "A<2><[U:1:1]><CT>" [0 0 0] attacked "B<3><[U:1:2]><TERRORIST>" [0 0 0] with "ak47"
+// (damage "27") (damage_armor "3") (health "73") (armor "97") (hitgroup "chest")
+func parseAttackLine(line *LogLine, sink EventSink) bool {
+	c := lineCursor{s: line.Args}
+	victim, ok := c.player()
+	if !ok {
+		return false
+	}
+	victimPos, _ := c.position()
+	if !c.literal("with") {
+		return false
+	}
+	weapon, ok := c.quoted()
+	if !ok {
+		return false
+	}
+
+	event := DamageEvent{
+		AttackerName: line.Actor.Name,
+		AttackerSID:  line.Actor.SteamID,
+		AttackerTeam: line.Actor.Team,
+		VictimName:   victim.Name,
+		VictimSID:    victim.SteamID,
+		VictimTeam:   victim.Team,
+		Weapon:       strings.TrimSpace(weapon),
+		Date:         line.Date,
+		Time:         line.Time,
+		AttackerPos:  line.ActorPos,
+		VictimPos:    victimPos,
+	}
+
+	// Группы (ключ "значение"); все пять обязательны
+	seen := 0
+	for c.literal("(") {
+		key := c.word()
+		value, ok := c.quoted()
+		if !ok || !c.literal(")") {
+			return false
+		}
+		n, isNumber := digits(value)
+		switch {
+		case key == "damage" && isNumber:
+			event.Damage = n
+		case key == "damage_armor" && isNumber:
+			event.DamageArmor = n
+		case key == "health" && isNumber:
+			event.Health = n
+		case key == "armor" && isNumber:
+			event.Armor = n
+		case key == "hitgroup" && value != "":
+			event.Hitgroup = value
+		default:
+			continue
+		}
+		seen++
+	}
+	if seen < 5 {
+		return false
+	}
+	sink.AddDamage(event)
+	return true
+}
+
+// parseFlashLine разбирает ослепление флешкой:
+// "B<3><[U:1:2]><TERRORIST>" blinded for 1.20 by "A<2><[U:1:1]><CT>" from flashbang entindex 276
+func parseFlashLine(line *LogLine, sink EventSink) bool {
+	c := lineCursor{s: line.Args}
+	if !c.literal("for ") {
+		return false
+	}
+	duration := c.word()
+	if !c.literal("by ") {
+		return false
+	}
+	flasher, ok := c.player()
+	if !ok || !c.literal("from flashbang") {
+		return false
+	}
+	sink.AddFlash(FlashEvent{
+		VictimName:  line.Actor.Name,
+		VictimSID:   line.Actor.SteamID,
+		VictimTeam:  line.Actor.Team,
+		FlasherName: flasher.Name,
+		FlasherSID:  flasher.SteamID,
+		FlasherTeam: flasher.Team,
+		Duration:    parseFloat(duration),
+		TeamFlash:   line.Actor.Team == flasher.Team,
+		Date:        line.Date,
+		Time:        line.Time,
+	})
+	return true
+}
+
+// parseGrenadeLine разбирает бросок гранаты: "A<2><[U:1:1]><CT>" threw hegrenade [-100 200 10].
+// Флешка дописывает entindex: threw flashbang [0 0 0] flashbang entindex 276)
+func parseGrenadeLine(line *LogLine, sink EventSink) bool {
+	c := lineCursor{s: line.Args}
+	grenade := c.word()
+	if grenade == "" {
+		return false
+	}
+	pos, ok := c.position()
+	if !ok {
+		return false
+	}
+	sink.AddGrenade(GrenadeEvent{
+		ThrowerName: line.Actor.Name,
+		ThrowerSID:  line.Actor.SteamID,
+		ThrowerTeam: line.Actor.Team,
+		Grenade:     grenade,
+		Date:        line.Date,
+		Time:        line.Time,
+		Pos:         pos,
+	})
+	return true
+}
+
+// parseBombLine разбирает закладку, выброс и подбор бомбы:
+// "A<2><[U:1:1]><TERRORIST>" triggered "Planted_The_Bomb" at bombsite A
+func parseBombLine(line *LogLine, sink EventSink) bool {
+	c := lineCursor{s: line.Args}
+	action, ok := c.quoted()
+	eventType := bombEventTypes[action]
+	if !ok || eventType == "" {
+		return false
+	}
+	site := ""
+	if c.literal("at bombsite ") {
+		site = c.word()
+	}
+	sink.AddBomb(BombEvent{
+		PlayerName: line.Actor.Name,
+		PlayerSID:  line.Actor.SteamID,
+		PlayerTeam: line.Actor.Team,
+		EventType:  eventType,
+		Site:       site,
+		Date:       line.Date,
+		Time:       line.Time,
+	})
+	return true
+}
+
+// parsePurchaseLine разбирает покупку в магазине: "A<2><[U:1:1]><CT>" purchased "m4a1_silencer"
+func parsePurchaseLine(line *LogLine, sink EventSink) bool {
+	c := lineCursor{s: line.Args}
+	item, ok := c.quoted()
+	if !ok || item == "" {
+		return false
+	}
+	sink.AddPurchase(PurchaseEvent{
+		PlayerName: line.Actor.Name,
+		PlayerSID:  line.Actor.SteamID,
+		PlayerTeam: line.Actor.Team,
+		Item:       item,
+		Price:      lookupItem(item).price,
+		Date:       line.Date,
+		Time:       line.Time,
+	})
+	return true
+}
+
+// parseHostageLine разбирает подбор, спасение и убийство заложника:
+// "A<2><[U:1:1]><CT>" triggered "Rescued_A_Hostage"
+func parseHostageLine(line *LogLine, sink EventSink) bool {
+	c := lineCursor{s: line.Args}
+	action, ok := c.quoted()
+	eventType := hostageEventTypes[action]
+	if !ok || eventType == "" {
+		return false
+	}
+	sink.AddHostage(HostageEvent{
+		PlayerName: line.Actor.Name,
+		PlayerSID:  line.Actor.SteamID,
+		PlayerTeam: line.Actor.Team,
+		EventType:  eventType,
+		Date:       line.Date,
+		Time:       line.Time,
+	})
+	return true
+}
+
+// parseDefuseLine разбирает начало, успех и отмену дефьюза, а также взрыв бомбы.
+// Успешный дефьюз и взрыв завершают раунд: у них нет игрока, зато есть раунд, который они закрыли.
+func parseDefuseLine(line *LogLine, sink EventSink) bool {
+	if line.Subject == SubjectPlayer {
+		event := DefuseEvent{
+			PlayerName: line.Actor.Name,
+			PlayerSID:  line.Actor.SteamID,
+			PlayerTeam: line.Actor.Team,
+			Date:       line.Date,
+			Time:       line.Time,
+		}
+		switch {
+		case line.Verb == "triggered" && line.Args == `"Begin_Bomb_Defuse_With_Kit"`:
+			event.EventType, event.WithKit = "begin", true
+		case line.Verb == "triggered" && line.Args == `"Begin_Bomb_Defuse_Without_Kit"`:
+			event.EventType = "begin"
+		case line.Verb == "stopped" && strings.HasPrefix(line.Args, "defusing the bomb"):
+			event.EventType = "abandoned" // Кит будет определён при обработке
+		default:
+			return false
+		}
+		sink.AddDefuse(event)
+		return true
+	}
+
+	if line.Subject != SubjectTeam {
+		return false
+	}
+	eventType := ""
+	switch {
+	case line.Actor.Team == "CT" && strings.HasPrefix(line.Args, `"SFUI_Notice_Bomb_Defused"`):
+		eventType = "success"
+	case line.Actor.Team == "TERRORIST" && strings.HasPrefix(line.Args, `"SFUI_Notice_Target_Bombed"`):
+		eventType = "failed"
+	default:
+		return false
+	}
+	parseRoundEndLine(line, sink)
+	sink.AddDefuse(DefuseEvent{
+		EventType: eventType, // Игрок и кит будут определены при обработке
+		Round:     sink.LastRound(),
+		Date:      line.Date,
+		Time:      line.Time,
+	})
+	return true
+}
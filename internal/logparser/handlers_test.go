@@ -0,0 +1,163 @@
+package logparser
+
+import (
+	"strings"
+	"testing"
+)
+
+// handlerSamples has one typical line per built-in handler
+var handlerSamples = map[string]string{
+	LineKill:     `L 09/05/2025 - 18:01:30: "Alice<2><[U:1:100]><CT>" [-100 200 10] killed "Bob<3><[U:1:200]><TERRORIST>" [50 60 10] with "ak47" (headshot penetrated)`,
+	LineSuicide:  `L 09/05/2025 - 18:01:31: "Bob<3><[U:1:200]><TERRORIST>" [0 0 900] committed suicide with "world"`,
+	LineAttack:   `L 09/05/2025 - 18:01:29: "Alice<2><[U:1:100]><CT>" [-100 200 10] attacked "Bob<3><[U:1:200]><TERRORIST>" [50 60 10] with "m4a1" (damage "27") (damage_armor "3") (health "73") (armor "97") (hitgroup "chest")`,
+	LineFlash:    `L 09/05/2025 - 18:01:22: "Bob<3><[U:1:200]><TERRORIST>" blinded for 1.20 by "Alice<2><[U:1:100]><CT>" from flashbang entindex 276`,
+	LineGrenade:  `L 09/05/2025 - 18:01:20: "Alice<2><[U:1:100]><CT>" threw flashbang [-100 200 10] flashbang entindex 276)`,
+	LineBomb:     `L 09/05/2025 - 18:01:40: "Bob<3><[U:1:200]><TERRORIST>" triggered "Planted_The_Bomb" at bombsite A`,
+	LinePurchase: `L 09/05/2025 - 18:01:05: "Alice<2><[U:1:100]><CT>" purchased "m4a1_silencer"`,
+	LineHostage:  `L 09/05/2025 - 18:01:50: "Alice<2><[U:1:100]><CT>" triggered "Rescued_A_Hostage"`,
+	LineDefuse:   `L 09/05/2025 - 18:01:45: "Alice<2><[U:1:100]><CT>" triggered "Begin_Bomb_Defuse_With_Kit"`,
+	LineRoundEnd: `L 09/05/2025 - 18:02:01: Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")`,
+}
+
+// TestHandlerRegistry_CustomAndDisabled checks that custom handlers receive lines and disabled families are skipped
+func TestHandlerRegistry_CustomAndDisabled(t *testing.T) {
+	p := New()
+	p.Handlers().Disable(LinePurchase)
+	p.Handlers().Register(LineHandler{
+		Name:  "announcement",
+		Check: func(line *LogLine) bool { return strings.HasPrefix(line.Message, `[ANNOUNCE] `) },
+		Parse: func(line *LogLine, sink EventSink) bool {
+			sink.AddCustom(CustomEvent{
+				Type:  "announcement",
+				Text:  strings.TrimPrefix(line.Message, "[ANNOUNCE] "),
+				Date:  line.Date,
+				Time:  line.Time,
+				Round: sink.LastRound(),
+			})
+			return true
+		},
+	})
+
+	log := `L 09/05/2025 - 18:01:00: World triggered "Match_Start" on "de_dust2"
+L 09/05/2025 - 18:01:05: "Alice<2><[U:1:100]><CT>" purchased "m4a1_silencer"
+L 09/05/2025 - 18:01:10: [ANNOUNCE] Pistol round, no nades
+L 09/05/2025 - 18:01:30: "Alice<2><[U:1:100]><CT>" [0 0 0] killed "Bob<3><[U:1:200]><TERRORIST>" [0 0 0] with "ak47"
+L 09/05/2025 - 18:30:00: Game Over: competitive de_dust2 score 13:6 after 28 min
+`
+	result, err := p.ParseReader(strings.NewReader(log), "2025_09_05_180000.log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.PurchaseEvents) != 0 {
+		t.Errorf("Expected disabled purchases to be skipped, got %d", len(result.PurchaseEvents))
+	}
+	if len(result.KillEvents) != 1 {
+		t.Errorf("Expected built-in kills to keep working, got %d", len(result.KillEvents))
+	}
+	if len(result.CustomEvents) != 1 {
+		t.Fatalf("Expected 1 custom event, got %d", len(result.CustomEvents))
+	}
+	event := result.CustomEvents[0]
+	if event.Text != "Pistol round, no nades" || event.MatchID != testMatchID("2025_09_05_180000.log", 1) || event.Date != "2025-09-05" {
+		t.Errorf("Unexpected custom event: %+v", event)
+	}
+	if result.Matches[0].Custom != (EventRange{0, 1}) {
+		t.Errorf("Expected custom event range {0 1}, got %+v", result.Matches[0].Custom)
+	}
+
+	diag := result.Diagnostics.Files[0]
+	if diag.LineTypes["announcement"] != 1 || diag.Unmatched != 1 {
+		t.Errorf("Expected the custom type counted and the purchase unmatched, got %+v", diag)
+	}
+}
+
+// BenchmarkHandlers measures each built-in handler on a line of its family
+func BenchmarkHandlers(b *testing.B) {
+	for _, h := range DefaultHandlers() {
+		line := tokenizeLine(handlerSamples[h.Name])
+		b.Run(h.Name, func(b *testing.B) {
+			match := newParseResult()
+			sink := matchSink{match: match}
+			for i := 0; i < b.N; i++ {
+				if !h.Check(&line) || !h.Parse(&line, sink) {
+					b.Fatalf("handler %s rejected its sample", h.Name)
+				}
+				if i%1024 == 0 {
+					*match = *newParseResult()
+				}
+			}
+		})
+	}
+}
+
+// TestParseReader_ShameDeaths checks that team kills, suicides, falls and bomb deaths bypass KillEvents
+func TestParseReader_ShameDeaths(t *testing.T) {
+	row := func(id, team string) string {
+		return `"                   ` + id + `,      ` + team + `,   1000,      0,      0,      0,      0,   0.00,   0.00,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0"`
+	}
+	log := `L 09/05/2025 - 18:01:00: World triggered "Match_Start" on "de_nuke"
+L 09/05/2025 - 18:01:10: "Alice<2><[U:1:100]><CT>" [0 0 0] killed "Eve<6><[U:1:500]><TERRORIST>" [0 0 0] with "m4a1"
+L 09/05/2025 - 18:01:20: "Alice<2><[U:1:100]><CT>" [0 0 0] killed "Bob<3><[U:1:200]><CT>" [10 0 0] with "m4a1" (headshot)
+L 09/05/2025 - 18:01:30: "Carol<4><[U:1:300]><TERRORIST>" [0 0 900] committed suicide with "world"
+L 09/05/2025 - 18:01:40: "Alice<2><[U:1:100]><CT>" [0 0 0] committed suicide with "hegrenade"
+L 09/05/2025 - 18:01:50: "Dan<5><[U:1:400]><TERRORIST>" [0 0 0] was killed by the bomb.
+L 09/05/2025 - 18:05:00: JSON_BEGIN{
+L 09/05/2025 - 18:05:00: "name" : "round_stats",
+L 09/05/2025 - 18:05:00: "round_number" : "1",
+L 09/05/2025 - 18:05:00: "map" : "de_nuke",
+L 09/05/2025 - 18:05:00: "fields" : "             accountid,   team,  money,  kills, deaths,assists,    dmg,    hsp,    kdr,    adr,    mvp,     ef,     ud,     3k,     4k,     5k,clutchk, firstk,pistolk,sniperk, blindk,  bombk,firedmg,uniquek,  dinks,chickenk",
+L 09/05/2025 - 18:05:00: "players" : {
+L 09/05/2025 - 18:05:00: "player_0" : ` + row("100", "3") + `,
+L 09/05/2025 - 18:05:00: "player_1" : ` + row("200", "3") + `,
+L 09/05/2025 - 18:05:00: "player_2" : ` + row("300", "2") + `
+L 09/05/2025 - 18:05:00: }}
+L 09/05/2025 - 18:05:00: JSON_END
+L 09/05/2025 - 18:30:00: Game Over: competitive de_nuke score 13:6 after 28 min
+`
+	result, err := New().ParseReader(strings.NewReader(log), "2025_09_05_180000.log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.KillEvents) != 1 || result.KillEvents[0].VictimName != "Eve" {
+		t.Fatalf("Expected only the kill of Eve in KillEvents, got %+v", result.KillEvents)
+	}
+	if _, ok := result.WeaponSet["hegrenade"]; ok {
+		t.Errorf("Shame weapons must not extend the weapon set")
+	}
+
+	want := []struct{ kind, player, victim, weapon string }{
+		{ShameTeamKill, "Alice", "Bob", "m4a1"},
+		{ShameWorld, "Carol", "Carol", "world"},
+		{ShameSuicide, "Alice", "Alice", "hegrenade"},
+		{ShameBomb, "Dan", "Dan", ""},
+	}
+	if len(result.ShameEvents) != len(want) {
+		t.Fatalf("Expected %d shame events, got %+v", len(want), result.ShameEvents)
+	}
+	for i, w := range want {
+		e := result.ShameEvents[i]
+		if e.Kind != w.kind || e.PlayerName != w.player || e.VictimName != w.victim || e.Weapon != w.weapon {
+			t.Errorf("Shame event %d: expected %+v, got %+v", i, w, e)
+		}
+		if e.Round != 1 || e.Map != "de_nuke" || e.MatchID != testMatchID("2025_09_05_180000.log", 1) {
+			t.Errorf("Shame event %d not stamped: %+v", i, e)
+		}
+	}
+	if result.Matches[0].Shame != (EventRange{0, 4}) {
+		t.Errorf("Expected shame range {0 4}, got %+v", result.Matches[0].Shame)
+	}
+
+	// Deaths from teammates and falls still end survival
+	for _, ps := range result.RoundStats[0].Players {
+		if ps.Survived {
+			t.Errorf("Player %d died this round but is marked as survived", ps.AccountID)
+		}
+	}
+
+	diag := result.Diagnostics.Files[0]
+	if diag.LineTypes[LineKill] != 2 || diag.LineTypes[LineSuicide] != 3 || diag.Unmatched != 0 {
+		t.Errorf("Unexpected line types: %+v", diag.LineTypes)
+	}
+}
@@ -0,0 +1,40 @@
+package logparser
+
+// StreamHooks — обработчики, которые потоковый разбор вызывает по ходу матча.
+// Все поля опциональны. Вызовы идут синхронно из Feed (или из Follow).
+type StreamHooks struct {
+	OnRound func(RoundStats)   // Завершённый раунд с победителем и рейтингами
+	OnMatch func(*ParseResult) // Завершённый матч: его события, раунды и Matches[0]
+	OnKill  func(KillEvent)    // Убийство внутри матча сразу после его строки (MatchID ещё не проставлен)
+}
+
+// Stream разбирает лог, строки которого приходят по одной: по сети (logaddress_add_http)
+// или из другого источника без файла. Не безопасен для конкурентного использования.
+type Stream struct {
+	stream *matchStream
+}
+
+// NewParseResult создает пустой результат, в который NewStream может складывать матчи
+func NewParseResult() *ParseResult {
+	return newParseResult()
+}
+
+// NewStream создает потоковый разбор источника source: завершённые матчи добавляются в result,
+// их ID строятся из source как у файлов (`<source>#<номер>`)
+func (p *Parser) NewStream(source string, result *ParseResult, hooks StreamHooks) *Stream {
+	stream := newMatchStream(p, result, source)
+	stream.hooks = hooks
+	return &Stream{stream: stream}
+}
+
+// Feed обрабатывает одну строку лога в формате `L MM/DD/YYYY - HH:MM:SS: ...` без перевода строки
+func (s *Stream) Feed(line string) {
+	s.stream.processLine(line)
+}
+
+// Close завершает источник: незавершённый матч отбрасывается (или сохраняется с SetKeepPartial)
+// с причиной AbortLogEnded, диагностика источника добавляется в result
+func (s *Stream) Close() {
+	s.stream.abortMatch(AbortLogEnded)
+	s.stream.result.Diagnostics.Files = append(s.stream.result.Diagnostics.Files, *s.stream.diag)
+}
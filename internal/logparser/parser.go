@@ -1,73 +1,102 @@
 package logparser
 
 import (
-	"bufio"
 	"math"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+// ParserVersion — версия разбора логов. Увеличьте при изменении событий, их полей или правил
+// разбора: кэш парсинга с другой версией игнорируется.
+const ParserVersion = 5
+
 // Parser отвечает за парсинг log файлов
 type Parser struct {
-	regexps *LogRegexps
+	regexps     *LogRegexps
+	handlers    *HandlerRegistry // обработчики строк внутри матча
+	workers     int              // количество файлов, которые парсятся параллельно
+	keepPartial bool             // сохранять незавершённые матчи с пометкой Partial
+	cacheDir    string           // директория кэша результатов по файлам ("" — без кэша)
+	clutchEPI   bool             // клатч-бонус EPI по реальному клатчу (PlayerStats.ClutchVs)
 }
 
 // New создает новый парсер
 func New() *Parser {
+	regexps := NewLogRegexps()
 	return &Parser{
-		regexps: NewLogRegexps(),
+		regexps:  regexps,
+		handlers: NewHandlerRegistry(DefaultHandlers()...),
+		workers:  runtime.NumCPU(),
 	}
 }
 
-// ParseDirectory парсит все файлы в директории
-func (p *Parser) ParseDirectory(dir, ext string) (*ParseResult, error) {
-	result := &ParseResult{
-		Players:      make(map[string]Player),
-		KillEvents:   []KillEvent{},
-		FlashEvents:  []FlashEvent{},
-		DefuseEvents: []DefuseEvent{},
-		WeaponSet:    make(map[string]struct{}),
-		RoundStats:   []RoundStats{},
+// Handlers возвращает реестр обработчиков строк: через него можно выключить семейства событий
+// или добавить свои (сообщения плагинов, объявления сервера). Меняйте его до начала разбора.
+func (p *Parser) Handlers() *HandlerRegistry {
+	return p.handlers
+}
+
+// SetWorkers задает количество файлов, которые парсятся параллельно (минимум 1)
+func (p *Parser) SetWorkers(n int) {
+	if n < 1 {
+		n = 1
 	}
+	p.workers = n
+}
 
-	var fileDates []time.Time
-	dateRegex := regexp.MustCompile(`(\d{4})_(\d{2})_(\d{2})_\d{6}`)
+// SetKeepPartial включает сохранение матчей, не дошедших до Game Over (рестарт, смена карты, конец лога).
+// Такие матчи помечаются Match.Partial; по умолчанию они отбрасываются и попадают в DroppedMatches.
+func (p *Parser) SetKeepPartial(keep bool) {
+	p.keepPartial = keep
+}
 
-	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if d.IsDir() {
-			return nil
-		}
-		if ext != "" && !strings.HasSuffix(strings.ToLower(d.Name()), strings.ToLower(ext)) {
-			return nil
-		}
+// SetEventClutchEPI переключает клатч-бонус EPI на реальный клатч из смертей раунда (PlayerStats.ClutchVs):
+// игрок остался последним живым против N противников и команда выиграла. По умолчанию бонус получает
+// игрок с 2+ убийствами в выигранном раунде, если его команда по итоговому составу в меньшинстве.
+func (p *Parser) SetEventClutchEPI(on bool) {
+	p.clutchEPI = on
+}
 
-		// Извлекаем дату из названия файла
-		if matches := dateRegex.FindStringSubmatch(d.Name()); matches != nil {
-			if date, err := time.Parse("2006-01-02", matches[1]+"-"+matches[2]+"-"+matches[3]); err == nil {
-				fileDates = append(fileDates, date)
-			}
-		}
+// fileDateRegex извлекает дату из имени файла лога вида YYYY_MM_DD_HHMMSS
+var fileDateRegex = regexp.MustCompile(`(\d{4})_(\d{2})_(\d{2})_\d{6}`)
 
-		return p.parseFile(path, result)
-	})
+// ParseDirectory парсит все файлы в директории
+func (p *Parser) ParseDirectory(dir, ext string) (*ParseResult, error) {
+	return p.ParsePaths([]string{dir}, ext)
+}
+
+// ParsePaths парсит набор директорий (рекурсивно), файлов и архивов.
+// Файлы парсятся параллельно, а результаты объединяются в стабильном порядке
+// (дата файла, затем путь), поэтому вывод не зависит от количества воркеров.
+// Сжатые файлы и архивы (.gz, .zip, .tar.gz) раскрываются, фильтр ext применяется к их членам.
+func (p *Parser) ParsePaths(paths []string, ext string) (*ParseResult, error) {
+	result := newParseResult()
 
+	files, err := collectLogFiles(paths, ext)
 	if err != nil {
 		return result, err
 	}
 
+	sources, err := p.parseFiles(files, ext)
+
+	var fileDates []time.Time
+	for _, src := range sources {
+		result.merge(src.result)
+
+		if src.hasDate {
+			fileDates = append(fileDates, src.date)
+		}
+	}
+
 	// Определяем диапазон дат
 	if len(fileDates) > 0 {
-		sort.Slice(fileDates, func(i, j int) bool {
-			return fileDates[i].Before(fileDates[j])
-		})
 		result.StartDate = fileDates[0].Format("02-01-2006")
 		result.EndDate = fileDates[len(fileDates)-1].Format("02-01-2006")
 	}
@@ -75,212 +104,162 @@ func (p *Parser) ParseDirectory(dir, ext string) (*ParseResult, error) {
 	return result, err
 }
 
-// MatchBounds представляет границы одного матча
-type MatchBounds struct {
-	StartLine int
-	EndLine   int
-}
+// collectLogFiles собирает файлы логов: директории обходятся рекурсивно с фильтром ext,
+// архивы и явно указанные файлы берутся всегда
+func collectLogFiles(paths []string, ext string) ([]string, error) {
+	var files []string
 
-// parseFile парсит один файл и добавляет результаты в ParseResult
-func (p *Parser) parseFile(path string, result *ParseResult) error {
-	// Сначала читаем весь файл чтобы найти границы матчей
-	lines, err := p.readLines(path)
-	if err != nil {
-		return err
-	}
-
-	// Находим границы всех матчей в файле
-	matches := p.findMatchBounds(lines)
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			if path != root && !isArchiveName(d.Name()) && !matchesExt(d.Name(), ext) {
+				return nil
+			}
 
-	// Парсим события только внутри матчей
-	for _, match := range matches {
-		roundCountBefore := len(result.RoundStats)
-		p.parseMatchLines(lines, match.StartLine, match.EndLine, result)
-		// Пересчитываем рейтинги для раундов этого матча после того как Winner проставлен
-		for i := roundCountBefore; i < len(result.RoundStats); i++ {
-			calculateRoundRatings(&result.RoundStats[i])
+			files = append(files, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return nil
+	return files, nil
 }
 
-// readLines читает все строки из файла
-func (p *Parser) readLines(path string) ([]string, error) {
-	f, err := os.Open(path) // #nosec G304 - path is controlled by user input for log parsing
-	if err != nil {
-		return nil, err
+// parseFiles парсит файлы пулом воркеров и возвращает логи, отсортированные по дате, затем по пути.
+// При ошибке возвращается первая из них вместе со всем, что удалось распарсить.
+func (p *Parser) parseFiles(files []string, ext string) ([]sourceResult, error) {
+	perFile := make([][]sourceResult, len(files))
+	errs := make([]error, len(files))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	workers := p.workers
+	if workers > len(files) {
+		workers = len(files)
 	}
-	defer func() {
-		_ = f.Close()
-	}()
-
-	var lines []string
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				perFile[i], errs[i] = p.parseSourceCached(files[i], ext)
+			}
+		}()
 	}
-	return lines, scanner.Err()
-}
 
-// findMatchBounds находит границы всех матчей в файле
-func (p *Parser) findMatchBounds(lines []string) []MatchBounds {
-	var bounds []MatchBounds
-	var currentMatchStart = -1
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
-	for i, line := range lines {
-		// Ищем начало матча
-		if p.regexps.MatchStartPattern.MatchString(line) {
-			currentMatchStart = i
-			continue
+	var sources []sourceResult
+	for _, fileSources := range perFile {
+		sources = append(sources, fileSources...)
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		if !sources[i].date.Equal(sources[j].date) {
+			return sources[i].date.Before(sources[j].date)
 		}
+		return sources[i].name < sources[j].name
+	})
 
-		// Ищем конец матча по событию "Game Over:"
-		if p.regexps.GameOverPattern.MatchString(line) && currentMatchStart != -1 {
-			bounds = append(bounds, MatchBounds{
-				StartLine: currentMatchStart,
-				EndLine:   i,
-			})
-			currentMatchStart = -1
+	for _, err := range errs {
+		if err != nil {
+			return sources, err
 		}
 	}
 
-	return bounds
+	return sources, nil
 }
 
-// parseMatchLines парсит строки матча и добавляет события в result
-func (p *Parser) parseMatchLines(lines []string, startLine, endLine int, result *ParseResult) {
-	for i := startLine; i <= endLine && i < len(lines); i++ {
-		line := lines[i]
-		date := ExtractDateFromLogLine(line)
-
-		// Проверяем, не начало ли это JSON_BEGIN блока
-		if strings.Contains(line, "JSON_BEGIN{") {
-			roundStats, consumed := p.parseJSONBlockFromLines(lines, i, date)
-			if roundStats != nil {
-				result.RoundStats = append(result.RoundStats, *roundStats)
-			}
-			i += consumed // Пропускаем обработанные строки
-			continue
-		}
-
-		// Проверяем события победы команды
-		if p.regexps.CTWinPattern.MatchString(line) {
-			// CT выиграли - проставляем победителя последнему раунду
-			if len(result.RoundStats) > 0 {
-				result.RoundStats[len(result.RoundStats)-1].Winner = 3
-			}
-			continue
-		}
-
-		if p.regexps.TerroristWinPattern.MatchString(line) {
-			// T выиграли - проставляем победителя последнему раунду
-			if len(result.RoundStats) > 0 {
-				result.RoundStats[len(result.RoundStats)-1].Winner = 2
-			}
-			continue
-		}
-
-		// Попытка парсинга убийства
-		if matches := p.regexps.KillPattern.FindStringSubmatch(line); matches != nil {
-			event := KillEvent{
-				KillerName: matches[1],
-				KillerSID:  matches[2],
-				VictimName: matches[3],
-				VictimSID:  matches[4],
-				Weapon:     strings.TrimSpace(matches[5]),
-				Date:       date,
-			}
-			result.KillEvents = append(result.KillEvents, event)
+// parseMatchLine передаёт одну строку внутри матча цепочке обработчиков, которые добавляют события
+// в match, и возвращает тип строки (LineKill, LineFlash, ...). Пустая строка — ни один обработчик не подошёл.
+func (p *Parser) parseMatchLine(line string, match *ParseResult) string {
+	tokens := tokenizeLine(line)
+	return p.handlers.dispatch(&tokens, matchSink{match: match})
+}
 
-			if event.Weapon != "" {
-				result.WeaponSet[event.Weapon] = struct{}{}
-			}
-			continue
-		}
+// lastRoundNumber возвращает номер последнего закрытого раунда матча (0 — раундов ещё нет).
+// Строка конца раунда идёт после его JSON блока, поэтому события из неё относятся к этому раунду.
+func lastRoundNumber(match *ParseResult) int {
+	if len(match.RoundStats) == 0 {
+		return 0
+	}
+	return match.RoundStats[len(match.RoundStats)-1].RoundNumber
+}
 
-		// Попытка парсинга флешки
-		if matches := p.regexps.FlashPattern.FindStringSubmatch(line); matches != nil {
-			duration, _ := strconv.ParseFloat(matches[3], 64)
-			event := FlashEvent{
-				VictimName:  matches[1],
-				VictimSID:   matches[2],
-				FlasherName: matches[4],
-				FlasherSID:  matches[5],
-				Duration:    duration,
-				Date:        date,
-			}
-			result.FlashEvents = append(result.FlashEvents, event)
-			continue
-		}
+// bombEventTypes сопоставляет действия с бомбой из лога с BombEvent.EventType
+var bombEventTypes = map[string]string{
+	"Planted_The_Bomb": "plant",
+	"Dropped_The_Bomb": "drop",
+	"Got_The_Bomb":     "pickup",
+}
 
-		// Попытка парсинга начала дефьюза
-		if matches := p.regexps.DefuseBeginPattern.FindStringSubmatch(line); matches != nil {
-			withKit := matches[3] == "With"
-			event := DefuseEvent{
-				PlayerName: matches[1],
-				PlayerSID:  matches[2],
-				WithKit:    withKit,
-				EventType:  "begin",
-				Date:       date,
-			}
-			result.DefuseEvents = append(result.DefuseEvents, event)
-			continue
-		}
+// hostageEventTypes сопоставляет действия с заложником из лога с HostageEvent.EventType
+var hostageEventTypes = map[string]string{
+	"Touched_A_Hostage": "pickup",
+	"Rescued_A_Hostage": "rescue",
+	"Killed_A_Hostage":  "kill",
+}
 
-		// Успешный дефьюз бомбы
-		if matches := p.regexps.DefuseSuccessPattern.FindStringSubmatch(line); matches != nil {
-			// Создаем событие успешного дефьюза (без указания конкретного игрока)
-			event := DefuseEvent{
-				PlayerName: "", // Будет определено при обработке
-				PlayerSID:  "",
-				WithKit:    false, // Будет определено при обработке
-				EventType:  "success",
-				Date:       date,
-			}
-			result.DefuseEvents = append(result.DefuseEvents, event)
-			continue
-		}
+// parsePosition разбирает координаты вида "-100 200 10"
+func parsePosition(s string) (Position, bool) {
+	fields := strings.Fields(s)
+	if len(fields) != 3 {
+		return Position{}, false
+	}
 
-		// Брошенный дефьюз
-		if matches := p.regexps.DefuseAbandonedPattern.FindStringSubmatch(line); matches != nil {
-			event := DefuseEvent{
-				PlayerName: matches[1],
-				PlayerSID:  matches[2],
-				WithKit:    false, // Будет определено при обработке
-				EventType:  "abandoned",
-				Date:       date,
-			}
-			result.DefuseEvents = append(result.DefuseEvents, event)
-			continue
+	var coords [3]float64
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return Position{}, false
 		}
+		coords[i] = v
+	}
+	return Position{X: coords[0], Y: coords[1], Z: coords[2]}, true
+}
 
-		// Взрыв бомбы
-		if matches := p.regexps.BombExplodedPattern.FindStringSubmatch(line); matches != nil {
-			// Создаем событие взрыва бомбы
-			event := DefuseEvent{
-				PlayerName: "", // Будет определено при обработке
-				PlayerSID:  "",
-				WithKit:    false, // Будет определено при обработке
-				EventType:  "failed",
-				Date:       date,
-			}
-			result.DefuseEvents = append(result.DefuseEvents, event)
-			continue
+// applyKillModifiers разбирает хвост строки убийства вида "(headshot) (penetrated)"
+// или "(throughsmoke headshot)" и проставляет флаги события
+func applyKillModifiers(event *KillEvent, suffix string) {
+	modifiers := strings.FieldsFunc(suffix, func(r rune) bool {
+		return r == '(' || r == ')' || r == ' ' || r == '\t'
+	})
+	for _, modifier := range modifiers {
+		switch modifier {
+		case "headshot":
+			event.Headshot = true
+		case "penetrated":
+			event.Penetrated = true
+		case "throughsmoke":
+			event.ThroughSmoke = true
+		case "noscope":
+			event.NoScope = true
+		case "attackerblind":
+			event.AttackerBlind = true
 		}
 	}
 }
 
-// parseJSONBlockFromLines парсит блок JSON_BEGIN...JSON_END из массива строк
-// Возвращает RoundStats и количество обработанных строк
-func (p *Parser) parseJSONBlockFromLines(lines []string, startIdx int, date string) (*RoundStats, int) {
+// parseJSONBlock парсит блок JSON_BEGIN...JSON_END.
+// firstLine — строка с JSON_BEGIN, blockLines — строки блока без JSON_END.
+// Ошибки конвертации и короткие строки игроков учитываются в diag (может быть nil).
+func (p *Parser) parseJSONBlock(firstLine string, blockLines []string, date string, diag *FileDiagnostics) *RoundStats {
 	stats := &RoundStats{
 		Date:    date,
 		Players: []PlayerStats{},
 	}
 
-	firstLine := lines[startIdx]
-
 	// Извлекаем время из первой строки
 	if strings.HasPrefix(firstLine, "L ") {
 		parts := strings.Fields(firstLine)
@@ -289,16 +268,17 @@ func (p *Parser) parseJSONBlockFromLines(lines []string, startIdx int, date stri
 		}
 	}
 
-	// Читаем строки до JSON_END
-	consumed := 0
-	var blockLines []string
-	for i := startIdx + 1; i < len(lines); i++ {
-		consumed++
-		line := lines[i]
-		if strings.Contains(line, "JSON_END") {
-			break
+	// Числовые метаданные; ошибки конвертации учитываются в diag
+	integer := func(field, line string) int {
+		val := extractQuotedValue(line)
+		if val == "" {
+			return 0
+		}
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			diag.conversionError(field, val)
 		}
-		blockLines = append(blockLines, line)
+		return n
 	}
 
 	// Парсим метаданные
@@ -311,24 +291,18 @@ func (p *Parser) parseJSONBlockFromLines(lines []string, startIdx int, date stri
 
 		switch {
 		case strings.HasPrefix(line, `"round_number"`):
-			if val := extractQuotedValue(line); val != "" {
-				stats.RoundNumber, _ = strconv.Atoi(val)
-			}
+			stats.RoundNumber = integer("round_number", line)
 		case strings.HasPrefix(line, `"score_t"`):
-			if val := extractQuotedValue(line); val != "" {
-				stats.ScoreT, _ = strconv.Atoi(val)
-			}
+			stats.ScoreT = integer("score_t", line)
 		case strings.HasPrefix(line, `"score_ct"`):
-			if val := extractQuotedValue(line); val != "" {
-				stats.ScoreCT, _ = strconv.Atoi(val)
-			}
+			stats.ScoreCT = integer("score_ct", line)
 		case strings.HasPrefix(line, `"map"`):
 			stats.Map = extractQuotedValue(line)
 		case strings.HasPrefix(line, `"server"`):
 			stats.Server = extractQuotedValue(line)
 		case strings.HasPrefix(line, `"player_`):
 			// Парсим статистику игрока
-			playerStats := parsePlayerStats(line)
+			playerStats := parsePlayerStats(line, diag)
 			if playerStats != nil {
 				stats.Players = append(stats.Players, *playerStats)
 			}
@@ -336,11 +310,17 @@ func (p *Parser) parseJSONBlockFromLines(lines []string, startIdx int, date stri
 	}
 
 	// Рейтинги будут рассчитаны позже, после проставления Winner
-	return stats, consumed
+	return stats
 }
 
-// calculateRoundRatings рассчитывает EPI рейтинг для всех игроков в раунде
-func calculateRoundRatings(round *RoundStats) {
+// EPIVersion — версия формулы EPI. Увеличьте при любом изменении calculateRoundRatings:
+// рейтинги раундов хранятся в кэше парсинга и иначе не пересчитаются.
+// Версия 2: победой считается любой исход раунда из roundOutcomes (бомба, время, заложники), а не только уничтожение.
+const EPIVersion = 2
+
+// calculateRoundRatings рассчитывает EPI рейтинг для всех игроков в раунде.
+// eventClutch — клатч-бонус по PlayerStats.ClutchVs вместо сравнения размеров команд (SetEventClutchEPI).
+func calculateRoundRatings(round *RoundStats, eventClutch bool) {
 	if len(round.Players) == 0 {
 		return
 	}
@@ -430,7 +410,12 @@ func calculateRoundRatings(round *RoundStats) {
 
 		// Дополнительный бонус за клатч (в меньшинстве + победа + минимум 2 килла)
 		clutchBonus := 0.0
-		if teamCount < oppCount && win == 1.0 && kills >= 2 {
+		if eventClutch {
+			// Реальный клатч 1vN: каждый противник дает +5%, убийства не обязательны (дефьюз, время)
+			if p.ClutchVs > 0 && win == 1.0 {
+				clutchBonus = float64(p.ClutchVs) * 0.05
+			}
+		} else if teamCount < oppCount && win == 1.0 && kills >= 2 {
 			// Бонус зависит от разницы в численности: каждый игрок в минусе дает +5%
 			outnumberedDiff := float64(oppCount - teamCount)
 			clutchBonus = outnumberedDiff * 0.05
@@ -443,14 +428,24 @@ func calculateRoundRatings(round *RoundStats) {
 
 // ParseResult содержит результаты парсинга логов
 type ParseResult struct {
-	Players      map[string]Player
-	KillEvents   []KillEvent
-	FlashEvents  []FlashEvent
-	DefuseEvents []DefuseEvent
-	WeaponSet    map[string]struct{}
-	RoundStats   []RoundStats // Статистика раундов из JSON_BEGIN блоков
-	StartDate    string       // Начальная дата в формате DD-MM-YYYY
-	EndDate      string       // Конечная дата в формате DD-MM-YYYY
+	Players        map[string]Player
+	KillEvents     []KillEvent
+	FlashEvents    []FlashEvent
+	DamageEvents   []DamageEvent
+	GrenadeEvents  []GrenadeEvent
+	DefuseEvents   []DefuseEvent
+	BombEvents     []BombEvent
+	HostageEvents  []HostageEvent
+	PurchaseEvents []PurchaseEvent
+	CustomEvents   []CustomEvent // События внешних обработчиков строк (Parser.Handlers)
+	ShameEvents    []ShameEvent  // Тимкиллы, самоубийства, падения и смерти от бомбы
+	WeaponSet      map[string]struct{}
+	RoundStats     []RoundStats // Статистика раундов из JSON_BEGIN блоков
+	Matches        []Match      // Завершённые (и, с SetKeepPartial, незавершённые) матчи с диапазонами их событий
+	DroppedMatches []DroppedMatch
+	Diagnostics    Diagnostics // Счётчики разбора по файлам
+	StartDate      string      // Начальная дата в формате DD-MM-YYYY
+	EndDate        string      // Конечная дата в формате DD-MM-YYYY
 }
 
 // KeyAndTitle возвращает ключ и заголовок для группировки игроков.
@@ -470,65 +465,85 @@ func extractQuotedValue(line string) string {
 	return value
 }
 
-// parsePlayerStats парсит строку со статистикой игрока
+// parsePlayerStats разбирает строку "player_N" JSON блока.
 // Формат: "player_0" : "            26840160,      2,  16000,      0,      0,      0,      0,   0.00,   0.00,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0"
-func parsePlayerStats(line string) *PlayerStats {
+// Короткие строки пропускаются (nil),
+// поля, которые не удалось преобразовать, остаются нулевыми. Обе проблемы учитываются в diag (может быть nil).
+func parsePlayerStats(line string, diag *FileDiagnostics) *PlayerStats {
 	// Находим значение в кавычках после :
 	parts := strings.SplitN(line, ":", 2)
 	if len(parts) < 2 {
+		diag.shortPlayerRow()
 		return nil
 	}
 
-	// Извлекаем CSV данные
-	value := strings.TrimSpace(parts[1])
+	// Извлекаем CSV данные; у всех строк, кроме последней, после кавычки идёт запятая
+	value := strings.TrimSuffix(strings.TrimSpace(parts[1]), ",")
 	value = strings.Trim(value, `"`)
 
 	// Разбиваем по запятым
 	fields := strings.Split(value, ",")
-	if len(fields) < 26 {
+	if len(fields) < len(playerStatsFields) {
+		diag.shortPlayerRow()
 		return nil
 	}
 
+	// Конвертеры запоминают поля, которые не удалось разобрать
+	number := func(i int) float64 {
+		field := strings.TrimSpace(fields[i])
+		n, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			diag.conversionError(playerStatsFields[i], field)
+		}
+		return n
+	}
+	integer := func(i int) int {
+		field := strings.TrimSpace(fields[i])
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			diag.conversionError(playerStatsFields[i], field)
+		}
+		return n
+	}
+
 	// Парсим каждое поле
 	ps := &PlayerStats{}
-	ps.AccountID, _ = strconv.ParseInt(strings.TrimSpace(fields[0]), 10, 64)
-	ps.Team, _ = strconv.Atoi(strings.TrimSpace(fields[1]))
-	ps.Money, _ = strconv.Atoi(strings.TrimSpace(fields[2]))
-	ps.Kills, _ = strconv.Atoi(strings.TrimSpace(fields[3]))
-	ps.Deaths, _ = strconv.Atoi(strings.TrimSpace(fields[4]))
-	ps.Assists, _ = strconv.Atoi(strings.TrimSpace(fields[5]))
-
-	// Damage может быть как int так и float (в новых версиях CS2)
-	if damageFloat, err := strconv.ParseFloat(strings.TrimSpace(fields[6]), 64); err == nil {
-		ps.Damage = int(damageFloat)
+	accountID := strings.TrimSpace(fields[0])
+	var err error
+	if ps.AccountID, err = strconv.ParseInt(accountID, 10, 64); err != nil {
+		diag.conversionError(playerStatsFields[0], accountID)
 	}
+	ps.Team = integer(1)
+	ps.Money = integer(2)
+	ps.Kills = integer(3)
+	ps.Deaths = integer(4)
+	ps.Assists = integer(5)
 
-	ps.HSP, _ = strconv.ParseFloat(strings.TrimSpace(fields[7]), 64)
-	ps.KDR, _ = strconv.ParseFloat(strings.TrimSpace(fields[8]), 64)
-	ps.ADR, _ = strconv.ParseFloat(strings.TrimSpace(fields[9]), 64)
-	ps.MVP, _ = strconv.Atoi(strings.TrimSpace(fields[10]))
-	ps.EF, _ = strconv.Atoi(strings.TrimSpace(fields[11]))
-	ps.UD, _ = strconv.Atoi(strings.TrimSpace(fields[12]))
-	ps.ThreeK, _ = strconv.Atoi(strings.TrimSpace(fields[13]))
-	ps.FourK, _ = strconv.Atoi(strings.TrimSpace(fields[14]))
-	ps.FiveK, _ = strconv.Atoi(strings.TrimSpace(fields[15]))
-	ps.ClutchK, _ = strconv.Atoi(strings.TrimSpace(fields[16]))
-	ps.FirstK, _ = strconv.Atoi(strings.TrimSpace(fields[17]))
-	ps.PistolK, _ = strconv.Atoi(strings.TrimSpace(fields[18]))
-	ps.SniperK, _ = strconv.Atoi(strings.TrimSpace(fields[19]))
-	ps.BlindK, _ = strconv.Atoi(strings.TrimSpace(fields[20]))
-	ps.BombK, _ = strconv.Atoi(strings.TrimSpace(fields[21]))
+	// Damage может быть как int так и float (в новых версиях CS2)
+	ps.Damage = int(number(6))
+
+	ps.HSP = number(7)
+	ps.KDR = number(8)
+	ps.ADR = number(9)
+	ps.MVP = integer(10)
+	ps.EF = integer(11)
+	ps.UD = integer(12)
+	ps.ThreeK = integer(13)
+	ps.FourK = integer(14)
+	ps.FiveK = integer(15)
+	ps.ClutchK = integer(16)
+	ps.FirstK = integer(17)
+	ps.PistolK = integer(18)
+	ps.SniperK = integer(19)
+	ps.BlindK = integer(20)
+	ps.BombK = integer(21)
 
 	// FireDmg и UniqueK тоже могут быть float в новых версиях
-	if fireDmgFloat, err := strconv.ParseFloat(strings.TrimSpace(fields[22]), 64); err == nil {
-		ps.FireDmg = int(fireDmgFloat)
-	}
-	if uniqueKFloat, err := strconv.ParseFloat(strings.TrimSpace(fields[23]), 64); err == nil {
-		ps.UniqueK = int(uniqueKFloat)
-	}
+	ps.FireDmg = int(number(22))
+	ps.UniqueK = int(number(23))
 
-	ps.Dinks, _ = strconv.Atoi(strings.TrimSpace(fields[24]))
-	ps.ChickenK, _ = strconv.Atoi(strings.TrimSpace(fields[25]))
+	ps.Dinks = integer(24)
+	ps.ChickenK = integer(25)
 
 	return ps
 }
@@ -2,6 +2,10 @@ package logparser
 
 import (
 	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -29,7 +33,7 @@ func TestCalculateRoundRatings_BasicScenario(t *testing.T) {
 		round.Players = append(round.Players, PlayerStats{Team: 2})
 	}
 
-	calculateRoundRatings(round)
+	calculateRoundRatings(round, false)
 
 	player := &round.Players[0]
 
@@ -74,7 +78,7 @@ func TestCalculateRoundRatings_ACE(t *testing.T) {
 		round.Players = append(round.Players, PlayerStats{Team: 2})
 	}
 
-	calculateRoundRatings(round)
+	calculateRoundRatings(round, false)
 
 	player := &round.Players[0]
 
@@ -115,7 +119,7 @@ func TestCalculateRoundRatings_Clutch1v3(t *testing.T) {
 		round.Players = append(round.Players, PlayerStats{Team: 2})
 	}
 
-	calculateRoundRatings(round)
+	calculateRoundRatings(round, false)
 
 	player := &round.Players[0]
 
@@ -157,7 +161,7 @@ func TestCalculateRoundRatings_Clutch1v5(t *testing.T) {
 		round.Players = append(round.Players, PlayerStats{Team: 3})
 	}
 
-	calculateRoundRatings(round)
+	calculateRoundRatings(round, false)
 
 	player := &round.Players[0]
 
@@ -203,7 +207,7 @@ func TestCalculateRoundRatings_WeakRound(t *testing.T) {
 		round.Players = append(round.Players, PlayerStats{Team: 2})
 	}
 
-	calculateRoundRatings(round)
+	calculateRoundRatings(round, false)
 
 	player := &round.Players[0]
 
@@ -248,7 +252,7 @@ func TestCalculateRoundRatings_SupportPlay(t *testing.T) {
 		round.Players = append(round.Players, PlayerStats{Team: 2})
 	}
 
-	calculateRoundRatings(round)
+	calculateRoundRatings(round, false)
 
 	player := &round.Players[0]
 
@@ -292,7 +296,7 @@ func TestCalculateRoundRatings_Outnumbered3v5(t *testing.T) {
 		round.Players = append(round.Players, PlayerStats{Team: 2})
 	}
 
-	calculateRoundRatings(round)
+	calculateRoundRatings(round, false)
 
 	player := &round.Players[0]
 
@@ -351,7 +355,7 @@ func TestCalculateRoundRatings_MultiKillBoundaries(t *testing.T) {
 				round.Players = append(round.Players, PlayerStats{Team: 2})
 			}
 
-			calculateRoundRatings(round)
+			calculateRoundRatings(round, false)
 
 			player := &round.Players[0]
 
@@ -416,7 +420,7 @@ func TestCalculateRoundRatings_NoClutchBonusConditions(t *testing.T) {
 				round.Players = append(round.Players, PlayerStats{Team: 2})
 			}
 
-			calculateRoundRatings(round)
+			calculateRoundRatings(round, false)
 
 			player := &round.Players[0]
 
@@ -446,7 +450,7 @@ func TestCalculateRoundRatings_EmptyRound(t *testing.T) {
 	}
 
 	// Should not panic
-	calculateRoundRatings(round)
+	calculateRoundRatings(round, false)
 }
 
 // TestCalculateRoundRatings_ZeroDivisionProtection tests division by zero protection
@@ -465,7 +469,7 @@ func TestCalculateRoundRatings_ZeroDivisionProtection(t *testing.T) {
 	}
 
 	// No opponents - should default to 5
-	calculateRoundRatings(round)
+	calculateRoundRatings(round, false)
 
 	player := &round.Players[0]
 
@@ -474,3 +478,129 @@ func TestCalculateRoundRatings_ZeroDivisionProtection(t *testing.T) {
 		t.Errorf("Rating should be valid number, got %.3f", player.Rating)
 	}
 }
+
+// TestParseDirectory_ParallelMatchesSequential checks that worker count does not change the merged result
+func TestParseDirectory_ParallelMatchesSequential(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"b/2025_09_05_180000.log": testMatchLog,
+		"a/2025_09_12_180000.log": strings.ReplaceAll(testMatchLog, "m4a1", "famas"),
+		"2025_09_01_180000.log":   strings.ReplaceAll(testMatchLog, "m4a1", "ak47"),
+		"notes.txt":               testMatchLog,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sequential := New()
+	sequential.SetWorkers(1)
+	want, err := sequential.ParseDirectory(dir, ".log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parallel := New()
+	parallel.SetWorkers(8)
+	got, err := parallel.ParseDirectory(dir, ".log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Parallel result differs from sequential")
+	}
+
+	// Files are merged by date, not by path
+	var weapons []string
+	for _, e := range got.KillEvents {
+		weapons = append(weapons, e.Weapon)
+	}
+	if strings.Join(weapons, ",") != "ak47,m4a1,famas" {
+		t.Errorf("Expected kills ordered by file date, got %v", weapons)
+	}
+	if got.StartDate != "01-09-2025" || got.EndDate != "12-09-2025" {
+		t.Errorf("Unexpected date range: %s — %s", got.StartDate, got.EndDate)
+	}
+}
+
+// TestParseMatchLine_KillModifiers checks that kills with trailing modifiers are parsed
+func TestParseMatchLine_KillModifiers(t *testing.T) {
+	prefix := `L 09/05/2025 - 18:01:30: "Alice<2><[U:1:100]><CT>" [-100 200 10] killed "Bob<3><[U:1:200]><TERRORIST>" [50 60 10] with "awp"`
+
+	tests := []struct {
+		name   string
+		suffix string
+		want   KillEvent
+	}{
+		{"plain", "", KillEvent{}},
+		{"headshot", " (headshot)", KillEvent{Headshot: true}},
+		{"separate groups", " (headshot) (penetrated)", KillEvent{Headshot: true, Penetrated: true}},
+		{"combined group", " (noscope throughsmoke attackerblind headshot)", KillEvent{
+			Headshot: true, NoScope: true, ThroughSmoke: true, AttackerBlind: true,
+		}},
+	}
+
+	p := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := newParseResult()
+			p.parseMatchLine(prefix+tt.suffix, match)
+
+			if len(match.KillEvents) != 1 {
+				t.Fatalf("Expected kill to be parsed, got %d events", len(match.KillEvents))
+			}
+			got := match.KillEvents[0]
+			if got.Weapon != "awp" || got.KillerSID != "[U:1:100]" || got.VictimName != "Bob" {
+				t.Errorf("Unexpected kill event: %+v", got)
+			}
+			if got.Headshot != tt.want.Headshot || got.Penetrated != tt.want.Penetrated ||
+				got.ThroughSmoke != tt.want.ThroughSmoke || got.NoScope != tt.want.NoScope ||
+				got.AttackerBlind != tt.want.AttackerBlind {
+				t.Errorf("Unexpected modifiers: %+v", got)
+			}
+		})
+	}
+}
+
+// TestParseMatchLine_Utility checks grenade throws and team flash detection
+func TestParseMatchLine_Utility(t *testing.T) {
+	lines := []string{
+		`L 09/05/2025 - 18:01:20: "Alice<2><[U:1:100]><CT>" threw flashbang [-100 200 10] flashbang entindex 276)`,
+		`L 09/05/2025 - 18:01:21: "Bob<3><[U:1:200]><TERRORIST>" threw molotov [50 60 10]`,
+		`L 09/05/2025 - 18:01:22: "Bob<3><[U:1:200]><TERRORIST>" blinded for 1.20 by "Alice<2><[U:1:100]><CT>" from flashbang entindex 276`,
+		`L 09/05/2025 - 18:01:22: "Carl<4><[U:1:300]><CT>" blinded for 3.00 by "Alice<2><[U:1:100]><CT>" from flashbang entindex 276`,
+	}
+
+	p := New()
+	match := newParseResult()
+	for _, line := range lines {
+		p.parseMatchLine(line, match)
+	}
+
+	if len(match.GrenadeEvents) != 2 {
+		t.Fatalf("Expected 2 grenade events, got %d", len(match.GrenadeEvents))
+	}
+	flash, molotov := match.GrenadeEvents[0], match.GrenadeEvents[1]
+	if flash.Grenade != "flashbang" || flash.ThrowerSID != "[U:1:100]" || flash.Pos != (Position{X: -100, Y: 200, Z: 10}) {
+		t.Errorf("Unexpected flashbang throw: %+v", flash)
+	}
+	if molotov.Grenade != "molotov" || molotov.ThrowerName != "Bob" || molotov.Date != "2025-09-05" {
+		t.Errorf("Unexpected molotov throw: %+v", molotov)
+	}
+
+	if len(match.FlashEvents) != 2 {
+		t.Fatalf("Expected 2 flash events, got %d", len(match.FlashEvents))
+	}
+	if match.FlashEvents[0].TeamFlash || match.FlashEvents[0].Duration != 1.2 {
+		t.Errorf("Expected enemy blind, got %+v", match.FlashEvents[0])
+	}
+	if !match.FlashEvents[1].TeamFlash {
+		t.Errorf("Expected team flash, got %+v", match.FlashEvents[1])
+	}
+}
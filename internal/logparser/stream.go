@@ -0,0 +1,637 @@
+package logparser
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseReader парсит лог из произвольного источника (stdin, архив, сеть) за один проход.
+// name используется как имя источника: из него извлекается дата в формате YYYY_MM_DD_HHMMSS
+// и строятся ID матчей.
+func (p *Parser) ParseReader(r io.Reader, name string) (*ParseResult, error) {
+	result := newParseResult()
+
+	if date, ok := dateFromFileName(name); ok {
+		result.StartDate = date.Format("02-01-2006")
+		result.EndDate = result.StartDate
+	}
+
+	return result, p.parseStream(r, name, result)
+}
+
+// parseStream читает строки из r и добавляет в result события только подтверждённых матчей.
+// source — имя источника, из которого строятся ID матчей.
+func (p *Parser) parseStream(r io.Reader, source string, result *ParseResult) error {
+	stream := newMatchStream(p, result, source)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		stream.processLine(scanner.Text())
+	}
+	stream.abortMatch(AbortLogEnded)
+	result.Diagnostics.Files = append(result.Diagnostics.Files, *stream.diag)
+	return scanner.Err()
+}
+
+// matchStream — потоковый автомат, который буферизует события текущего матча
+// и переносит их в итоговый результат только после пары Match_Start … Game Over:
+type matchStream struct {
+	p          *Parser
+	result     *ParseResult
+	source     string           // имя источника для Match.Source и Match.ID
+	sourceHash string           // хэш полного пути источника для Match.ID (см. matchID)
+	count      int              // количество подтверждённых матчей в источнике
+	diag       *FileDiagnostics // счётчики разбора источника
+
+	// Режим слежения (Follow)
+	skip         int         // первые skip матчей источника уже есть в result: они разбираются, но не добавляются
+	skipDropped  int         // столько же для отброшенных матчей источника
+	hooks        StreamHooks // обработчики раундов, матчей и убийств
+	roundPending bool        // последний раунд матча ещё не передан OnRound
+
+	match           *ParseResult      // события текущего матча; nil, если матч не начат
+	mapName         string            // карта текущего матча из строки Match_Start
+	matchStart      time.Time         // время строки Match_Start текущего матча
+	lastLine        string            // последняя строка текущего матча (время конца незавершённого матча)
+	maxRounds       int               // mp_maxrounds: раунды основного времени
+	otMaxRounds     int               // mp_overtime_maxrounds: раунды одного овертайма
+	roundDamageFrom int               // индекс первого попадания текущего раунда в match.DamageEvents
+	roundThrowsFrom int               // индекс первого броска гранаты текущего раунда в match.GrenadeEvents
+	roundBombFrom   int               // индекс первого события с бомбой текущего раунда в match.BombEvents
+	roundHostFrom   int               // индекс первого события с заложником текущего раунда в match.HostageEvents
+	roundBuyFrom    int               // индекс первой покупки текущего раунда в match.PurchaseEvents
+	roundKillFrom   int               // индекс первого убийства текущего раунда в match.KillEvents
+	roundFlashFrom  int               // индекс первого ослепления текущего раунда в match.FlashEvents
+	roundDefuseFrom int               // индекс первого события дефьюза текущего раунда в match.DefuseEvents
+	roundCustomFrom int               // индекс первого события внешних обработчиков текущего раунда в match.CustomEvents
+	roundShameFrom  int               // индекс первой позорной смерти текущего раунда в match.ShameEvents
+	carried         map[string]string // SID -> основное оружие, с которым игрок начнёт следующий раунд
+	jsonFirst       string            // первая строка открытого JSON_BEGIN блока
+	jsonLines       []string          // строки открытого JSON_BEGIN блока
+	inJSON          bool
+}
+
+// newMatchStream создает автомат, пишущий подтверждённые матчи источника source в result
+func newMatchStream(p *Parser, result *ParseResult, source string) *matchStream {
+	return &matchStream{
+		p:           p,
+		result:      result,
+		source:      source,
+		sourceHash:  sourceHash(source),
+		diag:        newFileDiagnostics(source),
+		maxRounds:   defaultMaxRounds,
+		otMaxRounds: defaultOvertimeMaxRounds,
+	}
+}
+
+// processLine обрабатывает одну строку лога
+func (s *matchStream) processLine(line string) {
+	s.diag.Lines++
+
+	// Начало матча прерывает незавершённый матч, если он был
+	if matches := findMarked(s.p.regexps.MatchStartPattern, line, `"Match_Start"`); matches != nil {
+		s.diag.recognized(LineMatchStart)
+		s.abortMatch(AbortNewMatch)
+		s.startMatch(matches[1], line)
+		return
+	}
+
+	// Длина основного времени и овертаймов из настроек сервера
+	if matches := findMarked(s.p.regexps.ServerCvarPattern, line, "server_cvar:"); matches != nil {
+		s.diag.recognized(LineServerCvar)
+		s.setCvar(matches[1], matches[2])
+		return
+	}
+
+	if s.match == nil {
+		s.diag.recognized(LineOutsideMatch)
+		return
+	}
+	s.lastLine = line
+
+	// mp_restartgame: накопленное отбрасывается, матч начинается заново на той же карте
+	if strings.Contains(line, `"Restart_Round_(`) && s.p.regexps.RestartPattern.MatchString(line) {
+		s.diag.recognized(LineRestart)
+		mapName := s.mapName
+		s.abortMatch(AbortRestart)
+		s.startMatch(mapName, line)
+		return
+	}
+
+	// Смена карты или разминка посреди матча — матч брошен
+	if strings.Contains(line, "map") && s.p.regexps.MapChangePattern.MatchString(line) {
+		s.diag.recognized(LineMapChange)
+		s.abortMatch(AbortMapChange)
+		return
+	}
+	if strings.Contains(line, `"Warmup_Start"`) && s.p.regexps.WarmupStartPattern.MatchString(line) {
+		s.diag.recognized(LineWarmup)
+		s.abortMatch(AbortWarmup)
+		return
+	}
+
+	// Конец матча по событию "Game Over:" — матч подтверждён
+	if matches := findMarked(s.p.regexps.GameOverPattern, line, "Game Over:"); matches != nil {
+		s.diag.recognized(LineGameOver)
+		s.closeTruncatedJSONBlock()
+		s.commitMatch(line, matches, "")
+		return
+	}
+
+	if s.inJSON {
+		s.diag.recognized(LineJSON)
+		if strings.Contains(line, "JSON_END") {
+			s.closeJSONBlock()
+			return
+		}
+		s.jsonLines = append(s.jsonLines, line)
+		return
+	}
+
+	// Проверяем, не начало ли это JSON_BEGIN блока
+	if strings.Contains(line, "JSON_BEGIN{") {
+		s.diag.recognized(LineJSON)
+		s.inJSON = true
+		s.jsonFirst = line
+		s.jsonLines = s.jsonLines[:0]
+		return
+	}
+
+	kills := len(s.match.KillEvents)
+	lineType := s.p.parseMatchLine(line, s.match)
+	if lineType == "" {
+		s.diag.unmatched(line)
+	} else {
+		s.diag.recognized(lineType)
+	}
+	// Тимкилл тоже строка LineKill, но в KillEvents он не попадает
+	if len(s.match.KillEvents) > kills && s.hooks.OnKill != nil && !s.replaying() {
+		s.hooks.OnKill(s.match.KillEvents[len(s.match.KillEvents)-1])
+	}
+	s.emitRound(false)
+}
+
+// findMarked применяет re только к строкам, содержащим marker: почти все строки матча — события игроков,
+// и дешёвая проверка подстроки избавляет их от прогона регулярных выражений границ матча
+func findMarked(re *regexp.Regexp, line, marker string) []string {
+	if !strings.Contains(line, marker) {
+		return nil
+	}
+	return re.FindStringSubmatch(line)
+}
+
+// startMatch начинает буферизацию нового матча на карте mapName; line — строка начала
+func (s *matchStream) startMatch(mapName, line string) {
+	s.match = newParseResult()
+	s.mapName = mapName
+	s.matchStart, _ = ParseLogTime(line)
+	s.lastLine = line
+	s.roundDamageFrom = 0
+	s.roundThrowsFrom = 0
+	s.roundBombFrom = 0
+	s.roundHostFrom = 0
+	s.roundBuyFrom = 0
+	s.roundKillFrom = 0
+	s.roundFlashFrom = 0
+	s.roundDefuseFrom = 0
+	s.roundCustomFrom = 0
+	s.roundShameFrom = 0
+	s.carried = make(map[string]string)
+	s.roundPending = false
+}
+
+// abortMatch завершает текущий матч, не дошедший до Game Over. Пустые матчи (например, повторный
+// Match_Start сразу после рестарта) пропускаются молча. Остальные сохраняются с пометкой Partial,
+// если включён SetKeepPartial, иначе записываются в DroppedMatches с причиной reason.
+func (s *matchStream) abortMatch(reason string) {
+	s.closeTruncatedJSONBlock()
+	if s.match == nil {
+		return
+	}
+	if s.match.empty() {
+		s.match = nil
+		return
+	}
+
+	if s.p.keepPartial {
+		s.commitMatch(s.lastLine, nil, reason)
+		return
+	}
+
+	if s.skipDropped > 0 {
+		s.skipDropped--
+	} else {
+		s.result.DroppedMatches = append(s.result.DroppedMatches, DroppedMatch{
+			Source: s.source,
+			Map:    s.mapName,
+			Start:  s.matchStart,
+			Rounds: len(s.match.RoundStats),
+			Reason: reason,
+		})
+	}
+	s.match = nil
+}
+
+// replaying проверяет, что текущий матч уже есть в result (режим слежения дочитывает известное)
+func (s *matchStream) replaying() bool {
+	return s.count < s.skip
+}
+
+// matchID возвращает ID n-го матча источника: имя файла для читаемости и хэш полного пути источника,
+// чтобы одноимённые логи из разных директорий, архивов и серверов не сливали раунды разных матчей
+func (s *matchStream) matchID(n int) string {
+	return fmt.Sprintf("%s#%d@%s", filepath.Base(s.source), n, s.sourceHash)
+}
+
+// sourceHash — короткий хэш абсолютного пути источника (для членов архива — путь архива + имя члена)
+func sourceHash(source string) string {
+	sum := sha256.Sum256([]byte(cleanAbsPath(source)))
+	return hex.EncodeToString(sum[:4])
+}
+
+// emitRound передаёт OnRound последний раунд матча, как только строка конца раунда проставила победителя.
+// force — матч завершается, и раунд отдаётся даже без победителя. Рейтинги считаются на копии.
+func (s *matchStream) emitRound(force bool) {
+	if !s.roundPending || s.match == nil {
+		return
+	}
+	round := s.match.RoundStats[len(s.match.RoundStats)-1]
+	if round.Winner == 0 && !force {
+		return
+	}
+	s.roundPending = false
+	if s.hooks.OnRound == nil || s.replaying() {
+		return
+	}
+
+	round.Players = append([]PlayerStats(nil), round.Players...)
+	round.MatchID = s.matchID(s.count + 1)
+	calculateRoundRatings(&round, s.p.clutchEPI)
+	s.hooks.OnRound(round)
+}
+
+// setCvar запоминает настройки сервера, влияющие на разметку раундов
+func (s *matchStream) setCvar(name, value string) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return
+	}
+	switch name {
+	case "mp_maxrounds":
+		s.maxRounds = n
+	case "mp_overtime_maxrounds":
+		s.otMaxRounds = n
+	}
+}
+
+// roundPhase определяет овертайм и половину для раунда roundNumber (с 1).
+// Стороны меняются после половины основного времени и после половины каждого овертайма.
+func roundPhase(roundNumber, maxRounds, otMaxRounds int) (overtime, half int) {
+	if roundNumber <= 0 {
+		return 0, 0
+	}
+	if roundNumber <= maxRounds {
+		if roundNumber <= maxRounds/2 {
+			return 0, 1
+		}
+		return 0, 2
+	}
+
+	k := roundNumber - maxRounds - 1 // номер раунда внутри овертаймов с 0
+	overtime = k/otMaxRounds + 1
+	if k%otMaxRounds < otMaxRounds/2 {
+		return overtime, 1
+	}
+	return overtime, 2
+}
+
+// closeTruncatedJSONBlock закрывает блок, оборванный без JSON_END, и учитывает его в диагностике
+func (s *matchStream) closeTruncatedJSONBlock() {
+	if s.inJSON {
+		s.diag.TruncatedJSON++
+	}
+	s.closeJSONBlock()
+}
+
+// closeJSONBlock завершает открытый JSON_BEGIN блок и добавляет раунд в текущий матч
+func (s *matchStream) closeJSONBlock() {
+	if !s.inJSON {
+		return
+	}
+	s.inJSON = false
+
+	if s.match == nil {
+		return
+	}
+	roundStats := s.p.parseJSONBlock(s.jsonFirst, s.jsonLines, ExtractDateFromLogLine(s.jsonFirst), s.diag)
+	roundStats.Overtime, roundStats.Half = roundPhase(roundStats.RoundNumber, s.maxRounds, s.otMaxRounds)
+	s.match.RoundStats = append(s.match.RoundStats, *roundStats)
+	s.closeRound(&s.match.RoundStats[len(s.match.RoundStats)-1])
+	s.roundPending = s.hooks.OnRound != nil
+}
+
+// closeRound проставляет номер раунда round событиям, накопленным с конца предыдущего раунда,
+// считает фактически снятое здоровье по каждому попаданию, отмечает закладку бомбы, спасённых заложников
+// и экономику игроков.
+// round == nil — события после последнего JSON блока, не относящиеся ни к одному раунду.
+func (s *matchStream) closeRound(round *RoundStats) {
+	roundNumber := 0
+	if round != nil {
+		roundNumber = round.RoundNumber
+	}
+
+	health := make(map[string]int) // SID жертвы -> здоровье до следующего попадания
+	for i := s.roundDamageFrom; i < len(s.match.DamageEvents); i++ {
+		event := &s.match.DamageEvents[i]
+		event.Round = roundNumber
+
+		before, ok := health[event.VictimSID]
+		if !ok {
+			before = event.Health + event.Damage
+			if before > 100 {
+				before = 100
+			}
+		}
+		event.HealthDamage = before - event.Health
+		if event.HealthDamage > event.Damage {
+			event.HealthDamage = event.Damage
+		}
+		health[event.VictimSID] = event.Health
+	}
+	s.roundDamageFrom = len(s.match.DamageEvents)
+
+	for i := s.roundThrowsFrom; i < len(s.match.GrenadeEvents); i++ {
+		s.match.GrenadeEvents[i].Round = roundNumber
+	}
+	s.roundThrowsFrom = len(s.match.GrenadeEvents)
+
+	for i := s.roundBombFrom; i < len(s.match.BombEvents); i++ {
+		event := &s.match.BombEvents[i]
+		event.Round = roundNumber
+		if round != nil && event.EventType == "plant" {
+			round.BombSite = event.Site
+			round.PlanterSID = event.PlayerSID
+		}
+	}
+	s.roundBombFrom = len(s.match.BombEvents)
+
+	for i := s.roundHostFrom; i < len(s.match.HostageEvents); i++ {
+		event := &s.match.HostageEvents[i]
+		event.Round = roundNumber
+		if round != nil && event.EventType == "rescue" {
+			round.HostagesRescued++
+		}
+	}
+	s.roundHostFrom = len(s.match.HostageEvents)
+
+	for i := s.roundKillFrom; i < len(s.match.KillEvents); i++ {
+		s.match.KillEvents[i].Round = roundNumber
+	}
+	for i := s.roundFlashFrom; i < len(s.match.FlashEvents); i++ {
+		s.match.FlashEvents[i].Round = roundNumber
+	}
+	s.roundFlashFrom = len(s.match.FlashEvents)
+
+	// Успешный дефьюз и взрыв уже привязаны к раунду, который они завершили
+	for i := s.roundDefuseFrom; i < len(s.match.DefuseEvents); i++ {
+		if event := &s.match.DefuseEvents[i]; event.EventType != "success" && event.EventType != "failed" {
+			event.Round = roundNumber
+		}
+	}
+	s.roundDefuseFrom = len(s.match.DefuseEvents)
+
+	for i := s.roundCustomFrom; i < len(s.match.CustomEvents); i++ {
+		s.match.CustomEvents[i].Round = roundNumber
+	}
+	s.roundCustomFrom = len(s.match.CustomEvents)
+
+	shame := s.match.ShameEvents[s.roundShameFrom:]
+	for i := range shame {
+		shame[i].Round = roundNumber
+	}
+	s.roundShameFrom = len(s.match.ShameEvents)
+
+	purchases := s.match.PurchaseEvents[s.roundBuyFrom:]
+	for i := range purchases {
+		purchases[i].Round = roundNumber
+	}
+	closeRoundEconomy(round, purchases, s.match.KillEvents[s.roundKillFrom:], shame, s.carried)
+	closeRoundClutches(round, s.match.KillEvents[s.roundKillFrom:], shame)
+	s.roundBuyFrom = len(s.match.PurchaseEvents)
+	s.roundKillFrom = len(s.match.KillEvents)
+}
+
+// commitMatch рассчитывает рейтинги раундов матча, собирает Match и переносит события в результат.
+// line и gameOver — строка "Game Over:" и её разбор GameOverPattern. Для незавершённого матча
+// line — его последняя строка, gameOver == nil, а reason — причина прерывания.
+func (s *matchStream) commitMatch(line string, gameOver []string, reason string) {
+	// События после последнего JSON блока не относятся ни к одному раунду
+	s.closeRound(nil)
+	s.emitRound(true)
+
+	s.count++
+	if s.count <= s.skip {
+		s.match = nil
+		return
+	}
+
+	// Пересчитываем рейтинги для раундов этого матча после того как Winner проставлен
+	for i := range s.match.RoundStats {
+		calculateRoundRatings(&s.match.RoundStats[i], s.p.clutchEPI)
+	}
+
+	// Карта из Match_Start, а если её там нет — из статистики раундов
+	mapName := s.mapName
+	if mapName == "" && len(s.match.RoundStats) > 0 {
+		mapName = s.match.RoundStats[0].Map
+	}
+	for i := range s.match.KillEvents {
+		s.match.KillEvents[i].Map = mapName
+	}
+	for i := range s.match.DamageEvents {
+		s.match.DamageEvents[i].Map = mapName
+	}
+	for i := range s.match.ShameEvents {
+		s.match.ShameEvents[i].Map = mapName
+	}
+
+	match := s.buildMatch(mapName, line, gameOver)
+	match.Partial = reason != ""
+	match.AbortReason = reason
+	s.stampMatchID(match.ID)
+	s.match.Matches = append(s.match.Matches, match)
+	// Период матча — дата из имени источника, как у ParseReader: по ней stats.Processor.Apply расширяет период данных
+	if date, ok := dateFromFileName(s.source); ok {
+		s.match.StartDate = date.Format("02-01-2006")
+		s.match.EndDate = s.match.StartDate
+	}
+
+	s.result.merge(s.match)
+	if s.hooks.OnMatch != nil {
+		s.hooks.OnMatch(s.match)
+	}
+	s.match = nil
+}
+
+// stampMatchID проставляет ID матча раундам и всем событиям текущего матча
+func (s *matchStream) stampMatchID(id string) {
+	m := s.match
+	for i := range m.RoundStats {
+		m.RoundStats[i].MatchID = id
+	}
+	for i := range m.KillEvents {
+		m.KillEvents[i].MatchID = id
+	}
+	for i := range m.FlashEvents {
+		m.FlashEvents[i].MatchID = id
+	}
+	for i := range m.DamageEvents {
+		m.DamageEvents[i].MatchID = id
+	}
+	for i := range m.GrenadeEvents {
+		m.GrenadeEvents[i].MatchID = id
+	}
+	for i := range m.DefuseEvents {
+		m.DefuseEvents[i].MatchID = id
+	}
+	for i := range m.BombEvents {
+		m.BombEvents[i].MatchID = id
+	}
+	for i := range m.HostageEvents {
+		m.HostageEvents[i].MatchID = id
+	}
+	for i := range m.PurchaseEvents {
+		m.PurchaseEvents[i].MatchID = id
+	}
+	for i := range m.CustomEvents {
+		m.CustomEvents[i].MatchID = id
+	}
+	for i := range m.ShameEvents {
+		m.ShameEvents[i].MatchID = id
+	}
+}
+
+// buildMatch собирает Match текущего матча. Диапазоны событий считаются относительно s.match
+// и сдвигаются при слиянии в результат.
+func (s *matchStream) buildMatch(mapName, line string, gameOver []string) Match {
+	m := s.match
+	match := Match{
+		ID:       s.matchID(s.count),
+		Source:   s.source,
+		Map:      mapName,
+		Start:    s.matchStart,
+		Rounds:   EventRange{0, len(m.RoundStats)},
+		Kills:    EventRange{0, len(m.KillEvents)},
+		Flashes:  EventRange{0, len(m.FlashEvents)},
+		Damage:   EventRange{0, len(m.DamageEvents)},
+		Grenades: EventRange{0, len(m.GrenadeEvents)},
+		Defuses:  EventRange{0, len(m.DefuseEvents)},
+		Bomb:     EventRange{0, len(m.BombEvents)},
+		Hostages: EventRange{0, len(m.HostageEvents)},
+		Buys:     EventRange{0, len(m.PurchaseEvents)},
+		Custom:   EventRange{0, len(m.CustomEvents)},
+		Shame:    EventRange{0, len(m.ShameEvents)},
+	}
+	match.End, _ = ParseLogTime(line)
+
+	if len(m.RoundStats) > 0 {
+		first, last := m.RoundStats[0], m.RoundStats[len(m.RoundStats)-1]
+		match.Server = first.Server
+		match.ScoreCT, match.ScoreT = last.ScoreCT, last.ScoreT
+		match.Overtimes = last.Overtime
+		for _, ps := range first.Players {
+			sid := "[U:1:" + strconv.FormatInt(ps.AccountID, 10) + "]"
+			switch ps.Team {
+			case 3:
+				match.CTRoster = append(match.CTRoster, sid)
+			case 2:
+				match.TRoster = append(match.TRoster, sid)
+			}
+		}
+	}
+
+	// Счёт из строки Game Over точнее: JSON блок последнего раунда пишется до его окончания
+	if gameOver != nil && gameOver[1] != "" {
+		match.ScoreCT, _ = strconv.Atoi(gameOver[1])
+		match.ScoreT, _ = strconv.Atoi(gameOver[2])
+	}
+
+	return match
+}
+
+// newParseResult создает пустой ParseResult
+func newParseResult() *ParseResult {
+	return &ParseResult{
+		Players:        make(map[string]Player),
+		KillEvents:     []KillEvent{},
+		FlashEvents:    []FlashEvent{},
+		DamageEvents:   []DamageEvent{},
+		GrenadeEvents:  []GrenadeEvent{},
+		DefuseEvents:   []DefuseEvent{},
+		BombEvents:     []BombEvent{},
+		HostageEvents:  []HostageEvent{},
+		PurchaseEvents: []PurchaseEvent{},
+		CustomEvents:   []CustomEvent{},
+		ShameEvents:    []ShameEvent{},
+		WeaponSet:      make(map[string]struct{}),
+		RoundStats:     []RoundStats{},
+		Matches:        []Match{},
+		DroppedMatches: []DroppedMatch{},
+	}
+}
+
+// empty проверяет, что в результате нет ни раундов, ни событий
+func (r *ParseResult) empty() bool {
+	return len(r.RoundStats) == 0 && len(r.KillEvents) == 0 && len(r.FlashEvents) == 0 &&
+		len(r.DamageEvents) == 0 && len(r.GrenadeEvents) == 0 && len(r.DefuseEvents) == 0 &&
+		len(r.BombEvents) == 0 && len(r.HostageEvents) == 0 && len(r.PurchaseEvents) == 0 &&
+		len(r.CustomEvents) == 0 && len(r.ShameEvents) == 0
+}
+
+// merge добавляет события other в конец r и сдвигает диапазоны его матчей. Диапазон дат не трогает.
+func (r *ParseResult) merge(other *ParseResult) {
+	for _, match := range other.Matches {
+		match.shift(r)
+		r.Matches = append(r.Matches, match)
+	}
+	r.DroppedMatches = append(r.DroppedMatches, other.DroppedMatches...)
+	r.Diagnostics.Files = append(r.Diagnostics.Files, other.Diagnostics.Files...)
+	for key, player := range other.Players {
+		r.Players[key] = player
+	}
+	for weapon := range other.WeaponSet {
+		r.WeaponSet[weapon] = struct{}{}
+	}
+	r.KillEvents = append(r.KillEvents, other.KillEvents...)
+	r.FlashEvents = append(r.FlashEvents, other.FlashEvents...)
+	r.DamageEvents = append(r.DamageEvents, other.DamageEvents...)
+	r.GrenadeEvents = append(r.GrenadeEvents, other.GrenadeEvents...)
+	r.DefuseEvents = append(r.DefuseEvents, other.DefuseEvents...)
+	r.BombEvents = append(r.BombEvents, other.BombEvents...)
+	r.HostageEvents = append(r.HostageEvents, other.HostageEvents...)
+	r.PurchaseEvents = append(r.PurchaseEvents, other.PurchaseEvents...)
+	r.CustomEvents = append(r.CustomEvents, other.CustomEvents...)
+	r.ShameEvents = append(r.ShameEvents, other.ShameEvents...)
+	r.RoundStats = append(r.RoundStats, other.RoundStats...)
+}
+
+// dateFromFileName извлекает дату из имени файла вида YYYY_MM_DD_HHMMSS
+func dateFromFileName(name string) (time.Time, bool) {
+	matches := fileDateRegex.FindStringSubmatch(filepath.Base(name))
+	if matches == nil {
+		return time.Time{}, false
+	}
+	date, err := time.Parse("2006-01-02", matches[1]+"-"+matches[2]+"-"+matches[3])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return date, true
+}
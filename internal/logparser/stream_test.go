@@ -0,0 +1,414 @@
+package logparser
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testMatchID returns the ID of the n-th match of a source (see matchStream.matchID)
+func testMatchID(source string, n int) string {
+	return fmt.Sprintf("%s#%d@%s", filepath.Base(source), n, sourceHash(source))
+}
+
+// testMatchLog is a minimal log with one complete match and one match that never ended
+const testMatchLog = `L 09/05/2025 - 18:00:00: World triggered "Round_Start"
+L 09/05/2025 - 18:00:01: "Ghost<2><[U:1:100]><CT>" [0 0 0] killed "Warmup<3><[U:1:200]><TERRORIST>" [0 0 0] with "ak47"
+L 09/05/2025 - 18:01:00: World triggered "Match_Start" on "de_dust2"
+L 09/05/2025 - 18:01:20: "Alice<2><[U:1:100]><CT>" threw hegrenade [-100 200 10]
+L 09/05/2025 - 18:01:29: "Alice<2><[U:1:100]><CT>" [-100 200 10] attacked "Bob<3><[U:1:200]><TERRORIST>" [50 60 10] with "m4a1" (damage "27") (damage_armor "3") (health "73") (armor "97") (hitgroup "chest")
+L 09/05/2025 - 18:01:30: "Alice<2><[U:1:100]><CT>" [-100 200 10] attacked "Bob<3><[U:1:200]><TERRORIST>" [50 60 10] with "m4a1" (damage "112") (damage_armor "0") (health "0") (armor "97") (hitgroup "head")
+L 09/05/2025 - 18:01:30: "Alice<2><[U:1:100]><CT>" [-100 200 10] killed "Bob<3><[U:1:200]><TERRORIST>" [50 60 10] with "m4a1"
+L 09/05/2025 - 18:01:35: "Bob<3><[U:1:200]><TERRORIST>" blinded for 2.50 by "Alice<2><[U:1:100]><CT>" from flashbang entindex 123
+L 09/05/2025 - 18:02:00: JSON_BEGIN{
+L 09/05/2025 - 18:02:00: "name" : "round_stats",
+L 09/05/2025 - 18:02:00: "round_number" : "1",
+L 09/05/2025 - 18:02:00: "score_t" : "0",
+L 09/05/2025 - 18:02:00: "score_ct" : "1",
+L 09/05/2025 - 18:02:00: "map" : "de_dust2",
+L 09/05/2025 - 18:02:00: "server" : "Old Farts",
+L 09/05/2025 - 18:02:00: "fields" : "             accountid,   team,  money,  kills, deaths,assists,    dmg,    hsp,    kdr,    adr,    mvp,     ef,     ud,     3k,     4k,     5k,clutchk, firstk,pistolk,sniperk, blindk,  bombk,firedmg,uniquek,  dinks,chickenk",
+L 09/05/2025 - 18:02:00: "players" : {
+L 09/05/2025 - 18:02:00: "player_0" : "                   100,      3,   4200,      1,      0,      0,    100,   0.00,   0.00,    100,      1,      0,      0,      0,      0,      0,      0,      1,      0,      0,      0,      0,      0,      1,      0,      0",
+L 09/05/2025 - 18:02:00: "player_1" : "                   200,      2,   3100,      0,      1,      0,      0,   0.00,   0.00,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0"
+L 09/05/2025 - 18:02:00: }}
+L 09/05/2025 - 18:02:00: JSON_END
+L 09/05/2025 - 18:02:01: Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")
+L 09/05/2025 - 18:30:00: Game Over: competitive de_dust2 score 13:6 after 28 min
+L 09/05/2025 - 18:35:00: World triggered "Match_Start" on "de_mirage"
+L 09/05/2025 - 18:35:30: "Alice<2><[U:1:100]><CT>" [0 0 0] killed "Bob<3><[U:1:200]><TERRORIST>" [0 0 0] with "awp"
+`
+
+// TestParseReader_OnlyCompletedMatches checks that only events between Match_Start and Game Over are kept
+func TestParseReader_OnlyCompletedMatches(t *testing.T) {
+	result, err := New().ParseReader(strings.NewReader(testMatchLog), "logs/2025_09_05_180000.log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.KillEvents) != 1 {
+		t.Fatalf("Expected 1 kill event, got %d", len(result.KillEvents))
+	}
+	if result.KillEvents[0].Weapon != "m4a1" || result.KillEvents[0].Date != "2025-09-05" {
+		t.Errorf("Unexpected kill event: %+v", result.KillEvents[0])
+	}
+	kill := result.KillEvents[0]
+	if kill.Map != "de_dust2" {
+		t.Errorf("Expected map from Match_Start, got %q", kill.Map)
+	}
+	if kill.KillerPos != (Position{X: -100, Y: 200, Z: 10}) || kill.VictimPos != (Position{X: 50, Y: 60, Z: 10}) {
+		t.Errorf("Unexpected positions: %+v → %+v", kill.KillerPos, kill.VictimPos)
+	}
+	// sqrt(150² + 140²) = 205.18…
+	if kill.Distance != 205.2 {
+		t.Errorf("Expected distance 205.2, got %v", kill.Distance)
+	}
+	if _, ok := result.WeaponSet["awp"]; ok {
+		t.Errorf("Weapon from incomplete match must not be in WeaponSet")
+	}
+
+	if len(result.DamageEvents) != 2 {
+		t.Fatalf("Expected 2 damage events, got %d", len(result.DamageEvents))
+	}
+	first, lethal := result.DamageEvents[0], result.DamageEvents[1]
+	if first.Damage != 27 || first.HealthDamage != 27 || first.DamageArmor != 3 || first.Hitgroup != "chest" || first.Round != 1 {
+		t.Errorf("Unexpected damage event: %+v", first)
+	}
+	// Overkill headshot only removes the 73 HP that were left
+	if lethal.Damage != 112 || lethal.HealthDamage != 73 || lethal.Health != 0 || lethal.Hitgroup != "head" {
+		t.Errorf("Unexpected lethal damage event: %+v", lethal)
+	}
+
+	if len(result.GrenadeEvents) != 1 || result.GrenadeEvents[0].Grenade != "hegrenade" || result.GrenadeEvents[0].Round != 1 {
+		t.Errorf("Unexpected grenade events: %+v", result.GrenadeEvents)
+	}
+
+	if len(result.FlashEvents) != 1 || result.FlashEvents[0].Duration != 2.5 {
+		t.Errorf("Unexpected flash events: %+v", result.FlashEvents)
+	}
+
+	if len(result.RoundStats) != 1 {
+		t.Fatalf("Expected 1 round, got %d", len(result.RoundStats))
+	}
+	round := result.RoundStats[0]
+	if round.Winner != 3 || round.Map != "de_dust2" || len(round.Players) != 2 {
+		t.Errorf("Unexpected round: %+v", round)
+	}
+	if round.Players[0].Rating == 0 {
+		t.Errorf("Expected rating to be calculated after match commit")
+	}
+
+	if result.StartDate != "05-09-2025" || result.EndDate != "05-09-2025" {
+		t.Errorf("Expected date range from source name, got %s — %s", result.StartDate, result.EndDate)
+	}
+}
+
+// TestParseReader_Matches checks that completed matches are returned with metadata and event ranges
+func TestParseReader_Matches(t *testing.T) {
+	second := `L 09/05/2025 - 19:00:00: World triggered "Match_Start" on "de_nuke"
+L 09/05/2025 - 19:00:30: "Bob<3><[U:1:200]><CT>" [0 0 0] killed "Alice<2><[U:1:100]><TERRORIST>" [0 0 0] with "deagle"
+L 09/05/2025 - 19:00:40: "Bob<3><[U:1:200]><CT>" [0 0 0] killed "Carol<4><[U:1:300]><TERRORIST>" [0 0 0] with "deagle"
+L 09/05/2025 - 19:40:00: Game Over: competitive de_nuke score 4:13 after 40 min
+`
+	result, err := New().ParseReader(strings.NewReader(testMatchLog+second), "logs/2025_09_05_180000.log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Matches) != 2 {
+		t.Fatalf("Expected 2 matches, got %d", len(result.Matches))
+	}
+	first := result.Matches[0]
+	if first.ID != testMatchID("logs/2025_09_05_180000.log", 1) || first.Source != "logs/2025_09_05_180000.log" {
+		t.Errorf("Unexpected match identity: %q from %q", first.ID, first.Source)
+	}
+	if first.Map != "de_dust2" || first.Server != "Old Farts" || first.ScoreCT != 13 || first.ScoreT != 6 {
+		t.Errorf("Unexpected match metadata: %+v", first)
+	}
+	wantStart := time.Date(2025, 9, 5, 18, 1, 0, 0, time.UTC)
+	wantEnd := time.Date(2025, 9, 5, 18, 30, 0, 0, time.UTC)
+	if !first.Start.Equal(wantStart) || !first.End.Equal(wantEnd) {
+		t.Errorf("Unexpected match bounds: %v — %v", first.Start, first.End)
+	}
+	if len(first.CTRoster) != 1 || first.CTRoster[0] != "[U:1:100]" || len(first.TRoster) != 1 || first.TRoster[0] != "[U:1:200]" {
+		t.Errorf("Unexpected rosters: CT %v, T %v", first.CTRoster, first.TRoster)
+	}
+	if first.Rounds != (EventRange{0, 1}) || first.Kills != (EventRange{0, 1}) || first.Damage.Len() != 2 {
+		t.Errorf("Unexpected first match ranges: %+v", first)
+	}
+	if result.RoundStats[0].MatchID != first.ID {
+		t.Errorf("Expected round to reference match %q, got %q", first.ID, result.RoundStats[0].MatchID)
+	}
+
+	// Every event carries its match, round and full timestamp
+	kill := result.KillEvents[0]
+	if kill.MatchID != first.ID || kill.Round != 1 || !kill.Time.Equal(time.Date(2025, 9, 5, 18, 1, 30, 0, time.UTC)) {
+		t.Errorf("Unexpected kill stamps: match %q, round %d, time %v", kill.MatchID, kill.Round, kill.Time)
+	}
+	flash := result.FlashEvents[0]
+	if flash.MatchID != first.ID || flash.Round != 1 || flash.Time.Second() != 35 {
+		t.Errorf("Unexpected flash stamps: match %q, round %d, time %v", flash.MatchID, flash.Round, flash.Time)
+	}
+	if result.DamageEvents[0].MatchID != first.ID || result.GrenadeEvents[0].MatchID != first.ID {
+		t.Errorf("Expected damage and grenade events to reference match %q", first.ID)
+	}
+
+	// Ranges of the second match point past the events of the first one
+	nuke := result.Matches[1]
+	if nuke.ID != testMatchID("logs/2025_09_05_180000.log", 2) || nuke.ScoreCT != 4 || nuke.ScoreT != 13 {
+		t.Errorf("Unexpected second match: %+v", nuke)
+	}
+	if nuke.Kills != (EventRange{1, 3}) || nuke.Rounds.Len() != 0 {
+		t.Errorf("Unexpected second match ranges: kills %+v, rounds %+v", nuke.Kills, nuke.Rounds)
+	}
+	for _, kill := range result.KillEvents[nuke.Kills.From:nuke.Kills.To] {
+		if kill.Map != "de_nuke" {
+			t.Errorf("Kill outside of second match in its range: %+v", kill)
+		}
+	}
+}
+
+// TestParseReader_RestartDropsPreviousMatch checks that a new Match_Start discards the unfinished match
+func TestParseReader_RestartDropsPreviousMatch(t *testing.T) {
+	log := `L 09/05/2025 - 18:01:00: World triggered "Match_Start" on "de_dust2"
+L 09/05/2025 - 18:01:30: "Alice<2><[U:1:100]><CT>" [0 0 0] killed "Bob<3><[U:1:200]><TERRORIST>" [0 0 0] with "glock"
+L 09/05/2025 - 18:02:00: World triggered "Match_Start" on "de_dust2"
+L 09/05/2025 - 18:02:30: "Bob<3><[U:1:200]><TERRORIST>" [0 0 0] killed "Alice<2><[U:1:100]><CT>" [0 0 0] with "usp_silencer"
+L 09/05/2025 - 18:30:00: Game Over: competitive de_dust2 score 13:6 after 28 min
+`
+	result, err := New().ParseReader(strings.NewReader(log), "stdin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.KillEvents) != 1 || result.KillEvents[0].Weapon != "usp_silencer" {
+		t.Errorf("Expected only kill from restarted match, got %+v", result.KillEvents)
+	}
+	if result.StartDate != "" {
+		t.Errorf("Expected empty date range for source without date, got %q", result.StartDate)
+	}
+}
+
+// TestParseReader_BombAndWinConditions checks plant attribution to rounds and round win conditions
+func TestParseReader_BombAndWinConditions(t *testing.T) {
+	jsonBlock := func(round, scoreT, scoreCT string) string {
+		return `L 09/05/2025 - 18:05:00: JSON_BEGIN{
+L 09/05/2025 - 18:05:00: "name" : "round_stats",
+L 09/05/2025 - 18:05:00: "round_number" : "` + round + `",
+L 09/05/2025 - 18:05:00: "score_t" : "` + scoreT + `",
+L 09/05/2025 - 18:05:00: "score_ct" : "` + scoreCT + `",
+L 09/05/2025 - 18:05:00: "map" : "de_inferno",
+L 09/05/2025 - 18:05:00: }}
+L 09/05/2025 - 18:05:00: JSON_END
+`
+	}
+	log := `L 09/05/2025 - 18:01:00: World triggered "Match_Start" on "de_inferno"
+L 09/05/2025 - 18:01:05: "Bob<3><[U:1:200]><TERRORIST>" triggered "Got_The_Bomb"
+L 09/05/2025 - 18:01:10: "Bob<3><[U:1:200]><TERRORIST>" triggered "Dropped_The_Bomb"
+L 09/05/2025 - 18:01:15: "Eve<4><[U:1:300]><TERRORIST>" triggered "Got_The_Bomb"
+L 09/05/2025 - 18:01:40: "Eve<4><[U:1:300]><TERRORIST>" triggered "Planted_The_Bomb" at bombsite B
+` + jsonBlock("1", "1", "0") + `L 09/05/2025 - 18:05:01: Team "TERRORIST" triggered "SFUI_Notice_Target_Bombed" (CT "0") (T "1")
+` + jsonBlock("2", "1", "1") + `L 09/05/2025 - 18:08:01: Team "CT" triggered "SFUI_Notice_Target_Saved" (CT "1") (T "1")
+L 09/05/2025 - 18:30:00: Game Over: competitive de_inferno score 13:6 after 28 min
+`
+	result, err := New().ParseReader(strings.NewReader(log), "stdin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.RoundStats) != 2 {
+		t.Fatalf("Expected 2 rounds, got %d", len(result.RoundStats))
+	}
+	first, second := result.RoundStats[0], result.RoundStats[1]
+	if first.BombSite != "B" || first.PlanterSID != "[U:1:300]" || first.Winner != 2 || first.WinCondition != WinBombExploded {
+		t.Errorf("Unexpected first round: %+v", first)
+	}
+	if second.BombSite != "" || second.Winner != 3 || second.WinCondition != WinTimeRanOut {
+		t.Errorf("Unexpected second round: %+v", second)
+	}
+
+	if len(result.BombEvents) != 4 {
+		t.Fatalf("Expected 4 bomb events, got %d", len(result.BombEvents))
+	}
+	if result.BombEvents[1].EventType != "drop" || result.BombEvents[3].EventType != "plant" || result.BombEvents[3].Round != 1 {
+		t.Errorf("Unexpected bomb events: %+v", result.BombEvents)
+	}
+
+	// Bomb explosion is still reported as a defuse event
+	if len(result.DefuseEvents) != 1 || result.DefuseEvents[0].EventType != "failed" {
+		t.Fatalf("Expected bomb explosion defuse event, got %+v", result.DefuseEvents)
+	}
+	// The explosion line follows the round's JSON block but still belongs to that round
+	if result.DefuseEvents[0].Round != 1 {
+		t.Errorf("Expected explosion in round 1, got %d", result.DefuseEvents[0].Round)
+	}
+}
+
+// TestParseReader_DefuseRoundEPI pins EPI for a round won by a defuse: since EPIVersion 2 it gets the win factor
+func TestParseReader_DefuseRoundEPI(t *testing.T) {
+	log := `L 09/05/2025 - 18:01:00: World triggered "Match_Start" on "de_inferno"
+L 09/05/2025 - 18:05:00: JSON_BEGIN{
+L 09/05/2025 - 18:05:00: "name" : "round_stats",
+L 09/05/2025 - 18:05:00: "round_number" : "1",
+L 09/05/2025 - 18:05:00: "map" : "de_inferno",
+L 09/05/2025 - 18:05:00: "fields" : "             accountid,   team,  money,  kills, deaths,assists,    dmg,    hsp,    kdr,    adr,    mvp,     ef,     ud,     3k,     4k,     5k,clutchk, firstk,pistolk,sniperk, blindk,  bombk,firedmg,uniquek,  dinks,chickenk",
+L 09/05/2025 - 18:05:00: "players" : {
+L 09/05/2025 - 18:05:00: "player_0" : "                   100,      2,   1000,      0,      0,      0,      0,   0.00,   0.00,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0",
+L 09/05/2025 - 18:05:00: "player_1" : "                   200,      3,    400,      0,      0,      0,    100,   0.00,   0.00,    100,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0"
+L 09/05/2025 - 18:05:00: }}
+L 09/05/2025 - 18:05:00: JSON_END
+L 09/05/2025 - 18:05:01: Team "CT" triggered "SFUI_Notice_Bomb_Defused" (CT "1") (T "0")
+L 09/05/2025 - 18:30:00: Game Over: competitive de_inferno score 13:6 after 28 min
+`
+	result, err := New().ParseReader(strings.NewReader(log), "stdin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.RoundStats) != 1 || result.RoundStats[0].Winner != 3 {
+		t.Fatalf("Expected one round won by CT, got %+v", result.RoundStats)
+	}
+
+	// CT 1v1 with 100 damage: (100/100) * (5/1)^0.7 * (1/1)^0.5 = 3.085, win factor 1.10 → 3.394
+	ct := result.RoundStats[0].Players[1]
+	if math.Abs(ct.Rating-3.394) > 0.001 {
+		t.Errorf("Expected defuse round EPI ~3.394, got %.4f", ct.Rating)
+	}
+	if t0 := result.RoundStats[0].Players[0]; t0.Rating != 0 {
+		t.Errorf("Expected zero EPI for the losing T without damage, got %.4f", t0.Rating)
+	}
+}
+
+// TestParseReader_HostageRescue checks hostage events and hostage-rescue round wins
+func TestParseReader_HostageRescue(t *testing.T) {
+	log := `L 09/05/2025 - 18:01:00: World triggered "Match_Start" on "cs_office"
+L 09/05/2025 - 18:01:20: "Alice<2><[U:1:100]><CT>" triggered "Touched_A_Hostage"
+L 09/05/2025 - 18:01:40: "Alice<2><[U:1:100]><CT>" triggered "Rescued_A_Hostage"
+L 09/05/2025 - 18:01:45: "Bob<3><[U:1:200]><TERRORIST>" triggered "Killed_A_Hostage"
+L 09/05/2025 - 18:01:50: "Carl<4><[U:1:300]><CT>" triggered "Rescued_A_Hostage"
+L 09/05/2025 - 18:02:00: JSON_BEGIN{
+L 09/05/2025 - 18:02:00: "name" : "round_stats",
+L 09/05/2025 - 18:02:00: "round_number" : "1",
+L 09/05/2025 - 18:02:00: "map" : "cs_office",
+L 09/05/2025 - 18:02:00: }}
+L 09/05/2025 - 18:02:00: JSON_END
+L 09/05/2025 - 18:02:01: Team "CT" triggered "SFUI_Notice_All_Hostages_Rescued" (CT "1") (T "0")
+L 09/05/2025 - 18:30:00: Game Over: competitive cs_office score 13:6 after 28 min
+`
+	result, err := New().ParseReader(strings.NewReader(log), "stdin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.HostageEvents) != 4 {
+		t.Fatalf("Expected 4 hostage events, got %d", len(result.HostageEvents))
+	}
+	got := []string{}
+	for _, e := range result.HostageEvents {
+		got = append(got, e.EventType)
+		if e.Round != 1 {
+			t.Errorf("Expected hostage event in round 1, got %+v", e)
+		}
+	}
+	if strings.Join(got, ",") != "pickup,rescue,kill,rescue" {
+		t.Errorf("Unexpected hostage event types: %v", got)
+	}
+
+	round := result.RoundStats[0]
+	if round.HostagesRescued != 2 || round.Winner != 3 || round.WinCondition != WinHostageRescued {
+		t.Errorf("Unexpected hostage round: %+v", round)
+	}
+}
+
+// abortedMatchesLog has one completed match after a restart and three matches that never reach Game Over
+const abortedMatchesLog = `L 09/05/2025 - 18:00:00: World triggered "Match_Start" on "de_dust2"
+L 09/05/2025 - 18:00:30: "Alice<2><[U:1:100]><CT>" [0 0 0] killed "Bob<3><[U:1:200]><TERRORIST>" [0 0 0] with "glock"
+L 09/05/2025 - 18:01:00: World triggered "Restart_Round_(1_second)"
+L 09/05/2025 - 18:01:30: "Bob<3><[U:1:200]><TERRORIST>" [0 0 0] killed "Alice<2><[U:1:100]><CT>" [0 0 0] with "usp_silencer"
+L 09/05/2025 - 18:30:00: Game Over: competitive de_dust2 score 13:6 after 28 min
+L 09/05/2025 - 18:35:00: World triggered "Match_Start" on "de_nuke"
+L 09/05/2025 - 18:35:30: "Alice<2><[U:1:100]><CT>" [0 0 0] killed "Bob<3><[U:1:200]><TERRORIST>" [0 0 0] with "awp"
+L 09/05/2025 - 18:36:00: Loading map "de_mirage"
+L 09/05/2025 - 18:40:00: World triggered "Match_Start" on "de_mirage"
+L 09/05/2025 - 18:40:30: "Alice<2><[U:1:100]><CT>" [0 0 0] killed "Bob<3><[U:1:200]><TERRORIST>" [0 0 0] with "deagle"
+L 09/05/2025 - 18:41:00: World triggered "Match_Start" on "de_mirage"
+L 09/05/2025 - 18:41:30: World triggered "Match_Start" on "de_mirage"
+L 09/05/2025 - 18:42:00: "Alice<2><[U:1:100]><CT>" [0 0 0] killed "Bob<3><[U:1:200]><TERRORIST>" [0 0 0] with "ak47"
+`
+
+// TestParseReader_DroppedMatches checks that incomplete matches are dropped and reported with a reason
+func TestParseReader_DroppedMatches(t *testing.T) {
+	result, err := New().ParseReader(strings.NewReader(abortedMatchesLog), "stdin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Matches) != 1 || len(result.KillEvents) != 1 || result.KillEvents[0].Weapon != "usp_silencer" {
+		t.Errorf("Expected only the restarted match to be kept, got %d matches, kills %+v", len(result.Matches), result.KillEvents)
+	}
+
+	// The empty match between two Match_Start lines is not reported
+	var reasons []string
+	for _, dropped := range result.DroppedMatches {
+		reasons = append(reasons, dropped.Map+":"+dropped.Reason)
+	}
+	want := []string{"de_dust2:" + AbortRestart, "de_nuke:" + AbortMapChange, "de_mirage:" + AbortNewMatch, "de_mirage:" + AbortLogEnded}
+	if strings.Join(reasons, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected dropped matches %v, got %v", want, reasons)
+	}
+}
+
+// TestParseReader_KeepPartial checks that incomplete matches are kept and flagged when requested
+func TestParseReader_KeepPartial(t *testing.T) {
+	p := New()
+	p.SetKeepPartial(true)
+	result, err := p.ParseReader(strings.NewReader(abortedMatchesLog), "stdin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Matches) != 5 || len(result.KillEvents) != 5 || len(result.DroppedMatches) != 0 {
+		t.Fatalf("Expected 5 matches with 5 kills and nothing dropped, got %d matches, %d kills, %d dropped",
+			len(result.Matches), len(result.KillEvents), len(result.DroppedMatches))
+	}
+	restarted, completed, last := result.Matches[0], result.Matches[1], result.Matches[4]
+	if !restarted.Partial || restarted.AbortReason != AbortRestart || restarted.Kills != (EventRange{0, 1}) {
+		t.Errorf("Unexpected restarted match: %+v", restarted)
+	}
+	if completed.Partial || completed.AbortReason != "" || completed.ScoreCT != 13 {
+		t.Errorf("Unexpected completed match: %+v", completed)
+	}
+	// A partial match ends at its last line
+	if !last.Partial || last.AbortReason != AbortLogEnded || last.End.Minute() != 42 {
+		t.Errorf("Unexpected last match: %+v", last)
+	}
+}
+
+// TestRoundPhase checks halftime and overtime bookkeeping for MR12 and custom cvars
+func TestRoundPhase(t *testing.T) {
+	tests := []struct {
+		round, maxRounds, otMaxRounds int
+		overtime, half                int
+	}{
+		{0, 24, 6, 0, 0},
+		{1, 24, 6, 0, 1},
+		{12, 24, 6, 0, 1},
+		{13, 24, 6, 0, 2},
+		{24, 24, 6, 0, 2},
+		{25, 24, 6, 1, 1},
+		{27, 24, 6, 1, 1},
+		{28, 24, 6, 1, 2},
+		{31, 24, 6, 2, 1},
+		{16, 30, 6, 0, 2},
+		{36, 30, 10, 1, 2},
+	}
+	for _, tt := range tests {
+		overtime, half := roundPhase(tt.round, tt.maxRounds, tt.otMaxRounds)
+		if overtime != tt.overtime || half != tt.half {
+			t.Errorf("roundPhase(%d, %d, %d) = %d, %d; want %d, %d",
+				tt.round, tt.maxRounds, tt.otMaxRounds, overtime, half, tt.overtime, tt.half)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package logparser
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Грамматика строки события CS2:
+//
+//	L MM/DD/YYYY - HH:MM:SS: <субъект> [<позиция>] <глагол> <аргументы>
+//
+// Субъект — игрок "name<uid><steamid><team>", команда Team "CT" или World. Ник может содержать
+// любые символы, включая '<', '>' и кавычки, поэтому игрок разбирается с конца: три поля <...>
+// перед закрывающей кавычкой, за которой идёт пробел или конец строки.
+
+// Субъекты строки события
+const (
+	SubjectNone   = ""       // Строка другого вида (server_cvar, Game Over, JSON блок, ...)
+	SubjectPlayer = "player" // "name<uid><steamid><team>"
+	SubjectTeam   = "team"   // Team "CT"
+	SubjectWorld  = "world"  // World
+)
+
+// PlayerRef — ссылка на игрока в строке лога: "name<uid><steamid><team>"
+type PlayerRef struct {
+	Name    string // Ник как есть, с любыми символами
+	UserID  int    // Номер слота на сервере
+	SteamID string // [U:1:N], BOT, Console
+	Team    string // CT, TERRORIST, Unassigned, Spectator или пусто
+}
+
+// AccountIDFromSID извлекает Steam Account ID из SteamID вида [U:1:N]; BOT, Console и прочее — false
+func AccountIDFromSID(sid string) (int64, bool) {
+	if !strings.HasPrefix(sid, "[U:1:") || !strings.HasSuffix(sid, "]") {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(sid[len("[U:1:"):len(sid)-1], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// logPrefixLen — длина префикса "L MM/DD/YYYY - HH:MM:SS: "
+const logPrefixLen = len("L ") + len(logTimeLayout) + len(": ")
+
+// tokenizeLine разбирает строку на префикс времени, субъект, позицию, глагол и аргументы
+func tokenizeLine(raw string) LogLine {
+	line := LogLine{Raw: raw}
+	if !splitLogPrefix(&line) {
+		return line
+	}
+
+	c := lineCursor{s: line.Message}
+	switch {
+	case c.peek() == '"':
+		actor, ok := c.player()
+		if !ok {
+			return line
+		}
+		line.Subject, line.Actor = SubjectPlayer, actor
+		line.ActorPos, line.HasActorPos = c.position()
+	case c.literal("Team "):
+		team, ok := c.quoted()
+		if !ok {
+			return line
+		}
+		line.Subject, line.Actor = SubjectTeam, PlayerRef{Team: team}
+	case c.literal("World "):
+		line.Subject = SubjectWorld
+	default:
+		return line
+	}
+
+	line.Verb = c.word()
+	c.skipSpaces()
+	line.Args = c.rest()
+	return line
+}
+
+// splitLogPrefix проверяет префикс "L MM/DD/YYYY - HH:MM:SS: " и заполняет Date, Time и Message
+func splitLogPrefix(line *LogLine) bool {
+	raw := line.Raw
+	if len(raw) < logPrefixLen || raw[0] != 'L' || raw[1] != ' ' ||
+		raw[4] != '/' || raw[7] != '/' || raw[12:15] != " - " ||
+		raw[17] != ':' || raw[20] != ':' || raw[23:25] != ": " {
+		// Нестандартный префикс (например, месяц без ведущего нуля) — медленный путь
+		line.Date = ExtractDateFromLogLine(raw)
+		line.Time, _ = ParseLogTime(raw)
+		return false
+	}
+
+	month, ok1 := digits(raw[2:4])
+	day, ok2 := digits(raw[5:7])
+	year, ok3 := digits(raw[8:12])
+	hour, ok4 := digits(raw[15:17])
+	minute, ok5 := digits(raw[18:20])
+	sec, ok6 := digits(raw[21:23])
+	if !(ok1 && ok2 && ok3 && ok4 && ok5 && ok6) {
+		return false
+	}
+	line.Date = raw[8:12] + "-" + raw[2:4] + "-" + raw[5:7]
+	line.Time = time.Date(year, time.Month(month), day, hour, minute, sec, 0, time.UTC)
+	line.Message = raw[logPrefixLen:]
+	return true
+}
+
+// digits разбирает неотрицательное десятичное число без знака и пробелов
+func digits(s string) (int, bool) {
+	if s == "" {
+		return 0, false
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch < '0' || ch > '9' {
+			return 0, false
+		}
+		n = n*10 + int(ch-'0')
+	}
+	return n, true
+}
+
+// lineCursor последовательно читает токены из текста события
+type lineCursor struct {
+	s string
+	i int
+}
+
+// peek возвращает текущий байт (0 в конце строки)
+func (c *lineCursor) peek() byte {
+	if c.i >= len(c.s) {
+		return 0
+	}
+	return c.s[c.i]
+}
+
+// skipSpaces пропускает пробелы
+func (c *lineCursor) skipSpaces() {
+	for c.i < len(c.s) && c.s[c.i] == ' ' {
+		c.i++
+	}
+}
+
+// literal пропускает пробелы и lit, если текст продолжается им
+func (c *lineCursor) literal(lit string) bool {
+	c.skipSpaces()
+	if !strings.HasPrefix(c.s[c.i:], lit) {
+		return false
+	}
+	c.i += len(lit)
+	return true
+}
+
+// word читает слово до пробела
+func (c *lineCursor) word() string {
+	c.skipSpaces()
+	start := c.i
+	for c.i < len(c.s) && c.s[c.i] != ' ' {
+		c.i++
+	}
+	return c.s[start:c.i]
+}
+
+// quoted читает строку в кавычках без экранирования: "ak47"
+func (c *lineCursor) quoted() (string, bool) {
+	c.skipSpaces()
+	if c.peek() != '"' {
+		return "", false
+	}
+	end := strings.IndexByte(c.s[c.i+1:], '"')
+	if end < 0 {
+		return "", false
+	}
+	value := c.s[c.i+1 : c.i+1+end]
+	c.i += end + 2
+	return value, true
+}
+
+// rest возвращает непрочитанный остаток
+func (c *lineCursor) rest() string {
+	return c.s[c.i:]
+}
+
+// position читает координаты "[x y z]"; при неудаче курсор не двигается
+func (c *lineCursor) position() (Position, bool) {
+	start := c.i
+	c.skipSpaces()
+	if c.peek() != '[' {
+		c.i = start
+		return Position{}, false
+	}
+	end := strings.IndexByte(c.s[c.i:], ']')
+	if end < 0 {
+		c.i = start
+		return Position{}, false
+	}
+	pos, ok := parsePosition(c.s[c.i+1 : c.i+end])
+	c.i += end + 1
+	return pos, ok
+}
+
+// player читает ссылку на игрока "name<uid><steamid><team>". Ник может содержать '<', '>' и кавычки:
+// ищется первая закрывающая `>"` перед пробелом или концом строки, перед которой стоят три поля.
+func (c *lineCursor) player() (PlayerRef, bool) {
+	c.skipSpaces()
+	if c.peek() != '"' {
+		return PlayerRef{}, false
+	}
+	s := c.s[c.i:]
+	for from := 1; from < len(s); {
+		end := strings.Index(s[from:], `>"`)
+		if end < 0 {
+			return PlayerRef{}, false
+		}
+		end += from // s[end] == '>'
+		from = end + 1
+		if end+2 < len(s) && s[end+2] != ' ' {
+			continue
+		}
+		if ref, ok := parsePlayerFields(s[1 : end+1]); ok {
+			c.i += end + 2
+			return ref, true
+		}
+	}
+	return PlayerRef{}, false
+}
+
+// parsePlayerFields разбирает "name<uid><steamid><team>" с конца
+func parsePlayerFields(s string) (PlayerRef, bool) {
+	// <team>
+	open := strings.LastIndexByte(s, '<')
+	if open < 1 || s[open-1] != '>' {
+		return PlayerRef{}, false
+	}
+	team := s[open+1 : len(s)-1]
+	s = s[:open]
+
+	// <steamid>
+	open = strings.LastIndexByte(s, '<')
+	if open < 1 || s[open-1] != '>' || open+1 >= len(s)-1 {
+		return PlayerRef{}, false
+	}
+	steamID := s[open+1 : len(s)-1]
+	s = s[:open]
+
+	// <uid>
+	open = strings.LastIndexByte(s, '<')
+	if open < 0 {
+		return PlayerRef{}, false
+	}
+	uid, ok := digits(s[open+1 : len(s)-1])
+	if !ok || strings.ContainsAny(team, `<>"`) || strings.ContainsAny(steamID, `<>"`) {
+		return PlayerRef{}, false
+	}
+	return PlayerRef{Name: s[:open], UserID: uid, SteamID: steamID, Team: team}, true
+}
+
+// parseFloat разбирает число с плавающей точкой, 0 при ошибке
+func parseFloat(s string) float64 {
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
@@ -0,0 +1,166 @@
+package logparser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestTokenizeLine_Subjects checks prefix, subject, position, verb and args for each subject kind
+func TestTokenizeLine_Subjects(t *testing.T) {
+	line := tokenizeLine(`L 09/05/2025 - 18:01:30: "Alice<2><[U:1:100]><CT>" [-100 200 10] killed "Bob<3><[U:1:200]><TERRORIST>" [50 60 10] with "ak47"`)
+	if line.Date != "2025-09-05" || line.Time.Format("15:04:05") != "18:01:30" {
+		t.Errorf("Unexpected date/time: %s %s", line.Date, line.Time)
+	}
+	if line.Subject != SubjectPlayer || line.Actor != (PlayerRef{Name: "Alice", UserID: 2, SteamID: "[U:1:100]", Team: "CT"}) {
+		t.Errorf("Unexpected actor: %s %+v", line.Subject, line.Actor)
+	}
+	if !line.HasActorPos || line.ActorPos != (Position{X: -100, Y: 200, Z: 10}) {
+		t.Errorf("Unexpected actor position: %+v", line.ActorPos)
+	}
+	if line.Verb != "killed" || !strings.HasPrefix(line.Args, `"Bob<3>`) {
+		t.Errorf("Unexpected verb/args: %q %q", line.Verb, line.Args)
+	}
+
+	team := tokenizeLine(`L 09/05/2025 - 18:02:01: Team "TERRORIST" triggered "SFUI_Notice_Terrorists_Win" (CT "0") (T "1")`)
+	if team.Subject != SubjectTeam || team.Actor.Team != "TERRORIST" || team.Verb != "triggered" {
+		t.Errorf("Unexpected team line: %+v", team)
+	}
+
+	world := tokenizeLine(`L 09/05/2025 - 18:01:00: World triggered "Round_Start"`)
+	if world.Subject != SubjectWorld || world.Verb != "triggered" || world.Args != `"Round_Start"` {
+		t.Errorf("Unexpected world line: %+v", world)
+	}
+
+	other := tokenizeLine(`L 09/05/2025 - 18:01:00: server_cvar: "mp_maxrounds" "24"`)
+	if other.Subject != SubjectNone || other.Verb != "" || other.Message != `server_cvar: "mp_maxrounds" "24"` {
+		t.Errorf("Unexpected plain line: %+v", other)
+	}
+
+	// A month without a leading zero does not fit the fixed layout and goes through the slow path
+	slow := tokenizeLine(`L 9/05/2025 - 18:01:00: World triggered "Round_Start"`)
+	if slow.Date != "2025-09-05" || slow.Subject != SubjectNone {
+		t.Errorf("Unexpected slow-path line: %+v", slow)
+	}
+}
+
+// TestLineCursor_PlayerNames checks player references whose names contain brackets, quotes and spaces
+func TestLineCursor_PlayerNames(t *testing.T) {
+	tests := []struct {
+		text string
+		want PlayerRef
+		rest string
+	}{
+		{`"Alice<2><[U:1:100]><CT>" killed`, PlayerRef{"Alice", 2, "[U:1:100]", "CT"}, " killed"},
+		{`"C<a>rl<4><[U:1:102]><CT>" [0 0 0]`, PlayerRef{"C<a>rl", 4, "[U:1:102]", "CT"}, " [0 0 0]"},
+		{`"F"o"x<7><[U:1:105]><TERRORIST>" say "gg"`, PlayerRef{`F"o"x`, 7, "[U:1:105]", "TERRORIST"}, ` say "gg"`},
+		{`"H> "al<9><[U:1:107]><TERRORIST>"`, PlayerRef{`H> "al`, 9, "[U:1:107]", "TERRORIST"}, ""},
+		{`"Bot Kelvin<11><BOT><>" purchased`, PlayerRef{"Bot Kelvin", 11, "BOT", ""}, " purchased"},
+		{`"Console<0><Console><Console>" say`, PlayerRef{"Console", 0, "Console", "Console"}, " say"},
+	}
+	for _, tt := range tests {
+		c := lineCursor{s: tt.text}
+		got, ok := c.player()
+		if !ok || got != tt.want || c.rest() != tt.rest {
+			t.Errorf("player(%q) = %+v, %v, rest %q; want %+v, rest %q", tt.text, got, ok, c.rest(), tt.want, tt.rest)
+		}
+	}
+
+	for _, bad := range []string{`Alice<2><[U:1:100]><CT>"`, `"Alice<x><[U:1:100]><CT>"`, `"Alice<2><><CT>"`, `"Alice"`} {
+		c := lineCursor{s: bad}
+		if got, ok := c.player(); ok {
+			t.Errorf("player(%q) should fail, got %+v", bad, got)
+		}
+	}
+}
+
+// TestAccountIDFromSID checks Account ID extraction from [U:1:N] and rejection of everything else
+func TestAccountIDFromSID(t *testing.T) {
+	if id, ok := AccountIDFromSID("[U:1:26840160]"); !ok || id != 26840160 {
+		t.Errorf("AccountIDFromSID([U:1:26840160]) = %d, %v", id, ok)
+	}
+	for _, bad := range []string{"", "BOT", "Console", "STEAM_ID_PENDING", "[U:1:]", "[U:1:0]", "[U:1:12x]", "[U:1:123"} {
+		if id, ok := AccountIDFromSID(bad); ok {
+			t.Errorf("AccountIDFromSID(%q) should fail, got %d", bad, id)
+		}
+	}
+}
+
+// TestParseMatchLine_WeirdNames checks that events keep names with special characters and both teams
+func TestParseMatchLine_WeirdNames(t *testing.T) {
+	p := New()
+	match := newParseResult()
+
+	p.parseMatchLine(`L 09/05/2025 - 18:01:30: "H> "al<9><[U:1:107]><TERRORIST>" [0 0 0] killed "C<a>rl<4><[U:1:102]><CT>" [30 40 0] with "ak47" (headshot)`, match)
+	if len(match.KillEvents) != 1 {
+		t.Fatalf("Expected 1 kill, got %d", len(match.KillEvents))
+	}
+	kill := match.KillEvents[0]
+	if kill.KillerName != `H> "al` || kill.VictimName != "C<a>rl" || kill.KillerTeam != "TERRORIST" || kill.VictimTeam != "CT" {
+		t.Errorf("Unexpected kill: %+v", kill)
+	}
+	if !kill.Headshot || kill.Distance != 50 || kill.Weapon != "ak47" {
+		t.Errorf("Unexpected kill details: %+v", kill)
+	}
+
+	p.parseMatchLine(`L 09/05/2025 - 18:01:22: "F"o"x<7><[U:1:105]><TERRORIST>" blinded for 2.50 by "F"o"x<7><[U:1:105]><TERRORIST>" from flashbang entindex 276`, match)
+	if len(match.FlashEvents) != 1 {
+		t.Fatalf("Expected 1 flash, got %d", len(match.FlashEvents))
+	}
+	flash := match.FlashEvents[0]
+	if flash.VictimName != `F"o"x` || flash.FlasherName != `F"o"x` || flash.Duration != 2.5 || !flash.TeamFlash {
+		t.Errorf("Unexpected flash: %+v", flash)
+	}
+}
+
+// benchmarkLog builds a deterministic server log with the given number of 24-round matches
+func benchmarkLog(matches int) string {
+	var b strings.Builder
+	sec := 0
+	stamp := func() string {
+		sec++
+		return fmt.Sprintf("L 09/05/2025 - %02d:%02d:%02d: ", 10+sec/3600%12, sec/60%60, sec%60)
+	}
+	ref := func(i, round int) string {
+		team := "CT"
+		if (i < 5) != (round <= 12) {
+			team = "TERRORIST"
+		}
+		return fmt.Sprintf(`"Player %d<%d><[U:1:%d]><%s>"`, i, i+2, 100+i, team)
+	}
+
+	for m := 0; m < matches; m++ {
+		b.WriteString(stamp() + `World triggered "Match_Start" on "de_dust2"` + "\n")
+		for round := 1; round <= 24; round++ {
+			for i := 0; i < 10; i++ {
+				b.WriteString(stamp() + ref(i, round) + ` purchased "ak47"` + "\n")
+			}
+			for k := 0; k < 6; k++ {
+				a, v := (round+k)%10, (round+k+5)%10
+				b.WriteString(stamp() + ref(a, round) + ` threw flashbang [-100 200 10] flashbang entindex 276)` + "\n")
+				b.WriteString(stamp() + ref(v, round) + ` blinded for 1.20 by ` + ref(a, round) + ` from flashbang entindex 276` + "\n")
+				b.WriteString(stamp() + ref(a, round) + ` [-100 200 10] attacked ` + ref(v, round) +
+					` [50 60 10] with "ak47" (damage "100") (damage_armor "3") (health "0") (armor "97") (hitgroup "head")` + "\n")
+				b.WriteString(stamp() + ref(a, round) + ` [-100 200 10] killed ` + ref(v, round) + ` [50 60 10] with "ak47" (headshot)` + "\n")
+				b.WriteString(stamp() + ref(v, round) + ` say "gg"` + "\n")
+			}
+			b.WriteString(stamp() + ref(0, round) + ` triggered "Got_The_Bomb"` + "\n")
+			b.WriteString(stamp() + `Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")` + "\n")
+		}
+		b.WriteString(stamp() + "Game Over: competitive de_dust2 score 13:11 after 40 min\n")
+	}
+	return b.String()
+}
+
+// BenchmarkParseReader measures parsing of a large server log end to end
+func BenchmarkParseReader(b *testing.B) {
+	log := benchmarkLog(20)
+	p := New()
+	b.SetBytes(int64(len(log)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.ParseReader(strings.NewReader(log), "2025_09_05_100000.log"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
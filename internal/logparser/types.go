@@ -1,8 +1,10 @@
 package logparser
 
 import (
+	"math"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // Player представляет игрока с ключом группировки и отображаемым именем
@@ -11,46 +13,315 @@ type Player struct {
 	Title string // подпись (обычно ник)
 }
 
+// Position — координаты в мире игры (юниты Source, 1 юнит ≈ 2.54 см)
+type Position struct {
+	X, Y, Z float64
+}
+
+// Distance возвращает расстояние между двумя точками в юнитах
+func (p Position) Distance(other Position) float64 {
+	return math.Sqrt((p.X-other.X)*(p.X-other.X) + (p.Y-other.Y)*(p.Y-other.Y) + (p.Z-other.Z)*(p.Z-other.Z))
+}
+
 // KillEvent представляет событие убийства
 type KillEvent struct {
-	KillerName string
-	KillerSID  string
-	VictimName string
-	VictimSID  string
-	Weapon     string
-	Date       string // Дата в формате YYYY-MM-DD
+	KillerName    string
+	KillerSID     string
+	KillerTeam    string `json:"-"` // Команда убийцы на момент убийства: CT, TERRORIST
+	VictimName    string
+	VictimSID     string
+	VictimTeam    string `json:"-"` // Команда жертвы
+	Weapon        string
+	Date          string    // Дата в формате YYYY-MM-DD
+	Time          time.Time `json:"-"` // Время строки лога (UTC); в HTML хватает Date
+	MatchID       string    // ID матча (Match.ID)
+	Round         int       // Номер раунда из JSON блока, который закрывает этот раунд (0 — неизвестен)
+	Map           string    // Карта матча
+	KillerPos     Position  `json:"-"` // Позиция убийцы (в HTML не нужна)
+	VictimPos     Position  `json:"-"` // Позиция жертвы (в HTML не нужна)
+	Distance      float64   // Дистанция убийства в юнитах (0, если позиции неизвестны)
+	Headshot      bool      `json:",omitempty"` // (headshot)
+	Penetrated    bool      `json:",omitempty"` // (penetrated) — прострел через стену
+	ThroughSmoke  bool      `json:",omitempty"` // (throughsmoke)
+	NoScope       bool      `json:",omitempty"` // (noscope)
+	AttackerBlind bool      `json:",omitempty"` // (attackerblind) — убийца был ослеплён
+}
+
+// DamageEvent представляет попадание ("attacked") с уроном
+type DamageEvent struct {
+	AttackerName string
+	AttackerSID  string
+	AttackerTeam string `json:"-"` // Команда атакующего: CT, TERRORIST
+	VictimName   string
+	VictimSID    string
+	VictimTeam   string `json:"-"` // Команда жертвы
+	Weapon       string
+	Damage       int       // Урон по здоровью из лога (может превышать оставшееся здоровье)
+	HealthDamage int       // Фактически снятое здоровье: Damage, ограниченный здоровьем жертвы до попадания
+	DamageArmor  int       `json:"-"` // Урон по броне
+	Health       int       // Здоровье жертвы после попадания (0 — попадание смертельное)
+	Armor        int       `json:"-"` // Броня жертвы после попадания
+	Hitgroup     string    // Часть тела: head, chest, stomach, left arm, ...
+	Round        int       // Номер раунда из JSON блока, который закрывает этот раунд (0 — неизвестен)
+	Date         string    // Дата в формате YYYY-MM-DD
+	Time         time.Time `json:"-"` // Время строки лога (UTC)
+	MatchID      string    `json:"-"` // ID матча (Match.ID)
+	Map          string    `json:"-"` // Карта матча
+	AttackerPos  Position  `json:"-"`
+	VictimPos    Position  `json:"-"`
 }
 
 // FlashEvent представляет событие ослепления
 type FlashEvent struct {
 	FlasherName string
 	FlasherSID  string
+	FlasherTeam string `json:"-"` // Команда флешера: CT, TERRORIST
 	VictimName  string
 	VictimSID   string
+	VictimTeam  string `json:"-"` // Команда ослеплённого
 	Duration    float64
-	Date        string // Дата в формате YYYY-MM-DD
+	TeamFlash   bool      `json:",omitempty"` // Флешер и жертва из одной команды (включая самоослепление)
+	Date        string    // Дата в формате YYYY-MM-DD
+	Time        time.Time `json:"-"` // Время строки лога (UTC)
+	MatchID     string    // ID матча (Match.ID)
+	Round       int       // Номер раунда из JSON блока, который закрывает этот раунд (0 — неизвестен)
+}
+
+// GrenadeEvent представляет бросок гранаты
+type GrenadeEvent struct {
+	ThrowerName string
+	ThrowerSID  string
+	ThrowerTeam string    `json:"-"` // Команда бросившего: CT, TERRORIST
+	Grenade     string    // hegrenade, flashbang, smokegrenade, molotov, incgrenade, decoy
+	Round       int       // Номер раунда из JSON блока, который закрывает этот раунд (0 — неизвестен)
+	Date        string    // Дата в формате YYYY-MM-DD
+	Time        time.Time `json:"-"` // Время строки лога (UTC)
+	MatchID     string    `json:"-"` // ID матча (Match.ID)
+	Pos         Position  `json:"-"` // Точка броска
 }
 
 // DefuseEvent представляет событие дефьюза бомбы
 type DefuseEvent struct {
 	PlayerName string
 	PlayerSID  string
-	WithKit    bool   // true если с дефьюз-китом, false если без кита
-	EventType  string // "begin", "success", "abandoned", "failed"
-	Date       string // Дата в формате YYYY-MM-DD
+	PlayerTeam string    `json:"-"` // Команда игрока (пусто для success и failed)
+	WithKit    bool      // true если с дефьюз-китом, false если без кита
+	EventType  string    // "begin", "success", "abandoned", "failed"
+	Date       string    // Дата в формате YYYY-MM-DD
+	Time       time.Time `json:"-"` // Время строки лога (UTC)
+	MatchID    string    // ID матча (Match.ID)
+	Round      int       // Номер раунда; success и failed относятся к раунду, который они завершают
 }
 
+// BombEvent представляет событие с бомбой: закладку, выброс или подбор
+type BombEvent struct {
+	PlayerName string
+	PlayerSID  string
+	PlayerTeam string    `json:"-"` // Команда игрока: CT, TERRORIST
+	EventType  string    // "plant", "drop", "pickup"
+	Site       string    // Бомбплент для закладки: "A" или "B"
+	Round      int       // Номер раунда из JSON блока, который закрывает этот раунд (0 — неизвестен)
+	Date       string    // Дата в формате YYYY-MM-DD
+	Time       time.Time `json:"-"` // Время строки лога (UTC)
+	MatchID    string    // ID матча (Match.ID)
+}
+
+// PurchaseEvent представляет покупку предмета в магазине
+type PurchaseEvent struct {
+	PlayerName string
+	PlayerSID  string
+	PlayerTeam string    `json:"-"` // Команда покупателя: CT, TERRORIST
+	Item       string    // Название предмета из лога: ak47, item_assaultsuit, ...
+	Price      int       // Цена по таблице магазина (0 — неизвестный предмет)
+	Round      int       // Номер раунда из JSON блока, который закрывает этот раунд (0 — неизвестен)
+	Date       string    // Дата в формате YYYY-MM-DD
+	Time       time.Time `json:"-"` // Время строки лога (UTC)
+	MatchID    string    `json:"-"` // ID матча (Match.ID)
+}
+
+// HostageEvent представляет событие с заложником: подбор, спасение или убийство
+type HostageEvent struct {
+	PlayerName string
+	PlayerSID  string
+	PlayerTeam string    `json:"-"` // Команда игрока: CT, TERRORIST
+	EventType  string    // "pickup", "rescue", "kill"
+	Round      int       // Номер раунда из JSON блока, который закрывает этот раунд (0 — неизвестен)
+	Date       string    // Дата в формате YYYY-MM-DD
+	Time       time.Time `json:"-"` // Время строки лога (UTC)
+	MatchID    string    // ID матча (Match.ID)
+}
+
+// Виды позорных смертей (ShameEvent.Kind)
+const (
+	ShameTeamKill = "teamkill" // Убийство союзника
+	ShameSuicide  = "suicide"  // Самоубийство своей гранатой, молотовым или командой kill
+	ShameWorld    = "world"    // Падение с высоты или урон от карты: committed suicide with "world"
+	ShameBomb     = "bomb"     // Смерть от взрыва бомбы
+)
+
+// ShameEvent — смерть, которая не считается обычным убийством: тимкилл, самоубийство,
+// падение или взрыв бомбы. Хранится отдельно от KillEvents и не попадает в матрицу убийств.
+type ShameEvent struct {
+	Kind       string    // ShameTeamKill, ShameSuicide, ShameWorld, ShameBomb
+	PlayerName string    // Виновник: убийца союзника или сам погибший
+	PlayerSID  string    // SteamID виновника
+	PlayerTeam string    `json:"-"` // Команда виновника
+	VictimName string    // Погибший (для всех видов, кроме тимкилла, совпадает с виновником)
+	VictimSID  string    // SteamID погибшего
+	Weapon     string    // Оружие из лога: ak47, hegrenade, world, planted_c4 ("" — бомба без оружия)
+	Round      int       // Номер раунда из JSON блока, который закрывает этот раунд (0 — неизвестен)
+	Date       string    // Дата в формате YYYY-MM-DD
+	Time       time.Time `json:"-"` // Время строки лога (UTC)
+	MatchID    string    // ID матча (Match.ID)
+	Map        string    `json:"-"` // Карта матча
+}
+
+// CustomEvent — событие внешнего обработчика строк (сообщения плагинов, объявления сервера).
+// Парсер только проставляет ему раунд и матч; смысл полей задаёт обработчик.
+type CustomEvent struct {
+	Type       string            // Тип события, который задал обработчик
+	PlayerName string            // Игрок, если событие к нему относится
+	PlayerSID  string            // SteamID игрока
+	Text       string            // Текст сообщения
+	Fields     map[string]string `json:",omitempty"` // Дополнительные поля
+	Round      int               // Номер раунда из JSON блока, который закрывает этот раунд (0 — неизвестен)
+	Date       string            // Дата в формате YYYY-MM-DD
+	Time       time.Time         `json:"-"` // Время строки лога (UTC)
+	MatchID    string            // ID матча (Match.ID)
+}
+
+// Условия победы в раунде
+const (
+	WinElimination    = "elimination"     // Вся команда соперника убита
+	WinBombExploded   = "bomb_exploded"   // Бомба взорвалась
+	WinBombDefused    = "bomb_defused"    // Бомба обезврежена
+	WinTimeRanOut     = "time_ran_out"    // Время раунда вышло
+	WinHostageRescued = "hostage_rescued" // Заложники спасены
+)
+
+// roundOutcome — победитель и условие победы для уведомления о конце раунда
+type roundOutcome struct {
+	winner    int
+	condition string
+}
+
+// roundOutcomes сопоставляет уведомления SFUI_Notice_* с исходом раунда
+var roundOutcomes = map[string]roundOutcome{
+	"SFUI_Notice_CTs_Win":              {3, WinElimination},
+	"SFUI_Notice_Terrorists_Win":       {2, WinElimination},
+	"SFUI_Notice_Target_Bombed":        {2, WinBombExploded},
+	"SFUI_Notice_Bomb_Defused":         {3, WinBombDefused},
+	"SFUI_Notice_Target_Saved":         {3, WinTimeRanOut},
+	"SFUI_Notice_Hostages_NotRescued":  {2, WinTimeRanOut},
+	"SFUI_Notice_All_Hostages_Rescued": {3, WinHostageRescued},
+	"SFUI_Notice_Hostages_Rescued":     {3, WinHostageRescued},
+}
+
+// EventRange — полуоткрытый диапазон индексов [From, To) в срезе событий ParseResult
+type EventRange struct {
+	From int
+	To   int
+}
+
+// Len возвращает количество событий в диапазоне
+func (r EventRange) Len() int {
+	return r.To - r.From
+}
+
+// Match представляет один завершённый матч: от Match_Start до Game Over
+type Match struct {
+	ID      string    // Уникальный ID: имя источника + "#" + номер матча в источнике + "@" + хэш полного пути источника
+	Source  string    // Имя файла лога (или члена архива), из которого взят матч
+	Map     string    // Карта
+	Server  string    // Название сервера из JSON блоков раундов
+	Start   time.Time // Время строки Match_Start (UTC, часовой пояс сервера не известен)
+	End     time.Time // Время строки Game Over
+	ScoreCT int       // Итоговый счёт стороны CT из строки Game Over (или последнего раунда)
+	ScoreT  int       // Итоговый счёт стороны T
+	// Количество сыгранных овертаймов
+	Overtimes int
+	// Матч не дошёл до Game Over и сохранён только с SetKeepPartial(true)
+	Partial     bool
+	AbortReason string // Причина незавершённости: AbortRestart, AbortNewMatch, ... ("" для завершённых)
+	// Составы по сторонам первого раунда (SteamID вида [U:1:N])
+	CTRoster []string
+	TRoster  []string
+
+	// Диапазоны событий матча в срезах ParseResult
+	Rounds   EventRange
+	Kills    EventRange
+	Flashes  EventRange
+	Damage   EventRange
+	Grenades EventRange
+	Defuses  EventRange
+	Bomb     EventRange
+	Hostages EventRange
+	Buys     EventRange
+	Custom   EventRange
+	Shame    EventRange
+}
+
+// shift сдвигает все диапазоны матча на смещения срезов, к которым он дописывается
+func (m *Match) shift(base *ParseResult) {
+	shiftRange := func(r *EventRange, offset int) {
+		r.From += offset
+		r.To += offset
+	}
+	shiftRange(&m.Rounds, len(base.RoundStats))
+	shiftRange(&m.Kills, len(base.KillEvents))
+	shiftRange(&m.Flashes, len(base.FlashEvents))
+	shiftRange(&m.Damage, len(base.DamageEvents))
+	shiftRange(&m.Grenades, len(base.GrenadeEvents))
+	shiftRange(&m.Defuses, len(base.DefuseEvents))
+	shiftRange(&m.Bomb, len(base.BombEvents))
+	shiftRange(&m.Hostages, len(base.HostageEvents))
+	shiftRange(&m.Buys, len(base.PurchaseEvents))
+	shiftRange(&m.Custom, len(base.CustomEvents))
+	shiftRange(&m.Shame, len(base.ShameEvents))
+}
+
+// Причины, по которым матч не дошёл до Game Over
+const (
+	AbortRestart   = "restart"         // Админ перезапустил игру (mp_restartgame)
+	AbortNewMatch  = "new_match_start" // Новый Match_Start без Game Over
+	AbortMapChange = "map_change"      // Сервер сменил карту
+	AbortWarmup    = "warmup"          // Сервер ушёл в разминку
+	AbortLogEnded  = "log_ended"       // Лог закончился посреди матча
+)
+
+// DroppedMatch описывает отброшенный незавершённый матч
+type DroppedMatch struct {
+	Source string
+	Map    string
+	Start  time.Time
+	Rounds int    // Сыгранные раунды (JSON блоки)
+	Reason string // AbortRestart, AbortNewMatch, ...
+}
+
+// Значения по умолчанию для длины матча: MR12 и овертаймы MR3
+const (
+	defaultMaxRounds         = 24
+	defaultOvertimeMaxRounds = 6
+)
+
 // RoundStats представляет статистику раунда из JSON_BEGIN блока
 type RoundStats struct {
-	Date        string        // Дата в формате YYYY-MM-DD
-	Time        string        // Время в формате HH:MM:SS
-	RoundNumber int           // Номер раунда
-	ScoreT      int           // Счёт террористов
-	ScoreCT     int           // Счёт контр-террористов
-	Map         string        // Название карты
-	Server      string        // Название сервера
-	Players     []PlayerStats // Статистика игроков
-	Winner      int           // Победитель раунда: 2=T, 3=CT, 0=неизвестно/ничья
+	MatchID         string        // ID матча (Match.ID)
+	Date            string        // Дата в формате YYYY-MM-DD
+	Time            string        // Время в формате HH:MM:SS
+	RoundNumber     int           // Номер раунда
+	Overtime        int           // Номер овертайма (0 — основное время)
+	Half            int           // Половина основного времени или текущего овертайма: 1 или 2 (после смены сторон)
+	ScoreT          int           // Счёт террористов
+	ScoreCT         int           // Счёт контр-террористов
+	Map             string        // Название карты
+	Server          string        // Название сервера
+	Players         []PlayerStats // Статистика игроков
+	Winner          int           // Победитель раунда: 2=T, 3=CT, 0=неизвестно/ничья
+	WinCondition    string        // Условие победы: WinElimination, WinBombExploded, ... ("" — неизвестно)
+	BombSite        string        // Бомбплент, на котором заложена бомба ("" — закладки не было)
+	PlanterSID      string        // SteamID игрока, заложившего бомбу
+	HostagesRescued int           // Спасённые в раунде заложники
 }
 
 // PlayerStats представляет статистику одного игрока в раунде
@@ -82,56 +353,31 @@ type PlayerStats struct {
 	Dinks     int     // Headshot hits
 	ChickenK  int     // Chicken kills
 	Rating    float64 // EPI Rating (рассчитывается после парсинга)
+
+	// Экономика раунда: считается по строкам "purchased" и убийствам, а не из JSON блока
+	Spent        int  // Потрачено на покупки в раунде
+	CarriedValue int  // Стоимость основного оружия, сохранённого с прошлого раунда
+	Survived     bool // Игрок дожил до конца раунда
+	ClutchVs     int  // Остался последним живым в команде против стольких противников (0 — клатча не было), см. closeRoundClutches
 }
 
-// LogRegexps содержит регулярные выражения для парсинга логов
+// LogRegexps содержит регулярные выражения для строк, задающих границы матча.
+// События внутри матча разбирает токенизатор (tokenizer.go) и обработчики (handlers.go).
 type LogRegexps struct {
-	KillPattern            *regexp.Regexp
-	FlashPattern           *regexp.Regexp
-	DefuseBeginPattern     *regexp.Regexp
-	DefuseSuccessPattern   *regexp.Regexp
-	DefuseAbandonedPattern *regexp.Regexp
-	BombExplodedPattern    *regexp.Regexp
-	MatchStartPattern      *regexp.Regexp
-	MatchStatusPattern     *regexp.Regexp
-	GameOverPattern        *regexp.Regexp
-	CTWinPattern           *regexp.Regexp
-	TerroristWinPattern    *regexp.Regexp
+	MatchStartPattern  *regexp.Regexp
+	MatchStatusPattern *regexp.Regexp
+	GameOverPattern    *regexp.Regexp
+	RestartPattern     *regexp.Regexp
+	MapChangePattern   *regexp.Regexp
+	WarmupStartPattern *regexp.Regexp
+	ServerCvarPattern  *regexp.Regexp
 }
 
 // NewLogRegexps создает новые регулярные выражения для парсинга CS2 логов
 func NewLogRegexps() *LogRegexps {
-	killRe := regexp.MustCompile(
-		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s"` +
-			`([^"<]+)<\d+><([^>]+)><[^>]*>"\s+\[[^\]]+\]\s+killed\s+` + // killerName, killerSID
-			`"([^"<]+)<\d+><([^>]+)><[^>]*>"\s+\[[^\]]+\]\s+with\s+"([^"]+)"$`) // victimName, victimSID, weapon
-
-	flashRe := regexp.MustCompile(
-		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s"` +
-			`([^"<]+)<\d+><([^>]+)><[^>]*>"\s+blinded\s+for\s+([0-9.]+)\s+by\s+` + // victimName, victimSID, duration
-			`"([^"<]+)<\d+><([^>]+)><[^>]*>"\s+from\s+flashbang\s+entindex\s+\d+\s*$`) // flasherName, flasherSID
-
-	// Пример: "povidlo boy<4><[U:1:44922694]><CT>" triggered "Begin_Bomb_Defuse_With_Kit"
-	defuseBeginRe := regexp.MustCompile(
-		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s"` +
-			`([^"<]+)<\d+><([^>]+)><[^>]*>"\s+triggered\s+"Begin_Bomb_Defuse_(With|Without)_Kit"$`) // playerName, playerSID, withKit
-
-	// Пример: Team "CT" triggered "SFUI_Notice_Bomb_Defused" (CT "8") (T "5")
-	defuseSuccessRe := regexp.MustCompile(
-		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s+Team\s+"CT"\s+triggered\s+"SFUI_Notice_Bomb_Defused"`)
-
-	// Пример: "player<id><steamid><CT>" stopped defusing the bomb
-	defuseAbandonedRe := regexp.MustCompile(
-		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s"` +
-			`([^"<]+)<\d+><([^>]+)><[^>]*>"\s+stopped\s+defusing\s+the\s+bomb`)
-
-	// Пример: Team "TERRORIST" triggered "SFUI_Notice_Target_Bombed" (CT "8") (T "5")
-	bombExplodedRe := regexp.MustCompile(
-		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s+Team\s+"TERRORIST"\s+triggered\s+"SFUI_Notice_Target_Bombed"`)
-
 	// Пример: World triggered "Match_Start" on "cs_office"
 	matchStartRe := regexp.MustCompile(
-		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s+World\s+triggered\s+"Match_Start"`)
+		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s+World\s+triggered\s+"Match_Start"(?:\s+on\s+"([^"]+)")?`) // map
 
 	// Пример: MatchStatus: Score: 13:6 on map "cs_office" RoundsPlayed: 19
 	matchStatusRe := regexp.MustCompile(
@@ -139,28 +385,33 @@ func NewLogRegexps() *LogRegexps {
 
 	// Пример: Game Over: competitive cs_office score 13:6 after 28 min
 	gameOverRe := regexp.MustCompile(
-		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s+Game Over:`)
+		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s+Game Over:(?:.*\sscore\s+(\d+):(\d+))?`) // scoreCT, scoreT
 
-	// Пример: Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")
-	ctWinRe := regexp.MustCompile(
-		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s+Team\s+"CT"\s+triggered\s+"SFUI_Notice_CTs_Win"`)
+	// Пример: World triggered "Restart_Round_(1_second)" — mp_restartgame
+	restartRe := regexp.MustCompile(
+		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s+World\s+triggered\s+"Restart_Round_\(`)
 
-	// Пример: Team "TERRORIST" triggered "SFUI_Notice_Terrorists_Win" (CT "0") (T "1")
-	terroristWinRe := regexp.MustCompile(
-		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s+Team\s+"TERRORIST"\s+triggered\s+"SFUI_Notice_Terrorists_Win"`)
+	// Пример: Loading map "de_nuke"
+	// Пример: Started map "de_nuke" (CRC "-1234")
+	mapChangeRe := regexp.MustCompile(
+		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s+(?:Loading|Started)\s+map\s+"([^"]+)"`) // map
+
+	// Пример: World triggered "Warmup_Start"
+	warmupStartRe := regexp.MustCompile(
+		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s+World\s+triggered\s+"Warmup_Start"`)
+
+	// Пример: server_cvar: "mp_maxrounds" "24"
+	serverCvarRe := regexp.MustCompile(
+		`^L\s+\d{2}/\d{2}/\d{4}\s+-\s+\d{2}:\d{2}:\d{2}:\s+server_cvar:\s+"(\w+)"\s+"([^"]*)"`) // name, value
 
 	return &LogRegexps{
-		KillPattern:            killRe,
-		FlashPattern:           flashRe,
-		DefuseBeginPattern:     defuseBeginRe,
-		DefuseSuccessPattern:   defuseSuccessRe,
-		DefuseAbandonedPattern: defuseAbandonedRe,
-		BombExplodedPattern:    bombExplodedRe,
-		MatchStartPattern:      matchStartRe,
-		MatchStatusPattern:     matchStatusRe,
-		GameOverPattern:        gameOverRe,
-		CTWinPattern:           ctWinRe,
-		TerroristWinPattern:    terroristWinRe,
+		MatchStartPattern:  matchStartRe,
+		MatchStatusPattern: matchStatusRe,
+		GameOverPattern:    gameOverRe,
+		RestartPattern:     restartRe,
+		MapChangePattern:   mapChangeRe,
+		WarmupStartPattern: warmupStartRe,
+		ServerCvarPattern:  serverCvarRe,
 	}
 }
 
@@ -199,3 +450,19 @@ func ExtractDateFromLogLine(line string) string {
 
 	return year + "-" + month + "-" + day
 }
+
+// logTimeLayout — формат префикса строки лога после "L "
+const logTimeLayout = "01/02/2006 - 15:04:05"
+
+// ParseLogTime извлекает время из префикса строки лога "L MM/DD/YYYY - HH:MM:SS:".
+// Часовой пояс сервера в логе не указан, поэтому время возвращается в UTC.
+func ParseLogTime(line string) (time.Time, bool) {
+	if len(line) < len("L ")+len(logTimeLayout) || !strings.HasPrefix(line, "L ") {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(logTimeLayout, line[len("L "):len("L ")+len(logTimeLayout)])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
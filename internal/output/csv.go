@@ -3,8 +3,11 @@ package output
 import (
 	"encoding/csv"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 
+	"oldfartscounter/internal/logparser"
 	"oldfartscounter/internal/stats"
 )
 
@@ -49,3 +52,111 @@ func (c *CSVExporter) WriteKillMatrix(path string, data *stats.StatsData) error
 
 	return w.Error()
 }
+
+// WriteRatings записывает рейтинги игроков с KAST, выживаемостью и разменами в CSV файл
+func (c *CSVExporter) WriteRatings(path string, data *stats.StatsData) error {
+	f, err := os.Create(path) // #nosec G304 - path is controlled by user input for CSV export
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	formatFloat := func(v float64, prec int) string {
+		return strconv.FormatFloat(v, 'f', prec, 64)
+	}
+
+	_ = w.Write([]string{
+		"player", "account_id", "rounds", "bayesian_epi", "average_epi", "kills", "deaths", "assists", "damage", "wins",
+		"kast", "kast_rounds", "survival_rate", "survived_rounds", "deaths_per_round",
+		"trades_made", "traded_deaths", "untraded_deaths", "avg_trade_time", "last_played",
+	})
+	for _, r := range data.PlayerRatings {
+		_ = w.Write([]string{
+			r.Name, strconv.FormatInt(r.AccountID, 10), strconv.Itoa(r.RoundsPlayed),
+			formatFloat(r.BayesianEPI, 3), formatFloat(r.AverageEPI, 3),
+			strconv.Itoa(r.TotalKills), strconv.Itoa(r.TotalDeaths), strconv.Itoa(r.TotalAssists),
+			strconv.Itoa(r.TotalDamage), strconv.Itoa(r.WinRounds),
+			formatFloat(r.KAST, 1), strconv.Itoa(r.KASTRounds),
+			formatFloat(r.SurvivalRate, 1), strconv.Itoa(r.SurvivedRounds), formatFloat(r.DeathsPerRound, 2),
+			strconv.Itoa(r.TradesMade), strconv.Itoa(r.TradedDeaths), strconv.Itoa(r.UntradedDeaths),
+			formatFloat(r.AvgTradeTime, 2), r.LastPlayed,
+		})
+	}
+
+	return w.Error()
+}
+
+// WriteKillPositions записывает позиции убийств в отдельный CSV на каждую карту (<dir>/<map>.csv).
+// Убийства без координат пропускаются. Возвращает список записанных файлов.
+func (c *CSVExporter) WriteKillPositions(dir string, data *stats.StatsData) ([]string, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+
+	byMap := make(map[string][]logparser.KillEvent)
+	for _, event := range data.KillEvents {
+		if event.Distance == 0 {
+			continue
+		}
+		mapName := event.Map
+		if mapName == "" {
+			mapName = "unknown"
+		}
+		byMap[mapName] = append(byMap[mapName], event)
+	}
+
+	maps := make([]string, 0, len(byMap))
+	for mapName := range byMap {
+		maps = append(maps, mapName)
+	}
+	sort.Strings(maps)
+
+	files := make([]string, 0, len(maps))
+	for _, mapName := range maps {
+		path := filepath.Join(dir, filepath.Base(mapName)+".csv")
+		if err := writeKillPositionsFile(path, byMap[mapName]); err != nil {
+			return files, err
+		}
+		files = append(files, path)
+	}
+
+	return files, nil
+}
+
+// writeKillPositionsFile записывает позиции убийств одной карты
+func writeKillPositionsFile(path string, events []logparser.KillEvent) error {
+	f, err := os.Create(path) // #nosec G304 - path is controlled by user input for CSV export
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	formatFloat := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	_ = w.Write([]string{
+		"date", "killer", "killer_sid", "victim", "victim_sid", "weapon", "headshot",
+		"killer_x", "killer_y", "killer_z", "victim_x", "victim_y", "victim_z", "distance",
+	})
+	for _, e := range events {
+		_ = w.Write([]string{
+			e.Date, e.KillerName, e.KillerSID, e.VictimName, e.VictimSID, e.Weapon, strconv.FormatBool(e.Headshot),
+			formatFloat(e.KillerPos.X), formatFloat(e.KillerPos.Y), formatFloat(e.KillerPos.Z),
+			formatFloat(e.VictimPos.X), formatFloat(e.VictimPos.Y), formatFloat(e.VictimPos.Z),
+			formatFloat(e.Distance),
+		})
+	}
+
+	return w.Error()
+}
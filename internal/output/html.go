@@ -20,7 +20,13 @@ type HTMLGenerator struct {
 	killsTab         *components.KillsTabComponent
 	weaponsTab       *components.WeaponsTabComponent
 	flashTab         *components.FlashTabComponent
+	damageTab        *components.DamageTabComponent
 	defuseTab        *components.DefuseTabComponent
+	bombTab          *components.BombTabComponent
+	hostagesTab      *components.HostagesTabComponent
+	shameTab         *components.ShameTabComponent
+	clutchesTab      *components.ClutchesTabComponent
+	economyTab       *components.EconomyTabComponent
 	roundsTab        *components.RoundsTabComponent
 	playerRatingsTab *components.PlayerRatingsTabComponent
 	treeTab          *components.TreeTabComponent
@@ -50,7 +56,13 @@ func NewHTMLGenerator() *HTMLGenerator {
 		killsTab:         components.NewKillsTab(),
 		weaponsTab:       components.NewWeaponsTab(),
 		flashTab:         components.NewFlashTab(),
+		damageTab:        components.NewDamageTab(),
 		defuseTab:        components.NewDefuseTab(),
+		bombTab:          components.NewBombTab(),
+		hostagesTab:      components.NewHostagesTab(),
+		shameTab:         components.NewShameTab(),
+		clutchesTab:      components.NewClutchesTab(),
+		economyTab:       components.NewEconomyTab(),
 		roundsTab:        components.NewRoundsTab(),
 		playerRatingsTab: components.NewPlayerRatingsTab(),
 		treeTab:          components.NewTreeTab(),
@@ -90,7 +102,13 @@ func (h *HTMLGenerator) Generate(path string, data *stats.StatsData) error {
 	// Передаём агрегированные данные по дням
 	jDailyKills, _ := json.Marshal(data.DailyKills)
 	jDailyFlash, _ := json.Marshal(data.DailyFlash)
+	jDailyDamage, _ := json.Marshal(data.DailyDamage)
+	jDailyThrows, _ := json.Marshal(data.DailyThrows)
+	jDailyBomb, _ := json.Marshal(data.DailyBomb)
+	jDailyHostage, _ := json.Marshal(data.DailyHostage)
 	jDailyDefuse, _ := json.Marshal(data.DailyDefuse)
+	jDailyShame, _ := json.Marshal(data.DailyShame)
+	jDailyTrades, _ := json.Marshal(data.DailyTrades)
 	jDailyRounds, _ := json.Marshal(data.DailyRounds)
 
 	// Извлекаем диапазон дат для плейсхолдеров
@@ -152,7 +170,13 @@ document.getElementById('load-step-1').style.color = '#22c55e';
   <button class="tab-btn" data-tab="kw">Кто с чего убивает</button>
   <button class="tab-btn" data-tab="vw">Кого чем убивают</button>
   <button class="tab-btn" data-tab="flash">Индекс Пирога</button>
+  <button class="tab-btn" data-tab="damage">Урон</button>
   <button class="tab-btn" data-tab="rounds">Игры</button>
+  <button class="tab-btn" data-tab="bomb">Бомба</button>
+  <button class="tab-btn" data-tab="hostages">Заложники</button>
+  <button class="tab-btn" data-tab="economy">Экономика</button>
+  <button class="tab-btn" data-tab="clutches">Клатчи</button>
+  <button class="tab-btn" data-tab="shame">Позор</button>
   <button class="tab-btn" data-tab="defuse" style="display:none">Герои Дефьюза</button>
 </div>
 
@@ -170,10 +194,16 @@ document.getElementById('load-step-1').style.color = '#22c55e';
 ` + h.weaponsTab.GenerateKillerWeaponHTML(data) + `
 ` + h.weaponsTab.GenerateVictimWeaponHTML(data) + `
 ` + h.flashTab.GenerateHTML(data) + `
+` + h.damageTab.GenerateHTML(data) + `
 ` + h.playerRatingsTab.GenerateHTML() + `
 ` + h.progressTab.GenerateHTML() + `
 ` + h.roundsTab.GenerateHTML() + `
 ` + h.defuseTab.GenerateHTML(data) + `
+` + h.bombTab.GenerateHTML(data) + `
+` + h.hostagesTab.GenerateHTML(data) + `
+` + h.economyTab.GenerateHTML(data) + `
+` + h.clutchesTab.GenerateHTML(data) + `
+` + h.shameTab.GenerateHTML(data) + `
 ` + h.treeTab.GenerateHTML() + `
 
 <div class="footer">Сборка: ` + html.EscapeString(buildVersion) + `</div>
@@ -192,7 +222,7 @@ window.onerror = function(msg, url, line, col, error) {
   return false;
 };
 
-var PLAYERS, WEAPONS, DAILY_KILLS, DAILY_FLASH, DAILY_DEFUSE, DAILY_ROUNDS;
+var PLAYERS, WEAPONS, DAILY_KILLS, DAILY_FLASH, DAILY_DAMAGE, DAILY_THROWS, DAILY_DEFUSE, DAILY_BOMB, DAILY_HOSTAGE, DAILY_SHAME, DAILY_TRADES, DAILY_ROUNDS;
 try {
   // Шаг 3: Парсим данные
   document.getElementById('load-step-3').style.color = '#fde047';
@@ -201,7 +231,13 @@ try {
   WEAPONS = ` + string(jWeapons) + `;
   DAILY_KILLS = ` + string(jDailyKills) + `;
   DAILY_FLASH = ` + string(jDailyFlash) + `;
+  DAILY_DAMAGE = ` + string(jDailyDamage) + `;
+  DAILY_THROWS = ` + string(jDailyThrows) + `;
   DAILY_DEFUSE = ` + string(jDailyDefuse) + `;
+  DAILY_BOMB = ` + string(jDailyBomb) + `;
+  DAILY_HOSTAGE = ` + string(jDailyHostage) + `;
+  DAILY_SHAME = ` + string(jDailyShame) + `;
+  DAILY_TRADES = ` + string(jDailyTrades) + `;
   DAILY_ROUNDS = ` + string(jDailyRounds) + `;
 
   document.getElementById('load-step-3').style.color = '#22c55e';
@@ -223,10 +259,16 @@ try {
 ` + h.weaponsTab.GenerateKillerWeaponJS(data) + `
 ` + h.weaponsTab.GenerateVictimWeaponJS(data) + `
 ` + h.flashTab.GenerateJS(data) + `
+` + h.damageTab.GenerateJS(data) + `
 ` + h.playerRatingsTab.GenerateJS(data) + `
 ` + h.progressTab.GenerateJS(data) + `
 ` + h.roundsTab.GenerateJS(data) + `
 ` + h.defuseTab.GenerateJS(data) + `
+` + h.bombTab.GenerateJS(data) + `
+` + h.hostagesTab.GenerateJS(data) + `
+` + h.economyTab.GenerateJS(data) + `
+` + h.clutchesTab.GenerateJS(data) + `
+` + h.shameTab.GenerateJS(data) + `
 ` + h.treeTab.GenerateJS() + `
 
 // Шаг 4 завершен
@@ -579,6 +621,138 @@ function hslToHex(h,s,l){
 function escCSV(s){ s = String(s); if(/[",\n]/.test(s)){ return '"' + s.replace(/"/g,'""') + '"'; } return s; }
 function trimLabel(s, n=24){ const arr=[...s]; return arr.length<=n ? s : arr.slice(0,n-1).join("")+"…"; }
 
+// renderColumnTable рисует таблицу "строки × показатели" с сортировкой и heatmap по каждому столбцу.
+// columns: [{title, data}], где data[i] — значение для строки players[i].
+function renderColumnTable(opts){
+  const {rootId, players, columns, qInputId, csvBtnId, csvName, heatToggleId} = opts;
+  const rowTitle = opts.rowTitle || "Игрок";
+  const wrap = document.querySelector(rootId);
+  const thead = wrap.querySelector("thead");
+  const tbody = wrap.querySelector("tbody");
+  const q = document.getElementById(qInputId);
+  const csvBtn = document.getElementById(csvBtnId);
+  const heatToggle = document.getElementById(heatToggleId);
+
+  let filter = "";
+  let heatOn = true;
+
+  // Вычисляем максимальное значение для каждого столбца
+  const maxValues = columns.map(col => Math.max(...col.data));
+
+  q?.addEventListener("input", ()=>{ filter = q.value.trim().toLowerCase(); draw(); });
+  heatToggle?.addEventListener("change", ()=>{ heatOn = heatToggle.checked; draw(); });
+  csvBtn?.addEventListener("click", ()=>{
+    const rows = [];
+    const keepIdx = players.map((_,i)=>i).filter(i=>players[i].toLowerCase().includes(filter));
+    rows.push([rowTitle, ...columns.map(col => col.title)]);
+    keepIdx.forEach(i=>{
+      rows.push([players[i], ...columns.map(col => String(col.data[i] || 0))]);
+    });
+    const csv = rows.map(r=>r.map(escCSV).join(",")).join("\\n");
+    const blob = new Blob([csv], {type:"text/csv;charset=utf-8"});
+    const a=document.createElement("a"); a.href=URL.createObjectURL(blob); a.download=csvName || "table.csv"; a.click();
+  });
+
+  function draw(){
+    const keepIdx = players.map((_,i)=>i).filter(i=>players[i].toLowerCase().includes(filter));
+
+    // Сортируем по общему количеству попыток (первый столбец)
+    keepIdx.sort((a,b)=> (columns[0].data[b] || 0) - (columns[0].data[a] || 0));
+
+    // Создаем заголовок
+    thead.innerHTML = "";
+    const tr = document.createElement("tr");
+
+    const th1 = document.createElement("th");
+    th1.textContent = rowTitle;
+    th1.className = "sticky-left sortable";
+    th1.onclick = ()=> {
+      const sorted = keepIdx.slice().sort((a,b)=> players[a].localeCompare(players[b]));
+      renderBody(sorted);
+    };
+    tr.appendChild(th1);
+
+    columns.forEach((col, colIdx) => {
+      const th = document.createElement("th");
+      th.textContent = col.title;
+      th.className = "sortable";
+      th.onclick = ()=> {
+        const sorted = keepIdx.slice().sort((a,b)=> (col.data[b] || 0) - (col.data[a] || 0));
+        renderBody(sorted);
+      };
+      tr.appendChild(th);
+    });
+
+    thead.appendChild(tr);
+    renderBody(keepIdx);
+  }
+
+  function renderBody(indices){
+    tbody.innerHTML = "";
+    indices.forEach(i=>{
+      const tr = document.createElement("tr");
+
+      // Колонка с именем игрока
+      const td1 = document.createElement("td");
+      td1.textContent = trimLabel(players[i]);
+      td1.title = players[i];
+      td1.className = "sticky-left";
+      tr.appendChild(td1);
+
+      // Колонки с данными
+      columns.forEach((col, colIdx) => {
+        const td = document.createElement("td");
+        td.className = "cell";
+        const v = col.data[i] || 0;
+        td.textContent = String(v);
+        td.title = players[i] + " - " + col.title + ": " + v;
+
+        if(heatOn && maxValues[colIdx] > 0){
+          td.style.background = heatColor(v, maxValues[colIdx]);
+          td.style.color = textColor(v, maxValues[colIdx]);
+        } else {
+          td.style.background = "";
+          td.style.color = "";
+        }
+
+        tr.appendChild(td);
+      });
+
+      tbody.appendChild(tr);
+    });
+  }
+
+  draw();
+}
+
+// utilityGrenade приводит название гранаты или оружия урона к виду гранаты:
+// зажигательная граната считается молотовым, урон от огня приходит как "inferno"
+function utilityGrenade(name){
+  return (name === "incgrenade" || name === "inferno") ? "molotov" : name;
+}
+
+// Модификаторы убийств: (headshot), (penetrated), (noscope), (throughsmoke), (attackerblind)
+const KILL_MODIFIER_COLUMNS = ["Убийства", "Хедшоты", "HS %", "Прострелы", "Ноускоупы", "Сквозь дым", "Вслепую"];
+
+// countKillModifiers считает модификаторы по строкам; rowOf(e) возвращает индекс строки или undefined.
+// Возвращает столбцы в формате renderColumnTable.
+function countKillModifiers(events, rowsCount, rowOf){
+  const rows = Array(rowsCount).fill(0).map(() => Array(KILL_MODIFIER_COLUMNS.length).fill(0));
+  events.forEach(e => {
+    const idx = rowOf(e);
+    if (idx === undefined) return;
+    const row = rows[idx];
+    row[0]++;
+    if (e.Headshot) row[1]++;
+    if (e.Penetrated) row[3]++;
+    if (e.NoScope) row[4]++;
+    if (e.ThroughSmoke) row[5]++;
+    if (e.AttackerBlind) row[6]++;
+  });
+  rows.forEach(row => { row[2] = row[0] ? Math.round(row[1] * 100 / row[0]) : 0; });
+  return KILL_MODIFIER_COLUMNS.map((title, c) => ({title: title, data: rows.map(row => row[c])}));
+}
+
 function renderMatrix(opts){
   const {rootId, rowLabels, colLabels, data, maxVal, qInputId, csvBtnId, heatToggleId, cornerTitle, numFmt, highlightedPlayer, secondaryTarget} = opts;
   const wrap = document.querySelector(rootId);
@@ -973,11 +1147,26 @@ function getFilteredEvents(dailyData) {
   return result;
 }
 
+// Функция для получения всех событий за весь период, без фильтра по датам
+function getAllEvents(dailyData) {
+  var result = [];
+  for (var date in dailyData) {
+    result = result.concat(dailyData[date]);
+  }
+  return result;
+}
+
 // Функция для пересчета статистики с учетом фильтра по датам
 function recalculateStats() {
   window.filteredKillEvents = getFilteredEvents(DAILY_KILLS);
   window.filteredFlashEvents = getFilteredEvents(DAILY_FLASH);
+  window.filteredDamageEvents = getFilteredEvents(DAILY_DAMAGE);
+  window.filteredThrowEvents = getFilteredEvents(DAILY_THROWS);
   window.filteredDefuseEvents = getFilteredEvents(DAILY_DEFUSE);
+  window.filteredBombEvents = getFilteredEvents(DAILY_BOMB);
+  window.filteredHostageEvents = getFilteredEvents(DAILY_HOSTAGE);
+  window.filteredShameEvents = getFilteredEvents(DAILY_SHAME);
+  window.filteredTradeEvents = getFilteredEvents(DAILY_TRADES);
   window.filteredRoundStats = getFilteredEvents(DAILY_ROUNDS);
 
   // Триггерим событие для обновления всех таблиц (совместимо со старыми браузерами)
@@ -1023,7 +1212,13 @@ if (resetDatesEl) {
 // Инициализация фильтрованных событий (без фильтра по умолчанию)
 window.filteredKillEvents = getFilteredEvents(DAILY_KILLS);
 window.filteredFlashEvents = getFilteredEvents(DAILY_FLASH);
+window.filteredDamageEvents = getFilteredEvents(DAILY_DAMAGE);
+window.filteredThrowEvents = getFilteredEvents(DAILY_THROWS);
 window.filteredDefuseEvents = getFilteredEvents(DAILY_DEFUSE);
+window.filteredBombEvents = getFilteredEvents(DAILY_BOMB);
+window.filteredHostageEvents = getFilteredEvents(DAILY_HOSTAGE);
+window.filteredShameEvents = getFilteredEvents(DAILY_SHAME);
+window.filteredTradeEvents = getFilteredEvents(DAILY_TRADES);
 window.filteredRoundStats = getFilteredEvents(DAILY_ROUNDS);
 `
 }
@@ -1085,10 +1280,16 @@ const tabHashMap = {
   'kw': 'killer-weapon',
   'vw': 'victim-weapon',
   'flash': 'whereispie',
+  'damage': 'damage',
   'player-ratings': 'ratings',
   'progress': 'progress',
   'rounds': 'games',
   'defuse': 'defuse',
+  'bomb': 'bomb',
+  'hostages': 'hostages',
+  'economy': 'economy',
+  'clutches': 'clutches',
+  'shame': 'shame',
   'tree': 'tree'
 };
 
@@ -1101,6 +1302,17 @@ var savedDateFrom = null;
 var savedDateTo = null;
 var currentTab = null; // Изначально null, чтобы первая загрузка сработала корректно
 
+// Табы, на которых фильтр по датам скрыт. Рейтинг попадает сюда, пока выбрана модель, посчитанная за весь период
+var dateFilterHiddenTabs = {'tournament': true, 'tree': true};
+
+// Скрываем/показываем фильтр по датам в зависимости от текущего таба
+function updateDateFilterVisibility() {
+  var dateFilterEl = document.querySelector('.date-filter');
+  if (dateFilterEl) {
+    dateFilterEl.style.display = dateFilterHiddenTabs[currentTab] ? 'none' : 'flex';
+  }
+}
+
 // Функция для получения дат текущего месяца
 function getCurrentMonthDates() {
   var now = new Date();
@@ -1150,16 +1362,7 @@ function switchTab(tabId, updateHash) {
   }
 
   currentTab = tabId;
-
-  // Скрываем/показываем фильтр по датам в зависимости от таба
-  var dateFilterEl = document.querySelector('.date-filter');
-  if (dateFilterEl) {
-    if (tabId === 'tournament' || tabId === 'tree') {
-      dateFilterEl.style.display = 'none';
-    } else {
-      dateFilterEl.style.display = 'flex';
-    }
-  }
+  updateDateFilterVisibility();
 
   btns.forEach(x=>x.classList.remove("active"));
   document.querySelectorAll(".view").forEach(v=>v.classList.remove("active"));
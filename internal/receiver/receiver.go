@@ -0,0 +1,413 @@
+package receiver
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"oldfartscounter/internal/logparser"
+)
+
+// maxBatchBytes — предельный размер одной пачки строк от сервера
+const maxBatchBytes = 8 << 20
+
+// Ограничения на серверы по умолчанию (см. SetSourceLimits)
+const (
+	defaultIdleTimeout = 10 * time.Minute
+	defaultMaxSources  = 64
+)
+
+// ErrTooManySources — пачка от нового сервера, когда приём уже ведёт предельное число серверов
+var ErrTooManySources = errors.New("слишком много серверов")
+
+// httpLinePattern — префикс строки из logaddress_add_http: "MM/DD/YYYY - HH:MM:SS.mmm - "
+var httpLinePattern = regexp.MustCompile(`^(\d{2}/\d{2}/\d{4} - \d{2}:\d{2}:\d{2})\.\d+ - `)
+
+// Receiver принимает логи CS2 по HTTP (logaddress_add_http), разбирает их потоково
+// по серверам и ведёт живое табло каждого сервера.
+//
+// Маршруты:
+//   - POST /logs/{server} — пачка строк лога сервера server (тело — строки через \n)
+//   - POST /logs — то же, сервер определяется по хэшу X-Server-Instance-Token или адресу отправителя
+//   - GET /scoreboard — табло всех серверов (JSON)
+//   - GET /scoreboard/{server} — табло одного сервера (JSON)
+//   - GET /events — server-sent events: событие scoreboard при каждом изменении табло
+type Receiver struct {
+	parser      *logparser.Parser
+	onMatch     func(match, total *logparser.ParseResult)
+	now         func() time.Time
+	idleTimeout time.Duration // сервер без строк дольше этого забывается вместе с незавершённым матчем
+	maxSources  int           // предельное число серверов одновременно
+
+	mu          sync.Mutex
+	result      *logparser.ParseResult // завершённые матчи всех серверов
+	sources     map[string]*source
+	subscribers map[chan []byte]struct{}
+	pending     []matchUpdate // завершённые матчи, ещё не переданные onMatch
+	wake        *sync.Cond    // будит deliverMatches при новом матче и при Close
+	closed      bool
+	done        chan struct{} // закрывается, когда deliverMatches передал все матчи и вышел
+}
+
+// matchUpdate — завершённый матч и снимок всех принятых матчей на момент его завершения
+type matchUpdate struct {
+	match *logparser.ParseResult
+	total *logparser.ParseResult
+}
+
+// source — разбор и табло одного сервера
+type source struct {
+	stream   *logparser.Stream
+	board    *board
+	lastSeen time.Time // время последней пачки строк
+}
+
+// New создает приёмник, разбирающий строки парсером parser. Close останавливает его.
+func New(parser *logparser.Parser) *Receiver {
+	r := &Receiver{
+		parser:      parser,
+		now:         time.Now,
+		idleTimeout: defaultIdleTimeout,
+		maxSources:  defaultMaxSources,
+		result:      logparser.NewParseResult(),
+		sources:     make(map[string]*source),
+		subscribers: make(map[chan []byte]struct{}),
+		done:        make(chan struct{}),
+	}
+	r.wake = sync.NewCond(&r.mu)
+	go r.deliverMatches()
+	return r
+}
+
+// SetOnMatch задаёт обработчик завершённых матчей: match — сам матч, total — все матчи,
+// принятые к его завершению. Вызывается по очереди из одной горутины и без блокировки приёмника,
+// поэтому долгий обработчик (пересчёт статистики, HTML) не задерживает приём логов.
+// Задавать до первой пачки строк.
+func (r *Receiver) SetOnMatch(fn func(match, total *logparser.ParseResult)) {
+	r.onMatch = fn
+}
+
+// SetSourceLimits ограничивает память под серверы: сервер, не присылавший строк дольше idleTimeout,
+// забывается (табло пропадает, незавершённый матч отбрасывается), а пачки от новых серверов сверх
+// maxSources отклоняются с ErrTooManySources. Значения <= 0 оставляют ограничения по умолчанию.
+func (r *Receiver) SetSourceLimits(idleTimeout time.Duration, maxSources int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if idleTimeout > 0 {
+		r.idleTimeout = idleTimeout
+	}
+	if maxSources > 0 {
+		r.maxSources = maxSources
+	}
+}
+
+// Handler возвращает HTTP обработчик приёмника
+func (r *Receiver) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /logs/{server}", r.handleLogs)
+	mux.HandleFunc("POST /logs", r.handleLogs)
+	mux.HandleFunc("GET /scoreboard", r.handleScoreboards)
+	mux.HandleFunc("GET /scoreboard/{server}", r.handleScoreboard)
+	mux.HandleFunc("GET /events", r.handleEvents)
+	return mux
+}
+
+// Feed разбирает пачку строк сервера server. Строки могут быть в формате logaddress_add_http
+// или обычного файла лога (`L MM/DD/YYYY - HH:MM:SS: ...`).
+func (r *Receiver) Feed(server string, lines []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+
+	now := r.now()
+	r.evictIdle(now)
+	src := r.sources[server]
+	if src == nil {
+		if len(r.sources) >= r.maxSources {
+			return ErrTooManySources
+		}
+		src = r.newSource(server)
+	}
+	src.lastSeen = now
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		src.stream.Feed(normalizeLine(line))
+	}
+	return nil
+}
+
+// evictIdle забывает серверы, не присылавшие строк дольше idleTimeout. Вызывается под r.mu.
+func (r *Receiver) evictIdle(now time.Time) {
+	for server, src := range r.sources {
+		if now.Sub(src.lastSeen) > r.idleTimeout {
+			src.stream.Close()
+			delete(r.sources, server)
+		}
+	}
+}
+
+// Close завершает разбор всех серверов (незавершённые матчи отбрасываются)
+// и ждёт, пока onMatch получит все завершённые матчи
+func (r *Receiver) Close() {
+	r.mu.Lock()
+	for _, src := range r.sources {
+		src.stream.Close()
+	}
+	r.sources = make(map[string]*source)
+	r.closed = true
+	r.wake.Signal()
+	r.mu.Unlock()
+
+	<-r.done
+}
+
+// deliverMatches передаёт завершённые матчи onMatch вне блокировки приёмника, по одному и в порядке завершения
+func (r *Receiver) deliverMatches() {
+	defer close(r.done)
+	for {
+		r.mu.Lock()
+		for len(r.pending) == 0 && !r.closed {
+			r.wake.Wait()
+		}
+		updates := r.pending
+		r.pending = nil
+		r.mu.Unlock()
+
+		if len(updates) == 0 {
+			return
+		}
+		for _, update := range updates {
+			if r.onMatch != nil {
+				r.onMatch(update.match, update.total)
+			}
+		}
+	}
+}
+
+// Scoreboards возвращает табло всех серверов, отсортированные по имени сервера
+func (r *Receiver) Scoreboards() []Scoreboard {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	boards := make([]Scoreboard, 0, len(r.sources))
+	for _, src := range r.sources {
+		boards = append(boards, src.board.Scoreboard)
+	}
+	sort.Slice(boards, func(i, j int) bool {
+		return boards[i].Server < boards[j].Server
+	})
+	return boards
+}
+
+// newSource создает разбор сервера server при его первой пачке. Вызывается под r.mu.
+func (r *Receiver) newSource(server string) *source {
+	src := &source{board: newBoard(server)}
+	src.stream = r.parser.NewStream(server, r.result, logparser.StreamHooks{
+		OnKill: src.board.addKill,
+		OnRound: func(round logparser.RoundStats) {
+			src.board.addRound(round, r.now())
+			r.publish(src.board.Scoreboard)
+		},
+		OnMatch: func(match *logparser.ParseResult) {
+			src.board.finish(match.Matches[0], r.now())
+			extendDateRange(r.result, match.Matches[0].Start)
+			r.publish(src.board.Scoreboard)
+			r.pending = append(r.pending, matchUpdate{match: match, total: snapshotResult(r.result)})
+			r.wake.Signal()
+		},
+	})
+	r.sources[server] = src
+	return src
+}
+
+// publish рассылает табло подписчикам /events. Медленный подписчик пропускает обновление.
+// Вызывается под r.mu.
+func (r *Receiver) publish(board Scoreboard) {
+	data, err := json.Marshal(board)
+	if err != nil {
+		log.Printf("receiver: табло %s: %v", board.Server, err)
+		return
+	}
+	for ch := range r.subscribers {
+		select {
+		case ch <- data:
+		default:
+		}
+	}
+}
+
+// handleLogs принимает пачку строк от сервера
+func (r *Receiver) handleLogs(w http.ResponseWriter, req *http.Request) {
+	server := serverName(req)
+	body := http.MaxBytesReader(w, req.Body, maxBatchBytes)
+	var lines []string
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), maxBatchBytes)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := r.Feed(server, lines); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleScoreboards отдаёт табло всех серверов
+func (r *Receiver) handleScoreboards(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, r.Scoreboards())
+}
+
+// handleScoreboard отдаёт табло одного сервера
+func (r *Receiver) handleScoreboard(w http.ResponseWriter, req *http.Request) {
+	server := req.PathValue("server")
+	for _, board := range r.Scoreboards() {
+		if board.Server == server {
+			writeJSON(w, board)
+			return
+		}
+	}
+	http.NotFound(w, req)
+}
+
+// handleEvents отдаёт поток server-sent events: сначала текущие табло, затем каждое обновление
+func (r *Receiver) handleEvents(w http.ResponseWriter, req *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	updates := make(chan []byte, 16)
+	r.mu.Lock()
+	r.subscribers[updates] = struct{}{}
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.subscribers, updates)
+		r.mu.Unlock()
+	}()
+
+	for _, board := range r.Scoreboards() {
+		data, err := json.Marshal(board)
+		if err != nil {
+			continue
+		}
+		writeEvent(w, data)
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case data := <-updates:
+			writeEvent(w, data)
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent пишет одно событие scoreboard в поток SSE
+func writeEvent(w io.Writer, data []byte) {
+	_, _ = fmt.Fprintf(w, "event: scoreboard\ndata: %s\n\n", data)
+}
+
+// writeJSON отвечает значением v в JSON
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("receiver: ответ JSON: %v", err)
+	}
+}
+
+// serverName определяет сервер пачки: из пути, по заголовку CS2 или по адресу отправителя.
+// Токен сервера — секрет, поэтому в табло и SSE попадает только его короткий хэш.
+func serverName(req *http.Request) string {
+	if server := req.PathValue("server"); server != "" {
+		return server
+	}
+	if token := req.Header.Get("X-Server-Instance-Token"); token != "" {
+		sum := sha256.Sum256([]byte(token))
+		return "token-" + hex.EncodeToString(sum[:4])
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// normalizeLine приводит строку logaddress_add_http к формату файла лога,
+// который понимают шаблоны logparser: "L MM/DD/YYYY - HH:MM:SS: ..."
+func normalizeLine(line string) string {
+	loc := httpLinePattern.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return line
+	}
+	return "L " + line[loc[2]:loc[3]] + ": " + line[loc[1]:]
+}
+
+// snapshotResult копирует result для onMatch вне блокировки. Стрим только дописывает события в конец,
+// поэтому срезам достаточно ограничить ёмкость: новые матчи пишутся за пределами копии. Карты копируются.
+func snapshotResult(result *logparser.ParseResult) *logparser.ParseResult {
+	snap := *result
+	snap.Players = maps.Clone(result.Players)
+	snap.WeaponSet = maps.Clone(result.WeaponSet)
+	snap.KillEvents = slices.Clip(result.KillEvents)
+	snap.FlashEvents = slices.Clip(result.FlashEvents)
+	snap.DamageEvents = slices.Clip(result.DamageEvents)
+	snap.GrenadeEvents = slices.Clip(result.GrenadeEvents)
+	snap.DefuseEvents = slices.Clip(result.DefuseEvents)
+	snap.BombEvents = slices.Clip(result.BombEvents)
+	snap.HostageEvents = slices.Clip(result.HostageEvents)
+	snap.PurchaseEvents = slices.Clip(result.PurchaseEvents)
+	snap.CustomEvents = slices.Clip(result.CustomEvents)
+	snap.ShameEvents = slices.Clip(result.ShameEvents)
+	snap.RoundStats = slices.Clip(result.RoundStats)
+	snap.Matches = slices.Clip(result.Matches)
+	snap.DroppedMatches = slices.Clip(result.DroppedMatches)
+	snap.Diagnostics.Files = slices.Clip(result.Diagnostics.Files)
+	return &snap
+}
+
+// extendDateRange расширяет диапазон дат result датой матча (формат как у ParseResult)
+func extendDateRange(result *logparser.ParseResult, start time.Time) {
+	if start.IsZero() {
+		return
+	}
+	formatted := start.Format("02-01-2006")
+	if begin, err := time.Parse("02-01-2006", result.StartDate); err != nil || start.Before(begin) {
+		result.StartDate = formatted
+	}
+	if end, err := time.Parse("02-01-2006", result.EndDate); err != nil || !start.Before(end.AddDate(0, 0, 1)) {
+		result.EndDate = formatted
+	}
+}
@@ -0,0 +1,234 @@
+package receiver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"oldfartscounter/internal/logparser"
+)
+
+// recordedLog is a one-round match as written to a server log file
+const recordedLog = `L 09/05/2025 - 18:01:00: World triggered "Match_Start" on "de_dust2"
+L 09/05/2025 - 18:01:29: "Alice<2><[U:1:100]><CT>" [-100 200 10] attacked "Bob<3><[U:1:200]><TERRORIST>" [50 60 10] with "m4a1" (damage "100") (damage_armor "0") (health "0") (armor "97") (hitgroup "head")
+L 09/05/2025 - 18:01:30: "Alice<2><[U:1:100]><CT>" [-100 200 10] killed "Bob<3><[U:1:200]><TERRORIST>" [50 60 10] with "m4a1" (headshot)
+L 09/05/2025 - 18:02:00: JSON_BEGIN{
+L 09/05/2025 - 18:02:00: "name" : "round_stats",
+L 09/05/2025 - 18:02:00: "round_number" : "1",
+L 09/05/2025 - 18:02:00: "score_t" : "0",
+L 09/05/2025 - 18:02:00: "score_ct" : "1",
+L 09/05/2025 - 18:02:00: "map" : "de_dust2",
+L 09/05/2025 - 18:02:00: "server" : "Old Farts",
+L 09/05/2025 - 18:02:00: "fields" : "             accountid,   team,  money,  kills, deaths,assists,    dmg,    hsp,    kdr,    adr,    mvp,     ef,     ud,     3k,     4k,     5k,clutchk, firstk,pistolk,sniperk, blindk,  bombk,firedmg,uniquek,  dinks,chickenk",
+L 09/05/2025 - 18:02:00: "players" : {
+L 09/05/2025 - 18:02:00: "player_0" : "                   100,      3,   4200,      1,      0,      0,    100, 100.00,   0.00,    100,      1,      0,      0,      0,      0,      0,      0,      1,      0,      0,      0,      0,      0,      1,      1,      0",
+L 09/05/2025 - 18:02:00: "player_1" : "                   200,      2,   3100,      0,      1,      0,      0,   0.00,   0.00,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0,      0"
+L 09/05/2025 - 18:02:00: }}
+L 09/05/2025 - 18:02:00: JSON_END
+L 09/05/2025 - 18:02:01: Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")
+L 09/05/2025 - 18:30:00: Game Over: competitive de_dust2 score 13:6 after 28 min
+`
+
+// TestNormalizeLine checks the conversion between HTTP and file log line formats
+func TestNormalizeLine(t *testing.T) {
+	file := `L 09/05/2025 - 18:01:00: World triggered "Match_Start" on "de_dust2"`
+	sent := httpLine(file)
+	if sent != `09/05/2025 - 18:01:00.000 - World triggered "Match_Start" on "de_dust2"` {
+		t.Fatalf("Unexpected HTTP line: %q", sent)
+	}
+	if got := normalizeLine(sent); got != file {
+		t.Errorf("Expected %q, got %q", file, got)
+	}
+	if got := normalizeLine(file); got != file {
+		t.Errorf("File lines must pass through, got %q", got)
+	}
+}
+
+// TestReceiver_Replay posts a recorded log like a CS2 server and checks the scoreboard, SSE and completed matches
+func TestReceiver_Replay(t *testing.T) {
+	r := New(logparser.New())
+	var matches []*logparser.Match
+	var total int
+	r.SetOnMatch(func(match, all *logparser.ParseResult) {
+		matches = append(matches, &match.Matches[0])
+		total = len(all.Matches)
+	})
+	server := httptest.NewServer(r.Handler())
+	defer server.Close()
+
+	// Subscribe to SSE before the log arrives
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected text/event-stream, got %s", ct)
+	}
+
+	err = Replay(ctx, server.URL+"/logs/office", strings.NewReader(recordedLog), ReplayOptions{Batch: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Round and Game Over each publish an update
+	events := bufio.NewScanner(resp.Body)
+	var got []Scoreboard
+	for len(got) < 2 && events.Scan() {
+		if data, ok := strings.CutPrefix(events.Text(), "data: "); ok {
+			var board Scoreboard
+			if err := json.Unmarshal([]byte(data), &board); err != nil {
+				t.Fatalf("bad event %q: %v", data, err)
+			}
+			got = append(got, board)
+		}
+	}
+	if len(got) != 2 || got[0].Finished || !got[1].Finished {
+		t.Fatalf("Expected a round update and a final update, got %+v", got)
+	}
+
+	resp2, err := http.Get(server.URL + "/scoreboard/office")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() {
+		_ = resp2.Body.Close()
+	}()
+	var board Scoreboard
+	if err := json.NewDecoder(resp2.Body).Decode(&board); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if board.Map != "de_dust2" || board.ScoreCT != 13 || board.ScoreT != 6 || !board.Finished {
+		t.Errorf("Unexpected scoreboard header: %+v", board)
+	}
+	if len(board.Players) != 2 {
+		t.Fatalf("Expected 2 players, got %+v", board.Players)
+	}
+	alice := board.Players[0]
+	if alice.Name != "Alice" || alice.Team != 3 || alice.Kills != 1 || alice.Deaths != 0 || alice.ADR != 100 || alice.EPI <= 0 {
+		t.Errorf("Unexpected CT row: %+v", alice)
+	}
+	if bob := board.Players[1]; bob.Name != "Bob" || bob.Deaths != 1 {
+		t.Errorf("Unexpected T row: %+v", bob)
+	}
+
+	resp3, err := http.Get(server.URL + "/scoreboard/unknown")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp3.Body.Close()
+	if resp3.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown server, got %d", resp3.StatusCode)
+	}
+
+	// Close waits until OnMatch has received every completed match
+	r.Close()
+	if len(matches) != 1 || !strings.HasPrefix(matches[0].ID, "office#1@") || total != 1 {
+		t.Fatalf("Expected one completed match office#1, got %d (total %d)", len(matches), total)
+	}
+}
+
+// TestReceiver_SourceLimits checks token hashing, idle server eviction and the server limit
+func TestReceiver_SourceLimits(t *testing.T) {
+	r := New(logparser.New())
+	defer r.Close()
+	clock := time.Date(2025, 9, 5, 18, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return clock }
+	r.SetSourceLimits(10*time.Minute, 2)
+	server := httptest.NewServer(r.Handler())
+	defer server.Close()
+
+	post := func(path, token string) int {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader(httpLine(`L 09/05/2025 - 18:01:00: World triggered "Match_Start" on "de_dust2"`)))
+		if token != "" {
+			req.Header.Set("X-Server-Instance-Token", token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := post("/logs", "s3cr3t"); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	boards := r.Scoreboards()
+	if len(boards) != 1 || !strings.HasPrefix(boards[0].Server, "token-") || strings.Contains(boards[0].Server, "s3cr3t") {
+		t.Fatalf("Expected the token to be published only as a hash, got %+v", boards)
+	}
+
+	clock = clock.Add(5 * time.Minute)
+	if code := post("/logs/office", ""); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if code := post("/logs/garage", ""); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 over the server limit, got %d", code)
+	}
+
+	// The token server is idle for 11 minutes and is forgotten; office keeps sending
+	clock = clock.Add(6 * time.Minute)
+	if code := post("/logs/garage", ""); code != http.StatusOK {
+		t.Fatalf("Expected 200 after the idle server was dropped, got %d", code)
+	}
+	boards = r.Scoreboards()
+	if len(boards) != 2 || boards[0].Server != "garage" || boards[1].Server != "office" {
+		t.Errorf("Expected garage and office, got %+v", boards)
+	}
+}
+
+// TestReceiver_SlowOnMatch checks that a slow OnMatch does not block log batches and scoreboards of other servers
+func TestReceiver_SlowOnMatch(t *testing.T) {
+	r := New(logparser.New())
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var totals []int
+	r.SetOnMatch(func(match, total *logparser.ParseResult) {
+		totals = append(totals, len(total.Matches))
+		if len(totals) == 1 {
+			close(started)
+			<-release
+		}
+	})
+	server := httptest.NewServer(r.Handler())
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := Replay(ctx, server.URL+"/logs/office", strings.NewReader(recordedLog), ReplayOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-started:
+	case <-ctx.Done():
+		t.Fatal("OnMatch was not called")
+	}
+
+	// OnMatch is still running: another server's match and the scoreboard must go through
+	postCtx, postCancel := context.WithTimeout(context.Background(), time.Second)
+	defer postCancel()
+	if err := Replay(postCtx, server.URL+"/logs/garage", strings.NewReader(recordedLog), ReplayOptions{}); err != nil {
+		t.Fatalf("POST blocked by a slow OnMatch: %v", err)
+	}
+	if boards := r.Scoreboards(); len(boards) != 2 || !boards[0].Finished || !boards[1].Finished {
+		t.Fatalf("Expected two finished scoreboards, got %+v", boards)
+	}
+
+	close(release)
+	r.Close()
+	// Each OnMatch gets the matches completed by then, not the ones that arrived while the previous one was running
+	if len(totals) != 2 || totals[0] != 1 || totals[1] != 2 {
+		t.Errorf("Expected totals [1 2], got %v", totals)
+	}
+}
@@ -0,0 +1,98 @@
+package receiver
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// fileLinePattern — префикс строки файла лога: "L MM/DD/YYYY - HH:MM:SS: "
+var fileLinePattern = regexp.MustCompile(`^L (\d{2}/\d{2}/\d{4} - \d{2}:\d{2}:\d{2}): `)
+
+// ReplayOptions настраивает Replay
+type ReplayOptions struct {
+	Batch  int           // Строк в одном запросе (по умолчанию 100)
+	Delay  time.Duration // Пауза между запросами (0 — без пауз)
+	Client *http.Client  // HTTP клиент (по умолчанию http.DefaultClient)
+}
+
+// Replay изображает сервер CS2 с logaddress_add_http: читает записанный лог из r,
+// переводит строки в формат HTTP логов и отправляет их пачками POST запросами на url
+func Replay(ctx context.Context, url string, r io.Reader, opts ReplayOptions) error {
+	if opts.Batch <= 0 {
+		opts.Batch = 100
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+
+	var batch []string
+	send := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := postBatch(ctx, opts.Client, url, batch)
+		batch = batch[:0]
+		if err != nil || opts.Delay <= 0 {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(opts.Delay):
+			return nil
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxBatchBytes)
+	for scanner.Scan() {
+		batch = append(batch, httpLine(scanner.Text()))
+		if len(batch) == opts.Batch {
+			if err := send(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return send()
+}
+
+// postBatch отправляет одну пачку строк
+func postBatch(ctx context.Context, client *http.Client, url string, lines []string) error {
+	body := strings.NewReader(strings.Join(lines, "\n") + "\n")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post logs: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed with status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// httpLine переводит строку файла лога в формат logaddress_add_http (обратное normalizeLine)
+func httpLine(line string) string {
+	loc := fileLinePattern.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return line
+	}
+	return line[loc[2]:loc[3]] + ".000 - " + line[loc[1]:]
+}
@@ -0,0 +1,134 @@
+package receiver
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"oldfartscounter/internal/logparser"
+)
+
+// Scoreboard — живое табло матча на одном сервере. Обновляется после каждого раунда
+// по JSON блоку round_stats, поэтому K/D и EPI отстают от убийств не больше чем на раунд.
+type Scoreboard struct {
+	Server   string             // Имя сервера в receiver (из URL, хэш токена или адрес отправителя)
+	MatchID  string             // ID текущего матча (`<server>#<номер>@<хэш>`, см. logparser.Match.ID)
+	Map      string             // Карта
+	Round    int                // Номер последнего завершённого раунда
+	ScoreCT  int                // Счёт CT
+	ScoreT   int                // Счёт T
+	Finished bool               // Матч дошёл до Game Over
+	Updated  time.Time          // Когда табло менялось в последний раз
+	Players  []ScoreboardPlayer // Игроки: сначала CT, затем T, внутри — по EPI
+}
+
+// ScoreboardPlayer — строка табло
+type ScoreboardPlayer struct {
+	AccountID int64   // Steam Account ID
+	Name      string  // Ник из строк убийств ("[U:1:N]", пока игрок никого не убил и не умер)
+	Team      int     // 2=T, 3=CT — сторона в последнем раунде
+	Rounds    int     // Сыгранные раунды
+	Kills     int     // Убийства
+	Deaths    int     // Смерти
+	Assists   int     // Ассисты
+	ADR       float64 // Средний урон за раунд
+	EPI       float64 // Средний EPI за раунды матча
+}
+
+// board накапливает табло текущего матча сервера
+type board struct {
+	Scoreboard
+	players map[int64]*playerTotals
+	names   map[string]string // SteamID -> последний ник; переживает смену матча
+}
+
+// playerTotals — суммы по раундам, из которых считаются средние табло
+type playerTotals struct {
+	ScoreboardPlayer
+	damage int
+	rating float64
+}
+
+// newBoard создает пустое табло сервера
+func newBoard(server string) *board {
+	return &board{
+		Scoreboard: Scoreboard{Server: server, Players: []ScoreboardPlayer{}},
+		players:    make(map[int64]*playerTotals),
+		names:      make(map[string]string),
+	}
+}
+
+// addKill запоминает ники убийцы и жертвы
+func (b *board) addKill(kill logparser.KillEvent) {
+	b.names[kill.KillerSID] = kill.KillerName
+	b.names[kill.VictimSID] = kill.VictimName
+}
+
+// addRound добавляет раунд к табло; раунд нового матча начинает табло заново
+func (b *board) addRound(round logparser.RoundStats, now time.Time) {
+	if round.MatchID != b.MatchID {
+		b.MatchID = round.MatchID
+		b.Finished = false
+		b.players = make(map[int64]*playerTotals)
+	}
+	b.Map = round.Map
+	b.Round = round.RoundNumber
+	b.ScoreCT = round.ScoreCT
+	b.ScoreT = round.ScoreT
+	b.Updated = now
+
+	for _, ps := range round.Players {
+		totals, ok := b.players[ps.AccountID]
+		if !ok {
+			totals = &playerTotals{ScoreboardPlayer: ScoreboardPlayer{AccountID: ps.AccountID}}
+			b.players[ps.AccountID] = totals
+		}
+		totals.Team = ps.Team
+		totals.Rounds++
+		totals.Kills += ps.Kills
+		totals.Deaths += ps.Deaths
+		totals.Assists += ps.Assists
+		totals.damage += ps.Damage
+		totals.rating += ps.Rating
+	}
+	b.refresh()
+}
+
+// finish отмечает матч завершённым и берёт итоговый счёт из строки Game Over
+func (b *board) finish(match logparser.Match, now time.Time) {
+	b.MatchID = match.ID
+	b.Map = match.Map
+	b.ScoreCT = match.ScoreCT
+	b.ScoreT = match.ScoreT
+	b.Finished = true
+	b.Updated = now
+	b.refresh()
+}
+
+// refresh пересчитывает строки табло из накопленных сумм
+func (b *board) refresh() {
+	players := make([]ScoreboardPlayer, 0, len(b.players))
+	for _, totals := range b.players {
+		player := totals.ScoreboardPlayer
+		player.Name = b.names[fmt.Sprintf("[U:1:%d]", player.AccountID)]
+		if player.Name == "" {
+			player.Name = fmt.Sprintf("[U:1:%d]", player.AccountID)
+		}
+		if player.Rounds > 0 {
+			player.ADR = float64(totals.damage) / float64(player.Rounds)
+			player.EPI = totals.rating / float64(player.Rounds)
+		}
+		players = append(players, player)
+	}
+
+	sort.Slice(players, func(i, j int) bool {
+		if players[i].Team != players[j].Team {
+			return players[i].Team > players[j].Team
+		}
+		if players[i].EPI != players[j].EPI {
+			return players[i].EPI > players[j].EPI
+		}
+		return players[i].AccountID < players[j].AccountID
+	})
+	b.Players = players
+}
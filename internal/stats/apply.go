@@ -0,0 +1,573 @@
+package stats
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"oldfartscounter/internal/logparser"
+)
+
+// Apply дописывает в data один завершённый матч (ParseResult из logparser.StreamHooks.OnMatch) без
+// пересчёта истории: события, раунды и срезы по датам дописываются, а секции статистики, первые дуэли
+// и рейтинги игроков считаются по одному матчу и складываются с накопленными. Рейтинги моделей
+// (и BayesianEPI) сравнивают игроков со средним по всему пулу, поэтому пересчитываются по накопленным раундам.
+// data — результат Process или предыдущих Apply того же процессора; по итогу data совпадает с Process
+// по всем матчам (с точностью до округления сумм с плавающей точкой).
+func (p *Processor) Apply(data *StatsData, match *logparser.ParseResult) {
+	part := p.Process(match)
+
+	// Игроки и оружие: объединённые списки и перестановки старых и новых индексов в них
+	players := mergePlayers(data.Players, part.Players)
+	playerIndex := make(map[string]int, len(players))
+	for i, player := range players {
+		playerIndex[player.Key] = i
+	}
+	oldP := playerPerm(data.Players, playerIndex)
+	newP := playerPerm(part.Players, playerIndex)
+
+	weapons := mergeNames(data.Weapons, part.Weapons)
+	sort.Slice(weapons, func(i, j int) bool {
+		return strings.ToLower(weapons[i]) < strings.ToLower(weapons[j])
+	})
+	weaponIndex := indexOf(weapons)
+	oldW := namePerm(data.Weapons, weaponIndex)
+	newW := namePerm(part.Weapons, weaponIndex)
+	n, w := len(players), len(weapons)
+
+	killMatrix := sumGrids(n, n, data.KillMatrix.Matrix, oldP, oldP, part.KillMatrix.Matrix, newP, newP)
+	data.KillMatrix = KillMatrix{Matrix: killMatrix, Max: gridMax(killMatrix)}
+
+	killerWeapon := sumGrids(n, w, data.WeaponData.KillerWeaponMatrix, oldP, oldW, part.WeaponData.KillerWeaponMatrix, newP, newW)
+	victimWeapon := sumGrids(n, w, data.WeaponData.VictimWeaponMatrix, oldP, oldW, part.WeaponData.VictimWeaponMatrix, newP, newW)
+	distanceSum := sumSlices(w, data.WeaponData.distanceSum, oldW, part.WeaponData.distanceSum, newW)
+	distanceCount := sumSlices(w, data.WeaponData.distanceCount, oldW, part.WeaponData.distanceCount, newW)
+	data.WeaponData = WeaponData{
+		KillerWeaponMatrix: killerWeapon,
+		VictimWeaponMatrix: victimWeapon,
+		WeaponKillsMatrix:  transposeMatrix(killerWeapon),
+		AvgDistance:        averageDistances(distanceSum, distanceCount),
+		KillerMax:          gridMax(killerWeapon),
+		VictimMax:          gridMax(victimWeapon),
+		distanceSum:        distanceSum,
+		distanceCount:      distanceCount,
+	}
+
+	data.KillModifierData = KillModifierData{
+		ByPlayer: sumModifiers(n, data.KillModifierData.ByPlayer, oldP, part.KillModifierData.ByPlayer, newP),
+		ByWeapon: sumModifiers(w, data.KillModifierData.ByWeapon, oldW, part.KillModifierData.ByWeapon, newW),
+	}
+
+	flashCount := sumGrids(n, n, data.FlashData.CountMatrix, oldP, oldP, part.FlashData.CountMatrix, newP, newP)
+	flashSeconds := sumGrids(n, n, data.FlashData.SecondsMatrix, oldP, oldP, part.FlashData.SecondsMatrix, newP, newP)
+	data.FlashData = FlashData{
+		CountMatrix:   flashCount,
+		SecondsMatrix: flashSeconds,
+		CountMax:      gridMax(flashCount),
+		SecondsMax:    gridMax(flashSeconds),
+	}
+
+	data.DamageData = mergeDamageData(n, data.DamageData, oldP, part.DamageData, newP)
+
+	utility := UtilityData{
+		Grenades:       UtilityGrenades,
+		Throws:         sumGrids(n, len(UtilityGrenades), data.UtilityData.Throws, oldP, nil, part.UtilityData.Throws, newP, nil),
+		Damage:         sumGrids(n, len(UtilityGrenades), data.UtilityData.Damage, oldP, nil, part.UtilityData.Damage, newP, nil),
+		Rounds:         sumSlices(n, data.UtilityData.Rounds, oldP, part.UtilityData.Rounds, newP),
+		EnemiesBlinded: sumSlices(n, data.UtilityData.EnemiesBlinded, oldP, part.UtilityData.EnemiesBlinded, newP),
+		TeamBlinded:    sumSlices(n, data.UtilityData.TeamBlinded, oldP, part.UtilityData.TeamBlinded, newP),
+		ReportedUD:     sumSlices(n, data.UtilityData.ReportedUD, oldP, part.UtilityData.ReportedUD, newP),
+	}
+	utility.finish()
+	data.UtilityData = utility
+
+	data.BombData = BombData{
+		Maps:          mergeBombMaps(data.BombData.Maps, part.BombData.Maps),
+		Plants:        sumSlices(n, data.BombData.Plants, oldP, part.BombData.Plants, newP),
+		PlantsA:       sumSlices(n, data.BombData.PlantsA, oldP, part.BombData.PlantsA, newP),
+		PlantsB:       sumSlices(n, data.BombData.PlantsB, oldP, part.BombData.PlantsB, newP),
+		PostPlantWins: sumSlices(n, data.BombData.PostPlantWins, oldP, part.BombData.PostPlantWins, newP),
+		Drops:         sumSlices(n, data.BombData.Drops, oldP, part.BombData.Drops, newP),
+	}
+
+	data.HostageData = HostageData{
+		Rescues:    sumSlices(n, data.HostageData.Rescues, oldP, part.HostageData.Rescues, newP),
+		Pickups:    sumSlices(n, data.HostageData.Pickups, oldP, part.HostageData.Pickups, newP),
+		Kills:      sumSlices(n, data.HostageData.Kills, oldP, part.HostageData.Kills, newP),
+		Rounds:     sumSlices(n, data.HostageData.Rounds, oldP, part.HostageData.Rounds, newP),
+		RescueWins: sumSlices(n, data.HostageData.RescueWins, oldP, part.HostageData.RescueWins, newP),
+	}
+
+	economy := EconomyData{
+		BuyTypes:     BuyTypes,
+		TeamRounds:   sumSlices(len(BuyTypes), data.EconomyData.TeamRounds, nil, part.EconomyData.TeamRounds, nil),
+		TeamWins:     sumSlices(len(BuyTypes), data.EconomyData.TeamWins, nil, part.EconomyData.TeamWins, nil),
+		PlayerRounds: sumGrids(n, len(BuyTypes), data.EconomyData.PlayerRounds, oldP, nil, part.EconomyData.PlayerRounds, newP, nil),
+		PlayerWins:   sumGrids(n, len(BuyTypes), data.EconomyData.PlayerWins, oldP, nil, part.EconomyData.PlayerWins, newP, nil),
+		ForceLosses:  sumSlices(n, data.EconomyData.ForceLosses, oldP, part.EconomyData.ForceLosses, newP),
+		Saves:        sumSlices(n, data.EconomyData.Saves, oldP, part.EconomyData.Saves, newP),
+		SavedValue:   sumSlices(n, data.EconomyData.SavedValue, oldP, part.EconomyData.SavedValue, newP),
+		spent:        sumSlices(n, data.EconomyData.spent, oldP, part.EconomyData.spent, newP),
+	}
+	economy.finish()
+	data.EconomyData = economy
+
+	data.ShameData = ShameData{
+		TeamKills:   sumSlices(n, data.ShameData.TeamKills, oldP, part.ShameData.TeamKills, newP),
+		TeamKilled:  sumSlices(n, data.ShameData.TeamKilled, oldP, part.ShameData.TeamKilled, newP),
+		Suicides:    sumSlices(n, data.ShameData.Suicides, oldP, part.ShameData.Suicides, newP),
+		WorldDeaths: sumSlices(n, data.ShameData.WorldDeaths, oldP, part.ShameData.WorldDeaths, newP),
+		BombDeaths:  sumSlices(n, data.ShameData.BombDeaths, oldP, part.ShameData.BombDeaths, newP),
+	}
+
+	data.ClutchData = ClutchData{
+		Attempts: sumGrids(n, logparser.MaxClutchEnemies, data.ClutchData.Attempts, oldP, nil, part.ClutchData.Attempts, newP, nil),
+		Wins:     sumGrids(n, logparser.MaxClutchEnemies, data.ClutchData.Wins, oldP, nil, part.ClutchData.Wins, newP, nil),
+	}
+
+	attempts := sumSlices(n, data.DefuseData.Attempts, oldP, part.DefuseData.Attempts, newP)
+	data.DefuseData = DefuseData{
+		Attempts:          attempts,
+		WithKit:           sumSlices(n, data.DefuseData.WithKit, oldP, part.DefuseData.WithKit, newP),
+		WithoutKit:        sumSlices(n, data.DefuseData.WithoutKit, oldP, part.DefuseData.WithoutKit, newP),
+		SuccessWithKit:    sumSlices(n, data.DefuseData.SuccessWithKit, oldP, part.DefuseData.SuccessWithKit, newP),
+		SuccessWithoutKit: sumSlices(n, data.DefuseData.SuccessWithoutKit, oldP, part.DefuseData.SuccessWithoutKit, newP),
+		Abandoned:         sumSlices(n, data.DefuseData.Abandoned, oldP, part.DefuseData.Abandoned, newP),
+		Failed:            sumSlices(n, data.DefuseData.Failed, oldP, part.DefuseData.Failed, newP),
+		TotalMax:          gridMax([][]int{attempts}),
+	}
+
+	data.OpeningStats = mergeOpeningStats(data.OpeningStats, part.OpeningStats)
+
+	// Диапазоны матча указывают в срезы ParseResult: сдвигаем их на уже накопленные события
+	for _, m := range part.Matches {
+		data.Matches = append(data.Matches, data.shiftMatch(m))
+	}
+	data.appendEvents(part)
+
+	// Рейтинги: суммы складываются, рейтинги моделей пересчитываются по всем раундам
+	ratings := mergePlayerRatings(data.PlayerRatings, part.PlayerRatings)
+	data.ModelRatings, data.AverageMu = rateModels(RatingInput{Rounds: data.RoundStats, Kills: data.KillEvents, Trades: data.TradeEvents}, ratings)
+	data.PlayerRatings = orderByDefaultModel(ratings, data.ModelRatings)
+
+	// Актуальные ники из рейтингов, как в Process
+	for _, rating := range data.PlayerRatings {
+		if i, ok := playerIndex[fmt.Sprintf("[U:1:%d]", rating.AccountID)]; ok {
+			players[i].Title = rating.Name
+		}
+	}
+	data.Players = players
+	data.Weapons = weapons
+
+	data.startDate, data.endDate = extendPeriod(data.startDate, data.endDate, part.startDate, part.endDate)
+	data.DateRange = formatDateRange(data.startDate, data.endDate)
+}
+
+// appendEvents дописывает события, раунды и срезы по датам матча part (результат Process по одному матчу)
+func (d *StatsData) appendEvents(part *StatsData) {
+	// Тимкиллы (SetCountTeamKills) идут в KillEvents после убийств из лога, как в Process
+	d.KillEvents = slices.Insert(d.KillEvents, d.logKills, part.KillEvents[:part.logKills]...)
+	d.KillEvents = append(d.KillEvents, part.KillEvents[part.logKills:]...)
+	d.logKills += part.logKills
+	d.FlashEvents = append(d.FlashEvents, part.FlashEvents...)
+	d.DamageEvents = append(d.DamageEvents, part.DamageEvents...)
+	d.GrenadeEvents = append(d.GrenadeEvents, part.GrenadeEvents...)
+	d.BombEvents = append(d.BombEvents, part.BombEvents...)
+	d.HostageEvents = append(d.HostageEvents, part.HostageEvents...)
+	d.DefuseEvents = append(d.DefuseEvents, part.DefuseEvents...)
+	d.ShameEvents = append(d.ShameEvents, part.ShameEvents...)
+	d.TradeEvents = append(d.TradeEvents, part.TradeEvents...)
+	d.OpeningDuels = append(d.OpeningDuels, part.OpeningDuels...)
+	d.RoundStats = append(d.RoundStats, part.RoundStats...)
+	d.purchases += part.purchases
+	d.customs += part.customs
+
+	// Раунды урона продолжают нумерацию накопленных
+	for _, hits := range part.DailyDamage {
+		for i := range hits {
+			hits[i].RoundGroup += d.roundGroups
+		}
+	}
+	d.roundGroups += part.roundGroups
+
+	d.DailyKills = appendDaily(d.DailyKills, part.DailyKills)
+	d.DailyFlash = appendDaily(d.DailyFlash, part.DailyFlash)
+	d.DailyDamage = appendDaily(d.DailyDamage, part.DailyDamage)
+	d.DailyThrows = appendDaily(d.DailyThrows, part.DailyThrows)
+	d.DailyBomb = appendDaily(d.DailyBomb, part.DailyBomb)
+	d.DailyHostage = appendDaily(d.DailyHostage, part.DailyHostage)
+	d.DailyDefuse = appendDaily(d.DailyDefuse, part.DailyDefuse)
+	d.DailyShame = appendDaily(d.DailyShame, part.DailyShame)
+	d.DailyTrades = appendDaily(d.DailyTrades, part.DailyTrades)
+	d.DailyRounds = appendDaily(d.DailyRounds, part.DailyRounds)
+}
+
+// shiftMatch сдвигает диапазоны матча на количество событий, накопленных в d до него
+func (d *StatsData) shiftMatch(match logparser.Match) logparser.Match {
+	shift := func(r *logparser.EventRange, offset int) {
+		r.From += offset
+		r.To += offset
+	}
+	shift(&match.Rounds, len(d.RoundStats))
+	shift(&match.Kills, d.logKills)
+	shift(&match.Flashes, len(d.FlashEvents))
+	shift(&match.Damage, len(d.DamageEvents))
+	shift(&match.Grenades, len(d.GrenadeEvents))
+	shift(&match.Defuses, len(d.DefuseEvents))
+	shift(&match.Bomb, len(d.BombEvents))
+	shift(&match.Hostages, len(d.HostageEvents))
+	shift(&match.Buys, d.purchases)
+	shift(&match.Custom, d.customs)
+	shift(&match.Shame, len(d.ShameEvents))
+	return match
+}
+
+// appendDaily дописывает события из part в срезы по датам dst
+func appendDaily[T any](dst, part map[string][]T) map[string][]T {
+	if dst == nil {
+		dst = make(map[string][]T, len(part))
+	}
+	for date, events := range part {
+		dst[date] = append(dst[date], events...)
+	}
+	return dst
+}
+
+// extendPeriod расширяет период start—end периодом from—to (даты в формате DD-MM-YYYY)
+func extendPeriod(start, end, from, to string) (string, string) {
+	if start == "" || (from != "" && dayKey(from) < dayKey(start)) {
+		start = from
+	}
+	if end == "" || (to != "" && dayKey(to) > dayKey(end)) {
+		end = to
+	}
+	return start, end
+}
+
+// dayKey переводит дату DD-MM-YYYY в YYYY-MM-DD, которые сравниваются как строки
+func dayKey(date string) string {
+	if len(date) != len("02-01-2006") {
+		return date
+	}
+	return date[6:] + date[3:5] + date[:2]
+}
+
+// mergePlayers объединяет игроков: ник из нового матча, если там не запасное имя вида Player_N
+func mergePlayers(old, added []Player) []Player {
+	titles := make(map[string]string, len(old)+len(added))
+	for _, player := range old {
+		titles[player.Key] = player.Title
+	}
+	for _, player := range added {
+		if _, ok := titles[player.Key]; ok && isFallbackTitle(player) {
+			continue
+		}
+		titles[player.Key] = player.Title
+	}
+
+	players := make([]Player, 0, len(titles))
+	for key, title := range titles {
+		players = append(players, Player{Key: key, Title: title})
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return strings.ToLower(players[i].Title) < strings.ToLower(players[j].Title)
+	})
+	return players
+}
+
+// isFallbackTitle проверяет, что у игрока запасное имя (см. fallbackPlayerName)
+func isFallbackTitle(player Player) bool {
+	accountID, ok := logparser.AccountIDFromSID(player.Key)
+	return ok && player.Title == fallbackPlayerName(accountID)
+}
+
+// playerPerm возвращает новые индексы игроков players в объединённом списке
+func playerPerm(players []Player, index map[string]int) []int {
+	perm := make([]int, len(players))
+	for i, player := range players {
+		perm[i] = index[player.Key]
+	}
+	return perm
+}
+
+// mergeNames объединяет списки имён без повторов (порядок не определён)
+func mergeNames(old, added []string) []string {
+	set := make(map[string]struct{}, len(old)+len(added))
+	for _, name := range old {
+		set[name] = struct{}{}
+	}
+	for _, name := range added {
+		set[name] = struct{}{}
+	}
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	return names
+}
+
+// namePerm возвращает новые индексы имён names в объединённом списке
+func namePerm(names []string, index map[string]int) []int {
+	perm := make([]int, len(names))
+	for i, name := range names {
+		perm[i] = index[name]
+	}
+	return perm
+}
+
+// number — типы счётчиков, которые Apply складывает
+type number interface {
+	~int | ~float64
+}
+
+// at возвращает новый индекс элемента i по перестановке perm (nil — индекс не меняется)
+func at(perm []int, i int) int {
+	if perm == nil {
+		return i
+	}
+	return perm[i]
+}
+
+// sumSlices складывает a и b, переставленные в срез длины n: perm[i] — новый индекс элемента i
+func sumSlices[T number](n int, a []T, aPerm []int, b []T, bPerm []int) []T {
+	result := make([]T, n)
+	for i, v := range a {
+		result[at(aPerm, i)] += v
+	}
+	for i, v := range b {
+		result[at(bPerm, i)] += v
+	}
+	return result
+}
+
+// sumGrids складывает матрицы a и b, переставленные по строкам и столбцам в матрицу rows × cols
+func sumGrids[T number](rows, cols int, a [][]T, aRows, aCols []int, b [][]T, bRows, bCols []int) [][]T {
+	result := make([][]T, rows)
+	for i := range result {
+		result[i] = make([]T, cols)
+	}
+	add := func(grid [][]T, rowPerm, colPerm []int) {
+		for i, row := range grid {
+			for j, v := range row {
+				result[at(rowPerm, i)][at(colPerm, j)] += v
+			}
+		}
+	}
+	add(a, aRows, aCols)
+	add(b, bRows, bCols)
+	return result
+}
+
+// gridMax возвращает максимум матрицы; пустая или нулевая матрица даёт 1, как в build* функциях
+func gridMax[T number](grid [][]T) T {
+	var result T
+	for _, row := range grid {
+		for _, v := range row {
+			result = max(result, v)
+		}
+	}
+	if result == 0 {
+		result = 1
+	}
+	return result
+}
+
+// sumModifiers складывает модификаторы убийств, переставленные в срез длины n
+func sumModifiers(n int, a []KillModifiers, aPerm []int, b []KillModifiers, bPerm []int) []KillModifiers {
+	result := make([]KillModifiers, n)
+	for i, m := range a {
+		result[at(aPerm, i)].merge(m)
+	}
+	for i, m := range b {
+		result[at(bPerm, i)].merge(m)
+	}
+	return result
+}
+
+// merge прибавляет счётчики other
+func (m *KillModifiers) merge(other KillModifiers) {
+	m.Kills += other.Kills
+	m.Headshots += other.Headshots
+	m.Wallbangs += other.Wallbangs
+	m.NoScopes += other.NoScopes
+	m.ThroughSmoke += other.ThroughSmoke
+	m.AttackerBlind += other.AttackerBlind
+}
+
+// mergeDamageData складывает урон: у DamageData свои списки оружия и частей тела
+func mergeDamageData(n int, old DamageData, oldP []int, added DamageData, newP []int) DamageData {
+	weapons := mergeNames(old.Weapons, added.Weapons)
+	sort.Strings(weapons)
+	hitgroups := mergeNames(old.Hitgroups, added.Hitgroups)
+	sort.Strings(hitgroups)
+	weaponIndex, hitgroupIndex := indexOf(weapons), indexOf(hitgroups)
+	oldW, newW := namePerm(old.Weapons, weaponIndex), namePerm(added.Weapons, weaponIndex)
+	oldH, newH := namePerm(old.Hitgroups, hitgroupIndex), namePerm(added.Hitgroups, hitgroupIndex)
+
+	matrix := sumGrids(n, n, old.Matrix, oldP, oldP, added.Matrix, newP, newP)
+	return DamageData{
+		Matrix:         matrix,
+		Given:          sumSlices(n, old.Given, oldP, added.Given, newP),
+		Received:       sumSlices(n, old.Received, oldP, added.Received, newP),
+		WithoutKill:    sumSlices(n, old.WithoutKill, oldP, added.WithoutKill, newP),
+		TeamDamage:     sumSlices(n, old.TeamDamage, oldP, added.TeamDamage, newP),
+		Weapons:        weapons,
+		WeaponMatrix:   sumGrids(n, len(weapons), old.WeaponMatrix, oldP, oldW, added.WeaponMatrix, newP, newW),
+		Hitgroups:      hitgroups,
+		HitgroupMatrix: sumGrids(n, len(hitgroups), old.HitgroupMatrix, oldP, oldH, added.HitgroupMatrix, newP, newH),
+		Max:            gridMax(matrix),
+	}
+}
+
+// mergeBombMaps складывает статистику бомбы по картам
+func mergeBombMaps(old, added []BombMapStats) []BombMapStats {
+	byMap := make(map[string]*BombMapStats, len(old)+len(added))
+	maps := make([]BombMapStats, 0, len(old)+len(added))
+	for _, group := range [][]BombMapStats{old, added} {
+		for _, stats := range group {
+			ms := byMap[stats.Map]
+			if ms == nil {
+				ms = &BombMapStats{Map: stats.Map, WinConditions: make(map[string]int)}
+				byMap[stats.Map] = ms
+			}
+			ms.Rounds += stats.Rounds
+			ms.PlantsA += stats.PlantsA
+			ms.PlantsB += stats.PlantsB
+			ms.PostPlantWins += stats.PostPlantWins
+			for condition, count := range stats.WinConditions {
+				ms.WinConditions[condition] += count
+			}
+		}
+	}
+	for _, ms := range byMap {
+		maps = append(maps, *ms)
+	}
+	sort.Slice(maps, func(i, j int) bool {
+		return maps[i].Map < maps[j].Map
+	})
+	return maps
+}
+
+// merge прибавляет счётчики other и пересчитывает проценты
+func (s *OpeningStats) merge(other OpeningStats) {
+	s.Attempts += other.Attempts
+	s.Wins += other.Wins
+	s.FirstDeaths += other.FirstDeaths
+	s.RoundsWonAfterWin += other.RoundsWonAfterWin
+	s.RoundsWonAfterFD += other.RoundsWonAfterFD
+	s.finish()
+}
+
+// mergeOpeningStats складывает первые дуэли по игрокам; порядок — как в buildOpeningStats
+func mergeOpeningStats(old, added []PlayerOpeningStats) []PlayerOpeningStats {
+	byID := make(map[int64]int, len(old))
+	result := make([]PlayerOpeningStats, 0, len(old)+len(added))
+	for _, stats := range old {
+		stats.ByMap = slices.Clone(stats.ByMap)
+		byID[stats.AccountID] = len(result)
+		result = append(result, stats)
+	}
+	for _, stats := range added {
+		i, ok := byID[stats.AccountID]
+		if !ok {
+			byID[stats.AccountID] = len(result)
+			result = append(result, stats)
+			continue
+		}
+		player := &result[i]
+		player.Name = stats.Name
+		player.Total.merge(stats.Total)
+		player.T.merge(stats.T)
+		player.CT.merge(stats.CT)
+		for _, mapStats := range stats.ByMap {
+			j := slices.IndexFunc(player.ByMap, func(m MapOpeningStats) bool { return m.Map == mapStats.Map })
+			if j < 0 {
+				player.ByMap = append(player.ByMap, mapStats)
+				continue
+			}
+			player.ByMap[j].merge(mapStats.OpeningStats)
+		}
+		sort.Slice(player.ByMap, func(a, b int) bool {
+			x, y := player.ByMap[a], player.ByMap[b]
+			if x.Attempts != y.Attempts {
+				return x.Attempts > y.Attempts
+			}
+			return x.Map < y.Map
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total.Attempts != result[j].Total.Attempts {
+			return result[i].Total.Attempts > result[j].Total.Attempts
+		}
+		return result[i].AccountID < result[j].AccountID
+	})
+	return result
+}
+
+// mergePlayerRatings складывает суммы рейтингов по игрокам и пересчитывает средние и проценты.
+// BayesianEPI и порядок проставляет orderByDefaultModel.
+func mergePlayerRatings(old, added []PlayerRating) []PlayerRating {
+	byID := make(map[int64]int, len(old))
+	result := slices.Clone(old)
+	for i, rating := range result {
+		byID[rating.AccountID] = i
+	}
+	for _, rating := range added {
+		i, ok := byID[rating.AccountID]
+		if !ok {
+			byID[rating.AccountID] = len(result)
+			result = append(result, rating)
+			continue
+		}
+		total := &result[i]
+		if rating.Name != fallbackPlayerName(rating.AccountID) {
+			total.Name = rating.Name
+		}
+		tradeTime := total.AvgTradeTime*float64(total.TradesMade) + rating.AvgTradeTime*float64(rating.TradesMade)
+		total.RoundsPlayed += rating.RoundsPlayed
+		total.TotalEPI += rating.TotalEPI
+		total.TotalDamage += rating.TotalDamage
+		total.TotalKills += rating.TotalKills
+		total.TotalDeaths += rating.TotalDeaths
+		total.TotalAssists += rating.TotalAssists
+		total.WinRounds += rating.WinRounds
+		total.LastPlayed = max(total.LastPlayed, rating.LastPlayed)
+		total.TradesMade += rating.TradesMade
+		total.TradedDeaths += rating.TradedDeaths
+		total.UntradedDeaths += rating.UntradedDeaths
+		total.KASTRounds += rating.KASTRounds
+		total.SurvivedRounds += rating.SurvivedRounds
+
+		total.AverageEPI = total.TotalEPI / float64(total.RoundsPlayed)
+		if total.TradesMade > 0 {
+			total.AvgTradeTime = tradeTime / float64(total.TradesMade)
+		}
+		total.KAST = ratio(total.KASTRounds, total.RoundsPlayed) * 100
+		total.SurvivalRate = ratio(total.SurvivedRounds, total.RoundsPlayed) * 100
+		total.DeathsPerRound = ratio(total.TotalDeaths, total.RoundsPlayed)
+	}
+	return result
+}
+
+// orderByDefaultModel проставляет BayesianEPI из модели по умолчанию и сортирует рейтинги в её порядке
+func orderByDefaultModel(ratings []PlayerRating, models []ModelRatings) []PlayerRating {
+	byID := make(map[int64]*PlayerRating, len(ratings))
+	for i := range ratings {
+		byID[ratings[i].AccountID] = &ratings[i]
+	}
+	for _, model := range models {
+		if model.Model != DefaultRatingModel {
+			continue
+		}
+		ordered := make([]PlayerRating, 0, len(ratings))
+		for _, modelRating := range model.Ratings {
+			if rating := byID[modelRating.AccountID]; rating != nil {
+				rating.BayesianEPI = modelRating.Score
+				ordered = append(ordered, *rating)
+			}
+		}
+		return ordered
+	}
+	return ratings
+}
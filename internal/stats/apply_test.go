@@ -0,0 +1,198 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"oldfartscounter/internal/logparser"
+)
+
+// applyTestLog builds a server log with one match per roster. Every round the first CT player
+// nades, flashes and kills a T player with the given weapon, and a T teammate trades him.
+func applyTestLog(maps []string, rosters [][]int, weapons []string) string {
+	var b strings.Builder
+	sec := 0
+	stamp := func() string {
+		sec++
+		return fmt.Sprintf("L 09/05/2025 - %02d:%02d:%02d: ", 18+sec/3600, sec/60%60, sec%60)
+	}
+
+	for m, roster := range rosters {
+		ct, t := roster[:len(roster)/2], roster[len(roster)/2:]
+		ref := func(id int) string {
+			team := "CT"
+			if !containsID(ct, id) {
+				team = "TERRORIST"
+			}
+			return fmt.Sprintf(`"Player %d<%d><[U:1:%d]><%s>"`, id, id, 100000+id, team)
+		}
+
+		b.WriteString(stamp() + `World triggered "Match_Start" on "` + maps[m] + `"` + "\n")
+		for round := 1; round <= 3; round++ {
+			killer, victim, trader := ct[round%len(ct)], t[round%len(t)], t[(round+1)%len(t)]
+			b.WriteString(stamp() + ref(killer) + ` threw hegrenade [-100 200 10]` + "\n")
+			b.WriteString(stamp() + ref(killer) + ` [-100 200 10] attacked ` + ref(victim) +
+				` [50 60 10] with "hegrenade" (damage "40") (damage_armor "3") (health "60") (armor "97") (hitgroup "generic")` + "\n")
+			b.WriteString(stamp() + ref(victim) + ` blinded for 1.30 by ` + ref(killer) + ` from flashbang entindex 276` + "\n")
+			b.WriteString(stamp() + ref(killer) + ` [-100 200 10] attacked ` + ref(victim) +
+				` [50 60 10] with "` + weapons[m] + `" (damage "100") (damage_armor "3") (health "0") (armor "97") (hitgroup "head")` + "\n")
+			b.WriteString(stamp() + ref(killer) + ` [-100 200 10] killed ` + ref(victim) + ` [50 60 10] with "` + weapons[m] + `" (headshot)` + "\n")
+			b.WriteString(stamp() + ref(trader) + ` [0 0 0] killed ` + ref(killer) + ` [10 10 0] with "glock"` + "\n")
+			if round == 2 {
+				b.WriteString(stamp() + ref(trader) + ` triggered "Planted_The_Bomb" at bombsite A` + "\n")
+			}
+
+			b.WriteString(stamp() + "JSON_BEGIN{\n")
+			b.WriteString(stamp() + `"name" : "round_stats",` + "\n")
+			b.WriteString(stamp() + fmt.Sprintf(`"round_number" : "%d",`, round) + "\n")
+			b.WriteString(stamp() + `"score_t" : "0",` + "\n")
+			b.WriteString(stamp() + fmt.Sprintf(`"score_ct" : "%d",`, round) + "\n")
+			b.WriteString(stamp() + `"map" : "` + maps[m] + `",` + "\n")
+			b.WriteString(stamp() + `"server" : "Old Farts",` + "\n")
+			b.WriteString(stamp() + `"fields" : "accountid, team, money, kills, deaths, assists, dmg, hsp, kdr, adr, mvp, ef, ud, 3k, 4k, 5k, clutchk, firstk, pistolk, sniperk, blindk, bombk, firedmg, uniquek, dinks, chickenk",` + "\n")
+			b.WriteString(stamp() + `"players" : {` + "\n")
+			for i, id := range roster {
+				team, kills, deaths, dmg := 2, 0, 0, 0
+				if containsID(ct, id) {
+					team = 3
+				}
+				switch id {
+				case killer:
+					kills, deaths, dmg = 1, 1, 140
+				case victim:
+					deaths = 1
+				case trader:
+					kills, dmg = 1, 100
+				}
+				sep := ","
+				if i == len(roster)-1 {
+					sep = ""
+				}
+				b.WriteString(stamp() + fmt.Sprintf(`"player_%d" : "%d, %d, 3000, %d, %d, 0, %d, 0.00, 0.00, %d, 0, 0, 40, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0"%s`,
+					i, 100000+id, team, kills, deaths, dmg, dmg, sep) + "\n")
+			}
+			b.WriteString(stamp() + "}}\n")
+			b.WriteString(stamp() + "JSON_END\n")
+			b.WriteString(stamp() + `Team "CT" triggered "SFUI_Notice_CTs_Win" (CT "1") (T "0")` + "\n")
+		}
+		b.WriteString(stamp() + "Game Over: competitive " + maps[m] + " score 3:0 after 10 min\n")
+	}
+	return b.String()
+}
+
+// containsID reports whether ids contains id
+func containsID(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// compareStats reports fields that differ between want and got; float sums may differ by rounding
+func compareStats(t *testing.T, path string, want, got reflect.Value) {
+	t.Helper()
+	switch want.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if math.Abs(want.Float()-got.Float()) > 1e-9*math.Max(1, math.Abs(want.Float())) {
+			t.Errorf("%s: want %v, got %v", path, want.Float(), got.Float())
+		}
+	case reflect.Struct:
+		for i := 0; i < want.NumField(); i++ {
+			compareStats(t, path+"."+want.Type().Field(i).Name, want.Field(i), got.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		if want.Len() != got.Len() {
+			t.Errorf("%s: want len %d, got %d", path, want.Len(), got.Len())
+			return
+		}
+		for i := 0; i < want.Len(); i++ {
+			compareStats(t, fmt.Sprintf("%s[%d]", path, i), want.Index(i), got.Index(i))
+		}
+	case reflect.Map:
+		if want.Len() != got.Len() {
+			t.Errorf("%s: want %d keys, got %d", path, want.Len(), got.Len())
+			return
+		}
+		for _, key := range want.MapKeys() {
+			value := got.MapIndex(key)
+			if !value.IsValid() {
+				t.Errorf("%s: missing key %v", path, key)
+				continue
+			}
+			compareStats(t, fmt.Sprintf("%s[%v]", path, key), want.MapIndex(key), value)
+		}
+	case reflect.Pointer:
+		if want.IsNil() != got.IsNil() {
+			t.Errorf("%s: want nil %v, got nil %v", path, want.IsNil(), got.IsNil())
+			return
+		}
+		if !want.IsNil() {
+			compareStats(t, path, want.Elem(), got.Elem())
+		}
+	case reflect.String:
+		if want.String() != got.String() {
+			t.Errorf("%s: want %q, got %q", path, want.String(), got.String())
+		}
+	case reflect.Bool:
+		if want.Bool() != got.Bool() {
+			t.Errorf("%s: want %v, got %v", path, want.Bool(), got.Bool())
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if want.Int() != got.Int() {
+			t.Errorf("%s: want %d, got %d", path, want.Int(), got.Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if want.Uint() != got.Uint() {
+			t.Errorf("%s: want %d, got %d", path, want.Uint(), got.Uint())
+		}
+	default:
+		t.Fatalf("%s: unexpected kind %s", path, want.Kind())
+	}
+}
+
+// TestApply_MatchByMatchEqualsProcess checks that applying matches one at a time gives the same
+// stats as one batch Process, including players and weapons that first appear in later matches
+func TestApply_MatchByMatchEqualsProcess(t *testing.T) {
+	log := applyTestLog(
+		[]string{"de_dust2", "de_mirage", "de_dust2"},
+		[][]int{{101, 102, 201, 202}, {101, 103, 201, 203}, {102, 104, 202, 204, 205, 206}},
+		[]string{"ak47", "awp", "ak47"},
+	)
+
+	const source = "2025_09_05_180000.log"
+	result, err := logparser.New().ParseReader(strings.NewReader(log), source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Matches as follow mode delivers them to OnMatch
+	var matches []*logparser.ParseResult
+	stream := logparser.New().NewStream(source, logparser.NewParseResult(), logparser.StreamHooks{
+		OnMatch: func(match *logparser.ParseResult) { matches = append(matches, match) },
+	})
+	for _, line := range strings.Split(strings.TrimSpace(log), "\n") {
+		stream.Feed(line)
+	}
+	stream.Close()
+	if len(matches) != 3 {
+		t.Fatalf("Expected 3 matches, got %d", len(matches))
+	}
+
+	processor := New()
+	want := processor.Process(result)
+	got := processor.Process(logparser.NewParseResult())
+	for _, match := range matches {
+		processor.Apply(got, match)
+	}
+
+	if want.DateRange != "05-09-2025" || len(want.Players) != 10 || len(want.Weapons) != 3 || len(want.TradeEvents) != 9 || len(want.OpeningDuels) != 9 {
+		t.Fatalf("Unexpected batch stats: period %q, %d players, weapons %v, %d trades, %d duels",
+			want.DateRange, len(want.Players), want.Weapons, len(want.TradeEvents), len(want.OpeningDuels))
+	}
+	compareStats(t, "StatsData", reflect.ValueOf(want).Elem(), reflect.ValueOf(got).Elem())
+}
@@ -0,0 +1,142 @@
+package stats
+
+import (
+	"fmt"
+
+	"oldfartscounter/internal/logparser"
+)
+
+// Типы закупки в раунде
+const (
+	BuyEco   = "eco"   // Эко: почти ничего не куплено
+	BuyForce = "force" // Форс: потрачено почти всё, но на полную закупку не хватило
+	BuyHalf  = "half"  // Полузакупка: купили часть, деньги оставили на следующий раунд
+	BuyFull  = "full"  // Полная закупка
+)
+
+// BuyTypes — типы закупки в порядке отображения
+var BuyTypes = []string{BuyEco, BuyForce, BuyHalf, BuyFull}
+
+// Пороги классификации закупки, в долларах на игрока
+const (
+	EcoMaxValue      = 1500 // Снаряжение дешевле — эко (пистолет и броня)
+	FullBuyMinValue  = 3500 // Снаряжение не дешевле — полная закупка (винтовка и броня)
+	ForceMaxLeftover = 1000 // Неполная закупка с меньшим остатком денег — форс, иначе полузакупка
+)
+
+// ClassifyBuy определяет тип закупки по стоимости снаряжения (траты в раунде + сохранённое оружие)
+// и остатку денег на конец раунда
+func ClassifyBuy(value, leftover float64) string {
+	switch {
+	case value < EcoMaxValue:
+		return BuyEco
+	case value >= FullBuyMinValue:
+		return BuyFull
+	case leftover < ForceMaxLeftover:
+		return BuyForce
+	default:
+		return BuyHalf
+	}
+}
+
+// buildEconomyData классифицирует закупки команд и игроков по раундам и считает
+// винрейты по типам закупки, средние траты и сохранения оружия.
+// Раунды без строк "purchased" (старые логи) пропускаются.
+func (p *Processor) buildEconomyData(rounds []logparser.RoundStats, players []Player, playerIndex map[string]int) EconomyData {
+	buyIndex := indexOf(BuyTypes)
+
+	data := EconomyData{
+		BuyTypes:     BuyTypes,
+		TeamRounds:   make([]int, len(BuyTypes)),
+		TeamWins:     make([]int, len(BuyTypes)),
+		AvgSpend:     make([]float64, len(players)),
+		PlayerRounds: make([][]int, len(players)),
+		PlayerWins:   make([][]int, len(players)),
+		ForceLosses:  make([]int, len(players)),
+		Saves:        make([]int, len(players)),
+		SavedValue:   make([]int, len(players)),
+		spent:        make([]int, len(players)),
+	}
+	for i := range players {
+		data.PlayerRounds[i] = make([]int, len(BuyTypes))
+		data.PlayerWins[i] = make([]int, len(BuyTypes))
+	}
+
+	for _, round := range rounds {
+		if !hasPurchases(round) {
+			continue
+		}
+
+		// Закупка команды — по средним значениям на игрока
+		for _, team := range []int{2, 3} {
+			var value, leftover float64
+			count := 0
+			for _, ps := range round.Players {
+				if ps.Team != team {
+					continue
+				}
+				value += float64(ps.Spent + ps.CarriedValue)
+				leftover += float64(ps.Money)
+				count++
+			}
+			if count == 0 {
+				continue
+			}
+			bIdx := buyIndex[ClassifyBuy(value/float64(count), leftover/float64(count))]
+			data.TeamRounds[bIdx]++
+			if round.Winner == team {
+				data.TeamWins[bIdx]++
+			}
+		}
+
+		// Собственная закупка каждого игрока
+		for _, ps := range round.Players {
+			pIdx, ok := playerIndex[fmt.Sprintf("[U:1:%d]", ps.AccountID)]
+			if !ok {
+				continue
+			}
+			buy := ClassifyBuy(float64(ps.Spent+ps.CarriedValue), float64(ps.Money))
+			won := round.Winner == ps.Team
+
+			data.spent[pIdx] += ps.Spent
+			data.SavedValue[pIdx] += ps.CarriedValue
+			data.PlayerRounds[pIdx][buyIndex[buy]]++
+			if won {
+				data.PlayerWins[pIdx][buyIndex[buy]]++
+			}
+			if buy == BuyForce && !won && round.Winner != 0 {
+				data.ForceLosses[pIdx]++
+			}
+			if ps.Survived && !won && round.Winner != 0 {
+				data.Saves[pIdx]++
+			}
+		}
+	}
+
+	data.finish()
+	return data
+}
+
+// finish считает средние траты игроков: каждый раунд с покупками попадает ровно в один тип закупки PlayerRounds
+func (d *EconomyData) finish() {
+	d.AvgSpend = make([]float64, len(d.spent))
+	for i, spent := range d.spent {
+		rounds := 0
+		for _, n := range d.PlayerRounds[i] {
+			rounds += n
+		}
+		if rounds > 0 {
+			d.AvgSpend[i] = float64(spent) / float64(rounds)
+		}
+	}
+}
+
+// hasPurchases проверяет, есть ли в раунде данные о покупках
+func hasPurchases(round logparser.RoundStats) bool {
+	for _, ps := range round.Players {
+		if ps.Spent > 0 || ps.CarriedValue > 0 {
+			return true
+		}
+	}
+	return false
+}
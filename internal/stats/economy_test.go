@@ -0,0 +1,68 @@
+package stats
+
+import (
+	"testing"
+
+	"oldfartscounter/internal/logparser"
+)
+
+// TestClassifyBuy tests buy type thresholds
+func TestClassifyBuy(t *testing.T) {
+	tests := []struct {
+		value, leftover float64
+		want            string
+	}{
+		{200, 3000, BuyEco},
+		{1499, 0, BuyEco},
+		{2000, 300, BuyForce},
+		{2000, 2500, BuyHalf},
+		{3500, 0, BuyFull},
+		{4750, 5000, BuyFull},
+	}
+	for _, tt := range tests {
+		if got := ClassifyBuy(tt.value, tt.leftover); got != tt.want {
+			t.Errorf("ClassifyBuy(%v, %v) = %s, want %s", tt.value, tt.leftover, got, tt.want)
+		}
+	}
+}
+
+// TestBuildEconomyData_WinRatesAndForceLosses tests team buy win rates and player force losses
+func TestBuildEconomyData_WinRatesAndForceLosses(t *testing.T) {
+	processor := New()
+
+	players := []Player{{Key: "[U:1:1]", Title: "Alice"}, {Key: "[U:1:2]", Title: "Bob"}}
+	playerIndex := map[string]int{"[U:1:1]": 0, "[U:1:2]": 1}
+
+	rounds := []logparser.RoundStats{
+		// T full buy beats CT force buy
+		{Winner: 2, Players: []logparser.PlayerStats{
+			{AccountID: 1, Team: 2, Spent: 3700, Money: 1500, Survived: true},
+			{AccountID: 2, Team: 3, Spent: 2000, Money: 100},
+		}},
+		// CT saves: Bob survives a lost eco round with his carried rifle
+		{Winner: 2, Players: []logparser.PlayerStats{
+			{AccountID: 1, Team: 2, Spent: 300, CarriedValue: 2700, Money: 4000, Survived: true},
+			{AccountID: 2, Team: 3, Spent: 200, Money: 2000, Survived: true},
+		}},
+		// Old log without purchases is skipped
+		{Winner: 3, Players: []logparser.PlayerStats{
+			{AccountID: 1, Team: 2}, {AccountID: 2, Team: 3},
+		}},
+	}
+
+	data := processor.buildEconomyData(rounds, players, playerIndex)
+
+	// eco, force, half, full
+	if data.TeamRounds[3] != 1 || data.TeamWins[3] != 1 || data.TeamRounds[1] != 1 || data.TeamWins[1] != 0 {
+		t.Errorf("Unexpected team rounds/wins: %v / %v", data.TeamRounds, data.TeamWins)
+	}
+	if data.TeamRounds[0] != 1 {
+		t.Errorf("Expected one eco round, got %v", data.TeamRounds)
+	}
+	if data.AvgSpend[0] != 2000 || data.SavedValue[0] != 2700 {
+		t.Errorf("Unexpected Alice economy: spend %v, saved %v", data.AvgSpend[0], data.SavedValue[0])
+	}
+	if data.ForceLosses[1] != 1 || data.Saves[1] != 1 {
+		t.Errorf("Unexpected Bob economy: force losses %d, saves %d", data.ForceLosses[1], data.Saves[1])
+	}
+}
@@ -0,0 +1,53 @@
+package stats
+
+import (
+	"oldfartscounter/internal/logparser"
+)
+
+// applyKASTStats дописывает в рейтинги KAST, выживаемость и смерти за раунд.
+// Раунды те же, что у buildPlayerRatings, поэтому проценты считаются от RoundsPlayed.
+func applyKASTStats(ratings []PlayerRating, roundStats []logparser.RoundStats, trades []TradeEvent) {
+	kastRounds, survived := countKASTRounds(roundStats, trades)
+	for i := range ratings {
+		rating := &ratings[i]
+		rating.KASTRounds = kastRounds[rating.AccountID]
+		rating.SurvivedRounds = survived[rating.AccountID]
+		rating.KAST = ratio(rating.KASTRounds, rating.RoundsPlayed) * 100
+		rating.SurvivalRate = ratio(rating.SurvivedRounds, rating.RoundsPlayed) * 100
+		rating.DeathsPerRound = ratio(rating.TotalDeaths, rating.RoundsPlayed)
+	}
+}
+
+// countKASTRounds считает по игрокам раунды KAST и раунды, до конца которых игрок дожил.
+// Раунд засчитывается в KAST, если игрок в нём убил, сделал ассист, выжил или его смерть разменяли.
+// Разменянные смерти привязываются к раунду по MatchID и номеру раунда (TradeEvent.Round = RoundStats.RoundNumber).
+func countKASTRounds(roundStats []logparser.RoundStats, trades []TradeEvent) (kastRounds, survived map[int64]int) {
+	type roundDeath struct {
+		matchID   string
+		round     int
+		accountID int64
+	}
+	traded := make(map[roundDeath]bool, len(trades))
+	for _, trade := range trades {
+		if id, ok := logparser.AccountIDFromSID(trade.VictimSID); ok {
+			traded[roundDeath{trade.MatchID, trade.Round, id}] = true
+		}
+	}
+
+	kastRounds = make(map[int64]int)
+	survived = make(map[int64]int)
+	for _, round := range roundStats {
+		for _, ps := range round.Players {
+			if ps.AccountID == 0 {
+				continue
+			}
+			if ps.Survived {
+				survived[ps.AccountID]++
+			}
+			if ps.Kills > 0 || ps.Assists > 0 || ps.Survived || traded[roundDeath{round.MatchID, round.RoundNumber, ps.AccountID}] {
+				kastRounds[ps.AccountID]++
+			}
+		}
+	}
+	return kastRounds, survived
+}
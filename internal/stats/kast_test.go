@@ -0,0 +1,39 @@
+package stats
+
+import (
+	"testing"
+
+	"oldfartscounter/internal/logparser"
+)
+
+// TestApplyKASTStats checks each KAST condition and that a trade only counts for its own round
+func TestApplyKASTStats(t *testing.T) {
+	const alice = 100001
+	round := func(number int, ps logparser.PlayerStats) logparser.RoundStats {
+		ps.AccountID, ps.Team = alice, 3
+		return logparser.RoundStats{MatchID: "m1", RoundNumber: number, Players: []logparser.PlayerStats{ps}}
+	}
+	rounds := []logparser.RoundStats{
+		round(1, logparser.PlayerStats{Kills: 1, Deaths: 1}),   // kill
+		round(2, logparser.PlayerStats{Assists: 1, Deaths: 1}), // assist
+		round(3, logparser.PlayerStats{Survived: true}),        // survived
+		round(4, logparser.PlayerStats{Deaths: 1}),             // traded
+		round(5, logparser.PlayerStats{Deaths: 1}),             // nothing
+	}
+	trades := []TradeEvent{
+		{VictimSID: "[U:1:100001]", MatchID: "m1", Round: 4},
+		// Another match's round 5 does not make Alice's round 5 traded
+		{VictimSID: "[U:1:100001]", MatchID: "m2", Round: 5},
+	}
+
+	ratings := []PlayerRating{{AccountID: alice, RoundsPlayed: 5, TotalDeaths: 4}}
+	applyKASTStats(ratings, rounds, trades)
+
+	r := ratings[0]
+	if r.KASTRounds != 4 || r.KAST != 80 {
+		t.Errorf("Expected KAST 4/5 = 80%%, got %d rounds, %.1f%%", r.KASTRounds, r.KAST)
+	}
+	if r.SurvivedRounds != 1 || r.SurvivalRate != 20 || r.DeathsPerRound != 0.8 {
+		t.Errorf("Unexpected survival stats: %+v", r)
+	}
+}
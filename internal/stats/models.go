@@ -0,0 +1,247 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"oldfartscounter/internal/logparser"
+)
+
+// DefaultRatingModel — модель рейтинга по умолчанию: EPI раунда со сглаживанием по Байесу
+const DefaultRatingModel = "epi"
+
+// bayesianK — сколько раундов нужно для "достоверного" рейтинга EPI (K в байесовской формуле)
+const bayesianK = 100.0
+
+// RatingInput — данные, по которым модель считает рейтинги
+type RatingInput struct {
+	Rounds []logparser.RoundStats // Раунды с PlayerStats; EPI раунда уже посчитан парсером
+	Kills  []logparser.KillEvent  // Убийства в порядке лога
+	Trades []TradeEvent           // Размены (см. detectTrades)
+}
+
+// ModelRating — рейтинг игрока в одной модели
+type ModelRating struct {
+	AccountID int64
+	Name      string
+	Rounds    int     // Сыгранные раунды
+	Score     float64 // Рейтинг в шкале модели
+}
+
+// ModelRatings — результат модели: рейтинги игроков по убыванию Score и метаданные модели
+type ModelRatings struct {
+	Model       string        // Имя модели (RatingModel.Name)
+	Title       string        // Название для интерфейса
+	Description string        // Как считается рейтинг
+	Mean        float64       // Средний рейтинг по раундам пула — от него считаются категории, как от μ у EPI
+	Ratings     []ModelRating // По убыванию Score
+}
+
+// RatingModel — модель рейтинга игроков. Модели регистрируются RegisterRatingModel и выбираются по имени.
+type RatingModel interface {
+	Name() string
+	Rate(input RatingInput) ModelRatings
+}
+
+// ratingModels — зарегистрированные модели; первой идёт модель по умолчанию
+var (
+	ratingModelsMu sync.RWMutex
+	ratingModels   = []RatingModel{epiModel{}, adrKDModel{}, hltv2Model{}}
+)
+
+// RegisterRatingModel добавляет модель рейтинга: Process считает её вместе со встроенными,
+// SetRatingModel и team builder выбирают её по имени, таб рейтингов показывает в списке моделей.
+// Имя модели должно быть непустым и уникальным.
+func RegisterRatingModel(model RatingModel) error {
+	if model == nil || model.Name() == "" {
+		return errors.New("у модели рейтинга нет имени")
+	}
+	ratingModelsMu.Lock()
+	defer ratingModelsMu.Unlock()
+	for _, registered := range ratingModels {
+		if registered.Name() == model.Name() {
+			return fmt.Errorf("модель рейтинга %q уже зарегистрирована", model.Name())
+		}
+	}
+	ratingModels = append(ratingModels, model)
+	return nil
+}
+
+// RatingModels возвращает все модели рейтинга в порядке регистрации
+func RatingModels() []RatingModel {
+	ratingModelsMu.RLock()
+	defer ratingModelsMu.RUnlock()
+	return slices.Clone(ratingModels)
+}
+
+// RatingModelByName ищет модель рейтинга по имени
+func RatingModelByName(name string) (RatingModel, error) {
+	models := RatingModels()
+	names := make([]string, 0, len(models))
+	for _, model := range models {
+		if model.Name() == name {
+			return model, nil
+		}
+		names = append(names, model.Name())
+	}
+	return nil, fmt.Errorf("неизвестная модель рейтинга %q (доступны: %s)", name, strings.Join(names, ", "))
+}
+
+// playerTotals — суммы PlayerStats игрока по раундам
+type playerTotals struct {
+	rounds  int
+	kills   int
+	deaths  int
+	assists int
+	damage  int
+	epi     float64
+}
+
+// sumPlayerStats суммирует PlayerStats по игрокам; боты (AccountID 0) пропускаются
+func sumPlayerStats(rounds []logparser.RoundStats) map[int64]*playerTotals {
+	totals := make(map[int64]*playerTotals)
+	for _, round := range rounds {
+		for _, ps := range round.Players {
+			if ps.AccountID == 0 {
+				continue
+			}
+			t := totals[ps.AccountID]
+			if t == nil {
+				t = &playerTotals{}
+				totals[ps.AccountID] = t
+			}
+			t.rounds++
+			t.kills += ps.Kills
+			t.deaths += ps.Deaths
+			t.assists += ps.Assists
+			t.damage += ps.Damage
+			t.epi += ps.Rating
+		}
+	}
+	return totals
+}
+
+// rateAll собирает ModelRatings из рейтингов игроков: сортирует по убыванию Score
+// и считает Mean как среднее по раундам
+func rateAll(result ModelRatings, totals map[int64]*playerTotals, score func(t *playerTotals) float64) ModelRatings {
+	var weighted float64
+	var rounds int
+	result.Ratings = make([]ModelRating, 0, len(totals))
+	for accountID, t := range totals {
+		s := score(t)
+		result.Ratings = append(result.Ratings, ModelRating{AccountID: accountID, Rounds: t.rounds, Score: s})
+		weighted += s * float64(t.rounds)
+		rounds += t.rounds
+	}
+	if rounds > 0 {
+		result.Mean = weighted / float64(rounds)
+	}
+	sort.Slice(result.Ratings, func(i, j int) bool {
+		if result.Ratings[i].Score != result.Ratings[j].Score {
+			return result.Ratings[i].Score > result.Ratings[j].Score
+		}
+		return result.Ratings[i].AccountID < result.Ratings[j].AccountID
+	})
+	return result
+}
+
+// bayesianEPI сглаживает EPI игрока к среднему μ: (ΣEPI + K×μ) / (N + K)
+func bayesianEPI(totalEPI float64, rounds int, mu float64) float64 {
+	return (totalEPI + bayesianK*mu) / (float64(rounds) + bayesianK)
+}
+
+// epiModel — модель по умолчанию: EPI раунда (logparser.calculateRoundRatings) со сглаживанием по Байесу.
+// buildPlayerRatings берёт из неё PlayerRating.BayesianEPI и порядок игроков, Process — μ (AverageMu).
+type epiModel struct{}
+
+func (epiModel) Name() string { return "epi" }
+
+func (epiModel) Rate(input RatingInput) ModelRatings {
+	totals := sumPlayerStats(input.Rounds)
+	var totalEPI float64
+	var totalRounds int
+	for _, t := range totals {
+		totalEPI += t.epi
+		totalRounds += t.rounds
+	}
+	mu := 0.6 // Дефолтное значение, если нет данных
+	if totalRounds > 0 {
+		mu = totalEPI / float64(totalRounds)
+	}
+
+	result := rateAll(ModelRatings{
+		Model:       "epi",
+		Title:       "EPI + Байес",
+		Description: "Средний EPI раунда, сглаженный к среднему μ: (ΣEPI + K×μ) / (N + K), K = 100",
+	}, totals, func(t *playerTotals) float64 {
+		return bayesianEPI(t.epi, t.rounds, mu)
+	})
+	// Категории EPI считаются от μ, а не от среднего сглаженного рейтинга
+	result.Mean = mu
+	return result
+}
+
+// adrKDModel — ADR и K/D игрока относительно среднего по пулу, поровну
+type adrKDModel struct{}
+
+func (adrKDModel) Name() string { return "adrkd" }
+
+func (adrKDModel) Rate(input RatingInput) ModelRatings {
+	totals := sumPlayerStats(input.Rounds)
+	var damage, kills, deaths, rounds int
+	for _, t := range totals {
+		damage += t.damage
+		kills += t.kills
+		deaths += t.deaths
+		rounds += t.rounds
+	}
+	poolADR := ratio(damage, rounds)
+	poolKD := ratio(kills, max(deaths, 1))
+
+	return rateAll(ModelRatings{
+		Model:       "adrkd",
+		Title:       "ADR + K/D",
+		Description: "Среднее из ADR / ADR пула и K/D / K/D пула: 1.0 — средний игрок",
+	}, totals, func(t *playerTotals) float64 {
+		var score float64
+		if poolADR > 0 {
+			score += ratio(t.damage, t.rounds) / poolADR
+		}
+		if poolKD > 0 {
+			score += ratio(t.kills, max(t.deaths, 1)) / poolKD
+		}
+		return score / 2
+	})
+}
+
+// hltv2Model — приближение HLTV Rating 2.0 по KAST, KPR, DPR, Impact и ADR (коэффициенты из публичной регрессии)
+type hltv2Model struct{}
+
+func (hltv2Model) Name() string { return "hltv2" }
+
+func (hltv2Model) Rate(input RatingInput) ModelRatings {
+	totals := sumPlayerStats(input.Rounds)
+	kastRounds, _ := countKASTRounds(input.Rounds, input.Trades)
+	kast := make(map[*playerTotals]float64, len(totals))
+	for accountID, t := range totals {
+		kast[t] = ratio(kastRounds[accountID], t.rounds) * 100
+	}
+
+	return rateAll(ModelRatings{
+		Model:       "hltv2",
+		Title:       "HLTV 2.0 (приближение)",
+		Description: "0.0073×KAST + 0.3591×KPR − 0.5329×DPR + 0.2372×Impact + 0.0032×ADR + 0.1587, Impact = 2.13×KPR + 0.42×APR − 0.41",
+	}, totals, func(t *playerTotals) float64 {
+		return hltv2Rating(kast[t], ratio(t.kills, t.rounds), ratio(t.deaths, t.rounds), ratio(t.assists, t.rounds), ratio(t.damage, t.rounds))
+	})
+}
+
+// hltv2Rating — формула приближения HLTV 2.0; kast в процентах, остальное — за раунд
+func hltv2Rating(kast, kpr, dpr, apr, adr float64) float64 {
+	impact := 2.13*kpr + 0.42*apr - 0.41
+	return 0.0073*kast + 0.3591*kpr - 0.5329*dpr + 0.2372*impact + 0.0032*adr + 0.1587
+}
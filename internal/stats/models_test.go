@@ -0,0 +1,111 @@
+package stats
+
+import (
+	"math"
+	"testing"
+
+	"oldfartscounter/internal/logparser"
+)
+
+// TestRatingModels checks each model on two players and that the EPI model matches PlayerRating.BayesianEPI
+func TestRatingModels(t *testing.T) {
+	const alice, bob = 100001, 100002
+	var rounds []logparser.RoundStats
+	for i := 1; i <= 4; i++ {
+		rounds = append(rounds, logparser.RoundStats{MatchID: "m1", RoundNumber: i, Players: []logparser.PlayerStats{
+			// Alice: 1 kill, 100 damage, survives every round
+			{AccountID: alice, Team: 3, Kills: 1, Damage: 100, Rating: 1.2, Survived: true},
+			// Bob: dies every round, assists in every other one
+			{AccountID: bob, Team: 2, Deaths: 1, Assists: i % 2, Damage: 20, Rating: 0.2},
+		}})
+	}
+	input := RatingInput{Rounds: rounds}
+	scores := func(name string) (map[int64]float64, ModelRatings) {
+		model, err := RatingModelByName(name)
+		if err != nil {
+			t.Fatalf("RatingModelByName(%q): %v", name, err)
+		}
+		result := model.Rate(input)
+		if result.Model != name || len(result.Ratings) != 2 || result.Ratings[0].AccountID != alice || result.Ratings[0].Rounds != 4 {
+			t.Fatalf("Unexpected %s ratings: %+v", name, result)
+		}
+		byID := make(map[int64]float64)
+		for _, r := range result.Ratings {
+			byID[r.AccountID] = r.Score
+		}
+		return byID, result
+	}
+
+	epi, result := scores("epi")
+	for _, rating := range New().buildPlayerRatings(rounds, nil, nil, nil) {
+		if math.Abs(epi[rating.AccountID]-rating.BayesianEPI) > 1e-9 {
+			t.Errorf("EPI model %.4f != BayesianEPI %.4f for %d", epi[rating.AccountID], rating.BayesianEPI, rating.AccountID)
+		}
+	}
+	if math.Abs(result.Mean-0.7) > 1e-9 {
+		t.Errorf("Expected EPI mean μ = 0.7, got %.4f", result.Mean)
+	}
+
+	// Pool ADR is 60 and pool K/D is 4/4: Alice (4 kills, no deaths) = (100/60 + 4) / 2, Bob = (20/60 + 0) / 2
+	adrKD, _ := scores("adrkd")
+	if math.Abs(adrKD[alice]-(100.0/60+4)/2) > 1e-9 || math.Abs(adrKD[bob]-20.0/60/2) > 1e-9 {
+		t.Errorf("Unexpected ADR/KD scores: %v", adrKD)
+	}
+
+	// Alice: KAST 100%, KPR 1; Bob: KAST 50% (assists only), DPR 1, APR 0.5
+	hltv, _ := scores("hltv2")
+	if math.Abs(hltv[alice]-hltv2Rating(100, 1, 0, 0, 100)) > 1e-9 || math.Abs(hltv[bob]-hltv2Rating(50, 0, 1, 0.5, 20)) > 1e-9 {
+		t.Errorf("Unexpected HLTV 2.0 scores: %v", hltv)
+	}
+
+	if _, err := RatingModelByName("elo"); err == nil {
+		t.Error("Expected an error for an unknown model")
+	}
+	if err := New().SetRatingModel("elo"); err == nil {
+		t.Error("Expected SetRatingModel to reject an unknown model")
+	}
+}
+
+// constModel rates every player with the same score
+type constModel struct{ score float64 }
+
+func (constModel) Name() string { return "const" }
+
+func (m constModel) Rate(input RatingInput) ModelRatings {
+	return rateAll(ModelRatings{Model: "const", Title: "Const"}, sumPlayerStats(input.Rounds), func(*playerTotals) float64 {
+		return m.score
+	})
+}
+
+// TestRegisterRatingModel checks that a registered model is selectable by name and rated by Process
+func TestRegisterRatingModel(t *testing.T) {
+	registered := RatingModels()
+	t.Cleanup(func() {
+		ratingModels = registered
+	})
+
+	if err := RegisterRatingModel(constModel{score: 1.5}); err != nil {
+		t.Fatalf("RegisterRatingModel: %v", err)
+	}
+	if err := RegisterRatingModel(constModel{}); err == nil {
+		t.Error("Expected an error for a duplicate model name")
+	}
+	if err := RegisterRatingModel(epiModel{}); err == nil {
+		t.Error("Expected an error for re-registering a built-in model")
+	}
+
+	processor := New()
+	if err := processor.SetRatingModel("const"); err != nil {
+		t.Fatalf("SetRatingModel: %v", err)
+	}
+	data := processor.Process(&logparser.ParseResult{RoundStats: []logparser.RoundStats{
+		{MatchID: "m1", RoundNumber: 1, Players: []logparser.PlayerStats{{AccountID: 100001, Team: 3, Damage: 100, Rating: 1.2}}},
+	}})
+	last := data.ModelRatings[len(data.ModelRatings)-1]
+	if len(data.ModelRatings) != len(registered)+1 || last.Model != "const" || len(last.Ratings) != 1 || last.Ratings[0].Score != 1.5 {
+		t.Errorf("Expected the registered model in ModelRatings, got %+v", data.ModelRatings)
+	}
+	if data.AverageMu != data.ModelRatings[0].Mean || data.PlayerRatings[0].BayesianEPI != data.ModelRatings[0].Ratings[0].Score {
+		t.Errorf("Expected μ and BayesianEPI from the default model, got μ %v and %+v", data.AverageMu, data.PlayerRatings)
+	}
+}
@@ -0,0 +1,212 @@
+package stats
+
+import (
+	"sort"
+
+	"oldfartscounter/internal/logparser"
+)
+
+// OpeningDuel — первая дуэль раунда: первое убийство (не тимкилл) и исход раунда
+type OpeningDuel struct {
+	KillerName  string // Кто открыл раунд
+	KillerSID   string
+	VictimName  string // Кто умер первым
+	VictimSID   string
+	KillerSide  string // Сторона открывшего: T или CT
+	KillerWon   bool   // Команда открывшего выиграла раунд
+	Map         string // Карта раунда
+	Round       int    // Номер раунда (как у KillEvent)
+	MatchID     string // ID матча
+	Date        string // Дата в формате YYYY-MM-DD
+	RoundWinner int    // Победитель раунда: 2=T, 3=CT, 0=неизвестно/ничья
+}
+
+// OpeningStats — первые дуэли игрока в целом, на стороне или на карте
+type OpeningStats struct {
+	Attempts          int     // Участвовал в первой дуэли раунда
+	Wins              int     // Сделал первое убийство
+	FirstDeaths       int     // Умер первым
+	SuccessRate       float64 // Wins / Attempts, %
+	RoundsWonAfterWin int     // Раунды, выигранные после своего первого убийства
+	RoundsWonAfterFD  int     // Раунды, выигранные после своей первой смерти
+	WinRateAfterWin   float64 // RoundsWonAfterWin / Wins, %
+	WinRateAfterFD    float64 // RoundsWonAfterFD / FirstDeaths, %
+}
+
+// MapOpeningStats — первые дуэли игрока на одной карте
+type MapOpeningStats struct {
+	Map string
+	OpeningStats
+}
+
+// PlayerOpeningStats — первые дуэли игрока: всего, по сторонам и по картам
+type PlayerOpeningStats struct {
+	AccountID int64
+	Name      string
+	Total     OpeningStats
+	T         OpeningStats
+	CT        OpeningStats
+	ByMap     []MapOpeningStats // По убыванию числа дуэлей
+}
+
+// add учитывает дуэль: won — игрок сделал первое убийство, roundWon — его команда выиграла раунд
+func (s *OpeningStats) add(won, roundWon bool) {
+	s.Attempts++
+	if won {
+		s.Wins++
+		if roundWon {
+			s.RoundsWonAfterWin++
+		}
+		return
+	}
+	s.FirstDeaths++
+	if roundWon {
+		s.RoundsWonAfterFD++
+	}
+}
+
+// finish считает проценты
+func (s *OpeningStats) finish() {
+	s.SuccessRate = ratio(s.Wins, s.Attempts) * 100
+	s.WinRateAfterWin = ratio(s.RoundsWonAfterWin, s.Wins) * 100
+	s.WinRateAfterFD = ratio(s.RoundsWonAfterFD, s.FirstDeaths) * 100
+}
+
+// findOpeningDuels находит первое убийство каждого раунда из roundStats. Убийства одного раунда идут
+// подряд в порядке лога; раунды без JSON блока (и без победителя) пропускаются.
+func findOpeningDuels(roundStats []logparser.RoundStats, kills []logparser.KillEvent) []OpeningDuel {
+	type roundKey struct {
+		matchID string
+		round   int
+	}
+	rounds := make(map[roundKey]*logparser.RoundStats, len(roundStats))
+	for i := range roundStats {
+		rounds[roundKey{roundStats[i].MatchID, roundStats[i].RoundNumber}] = &roundStats[i]
+	}
+
+	var duels []OpeningDuel
+	seen := make(map[roundKey]bool)
+	for _, kill := range kills {
+		key := roundKey{kill.MatchID, kill.Round}
+		if seen[key] || kill.KillerTeam == kill.VictimTeam {
+			continue
+		}
+		round := rounds[key]
+		side := sideName(kill.KillerTeam)
+		if round == nil || side == "" {
+			continue
+		}
+		seen[key] = true
+
+		mapName := round.Map
+		if mapName == "" {
+			mapName = kill.Map
+		}
+		duels = append(duels, OpeningDuel{
+			KillerName:  kill.KillerName,
+			KillerSID:   kill.KillerSID,
+			VictimName:  kill.VictimName,
+			VictimSID:   kill.VictimSID,
+			KillerSide:  side,
+			KillerWon:   round.Winner == teamNumber(side),
+			Map:         mapName,
+			Round:       kill.Round,
+			MatchID:     kill.MatchID,
+			Date:        kill.Date,
+			RoundWinner: round.Winner,
+		})
+	}
+	return duels
+}
+
+// sideName переводит команду из лога в сторону: TERRORIST → T, CT → CT
+func sideName(team string) string {
+	switch team {
+	case "TERRORIST":
+		return "T"
+	case "CT":
+		return "CT"
+	}
+	return ""
+}
+
+// teamNumber переводит сторону в номер команды из JSON блока: T → 2, CT → 3
+func teamNumber(side string) int {
+	switch side {
+	case "T":
+		return 2
+	case "CT":
+		return 3
+	}
+	return 0
+}
+
+// buildOpeningStats агрегирует первые дуэли по игрокам, сторонам и картам
+func (p *Processor) buildOpeningStats(duels []OpeningDuel) []PlayerOpeningStats {
+	type playerDuels struct {
+		stats PlayerOpeningStats
+		maps  map[string]*OpeningStats
+	}
+	players := make(map[int64]*playerDuels)
+	add := func(name, sid, side, mapName string, won, roundWon bool) {
+		accountID, ok := logparser.AccountIDFromSID(sid)
+		if !ok {
+			return
+		}
+		player := players[accountID]
+		if player == nil {
+			player = &playerDuels{stats: PlayerOpeningStats{AccountID: accountID}, maps: make(map[string]*OpeningStats)}
+			players[accountID] = player
+		}
+		player.stats.Name = name
+
+		player.stats.Total.add(won, roundWon)
+		if side == "T" {
+			player.stats.T.add(won, roundWon)
+		} else {
+			player.stats.CT.add(won, roundWon)
+		}
+		if mapName != "" {
+			if player.maps[mapName] == nil {
+				player.maps[mapName] = &OpeningStats{}
+			}
+			player.maps[mapName].add(won, roundWon)
+		}
+	}
+
+	for _, duel := range duels {
+		victimSide := "T"
+		if duel.KillerSide == "T" {
+			victimSide = "CT"
+		}
+		add(duel.KillerName, duel.KillerSID, duel.KillerSide, duel.Map, true, duel.KillerWon)
+		add(duel.VictimName, duel.VictimSID, victimSide, duel.Map, false, duel.RoundWinner == teamNumber(victimSide))
+	}
+
+	result := make([]PlayerOpeningStats, 0, len(players))
+	for _, player := range players {
+		player.stats.Total.finish()
+		player.stats.T.finish()
+		player.stats.CT.finish()
+		for mapName, stats := range player.maps {
+			stats.finish()
+			player.stats.ByMap = append(player.stats.ByMap, MapOpeningStats{Map: mapName, OpeningStats: *stats})
+		}
+		sort.Slice(player.stats.ByMap, func(i, j int) bool {
+			a, b := player.stats.ByMap[i], player.stats.ByMap[j]
+			if a.Attempts != b.Attempts {
+				return a.Attempts > b.Attempts
+			}
+			return a.Map < b.Map
+		})
+		result = append(result, player.stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total.Attempts != result[j].Total.Attempts {
+			return result[i].Total.Attempts > result[j].Total.Attempts
+		}
+		return result[i].AccountID < result[j].AccountID
+	})
+	return result
+}
@@ -0,0 +1,58 @@
+package stats
+
+import (
+	"testing"
+
+	"oldfartscounter/internal/logparser"
+)
+
+// TestOpeningDuels checks that only the first kill of a known round counts and how outcomes are split by side and map
+func TestOpeningDuels(t *testing.T) {
+	const alice, bob = "[U:1:100001]", "[U:1:100002]"
+	kill := func(round int, killer, killerTeam, victim, victimTeam string) logparser.KillEvent {
+		return logparser.KillEvent{KillerName: killer, KillerSID: killer, KillerTeam: killerTeam, VictimName: victim, VictimSID: victim, VictimTeam: victimTeam, MatchID: "m1", Round: round}
+	}
+	rounds := []logparser.RoundStats{
+		{MatchID: "m1", RoundNumber: 1, Map: "de_dust2", Winner: 3},
+		{MatchID: "m1", RoundNumber: 2, Map: "de_dust2", Winner: 3},
+		{MatchID: "m1", RoundNumber: 3, Map: "de_dust2", Winner: 2},
+	}
+	kills := []logparser.KillEvent{
+		// Round 1: Alice (CT) opens and CT wins; Bob's revenge kill is not an opener
+		kill(1, alice, "CT", bob, "TERRORIST"),
+		kill(1, bob, "TERRORIST", alice, "CT"),
+		// Round 2: Bob (T) opens, but CT still wins
+		kill(2, bob, "TERRORIST", alice, "CT"),
+		// Round 3: Alice (CT) opens, T wins
+		kill(3, alice, "CT", bob, "TERRORIST"),
+		// Round 4 has no JSON block and is skipped
+		kill(4, bob, "TERRORIST", alice, "CT"),
+	}
+
+	duels := findOpeningDuels(rounds, kills)
+	if len(duels) != 3 || duels[0].KillerSID != alice || !duels[0].KillerWon || duels[1].KillerSID != bob || duels[1].KillerWon {
+		t.Fatalf("Unexpected opening duels: %+v", duels)
+	}
+
+	stats := New().buildOpeningStats(duels)
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 players, got %+v", stats)
+	}
+	byID := map[int64]PlayerOpeningStats{stats[0].AccountID: stats[0], stats[1].AccountID: stats[1]}
+
+	a := byID[100001]
+	if a.Total.Attempts != 3 || a.Total.Wins != 2 || a.Total.FirstDeaths != 1 || a.CT.Attempts != 3 || a.T.Attempts != 0 {
+		t.Errorf("Unexpected Alice totals: %+v", a)
+	}
+	if a.Total.RoundsWonAfterWin != 1 || a.Total.WinRateAfterWin != 50 || a.Total.RoundsWonAfterFD != 1 || a.Total.WinRateAfterFD != 100 {
+		t.Errorf("Unexpected Alice round outcomes: %+v", a.Total)
+	}
+	if len(a.ByMap) != 1 || a.ByMap[0].Map != "de_dust2" || a.ByMap[0].Attempts != 3 {
+		t.Errorf("Unexpected Alice maps: %+v", a.ByMap)
+	}
+
+	b := byID[100002]
+	if b.T.Wins != 1 || b.T.FirstDeaths != 2 || b.T.RoundsWonAfterWin != 0 || b.T.RoundsWonAfterFD != 1 {
+		t.Errorf("Unexpected Bob T side: %+v", b.T)
+	}
+}
@@ -2,18 +2,75 @@ package stats
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"oldfartscounter/internal/logparser"
 )
 
 // Processor обрабатывает данные парсинга и создает статистику
-type Processor struct{}
+type Processor struct {
+	countTeamKills bool          // считать тимкиллы обычными убийствами
+	tradeWindow    time.Duration // окно размена после смерти союзника
+	ratingModel    string        // модель рейтинга, выбранная по умолчанию (см. RatingModels)
+}
 
 // New создает новый процессор статистики
 func New() *Processor {
-	return &Processor{}
+	return &Processor{tradeWindow: DefaultTradeWindow, ratingModel: DefaultRatingModel}
+}
+
+// SetRatingModel выбирает модель рейтинга по имени (StatsData.RatingModel). Рейтинги всех моделей
+// считаются всегда, выбранная только показывается первой. По умолчанию DefaultRatingModel.
+func (p *Processor) SetRatingModel(name string) error {
+	if _, err := RatingModelByName(name); err != nil {
+		return err
+	}
+	p.ratingModel = name
+	return nil
+}
+
+// SetTradeWindow задаёт, за сколько после смерти союзника нужно убить его убийцу, чтобы засчитать размен.
+// По умолчанию DefaultTradeWindow.
+func (p *Processor) SetTradeWindow(window time.Duration) {
+	p.tradeWindow = window
+}
+
+// SetCountTeamKills включает учёт тимкиллов как обычных убийств: в матрице убийств, оружии и рейтингах.
+// По умолчанию тимкиллы, как и остальные позорные смерти, видны только в ShameData.
+func (p *Processor) SetCountTeamKills(count bool) {
+	p.countTeamKills = count
+}
+
+// killEvents возвращает убийства для статистики: KillEvents и, с SetCountTeamKills(true), тимкиллы
+func (p *Processor) killEvents(parseResult *logparser.ParseResult) []logparser.KillEvent {
+	if !p.countTeamKills {
+		return parseResult.KillEvents
+	}
+	kills := make([]logparser.KillEvent, len(parseResult.KillEvents), len(parseResult.KillEvents)+len(parseResult.ShameEvents))
+	copy(kills, parseResult.KillEvents)
+	for _, e := range parseResult.ShameEvents {
+		if e.Kind != logparser.ShameTeamKill {
+			continue
+		}
+		kills = append(kills, logparser.KillEvent{
+			KillerName: e.PlayerName,
+			KillerSID:  e.PlayerSID,
+			KillerTeam: e.PlayerTeam,
+			VictimName: e.VictimName,
+			VictimSID:  e.VictimSID,
+			VictimTeam: e.PlayerTeam,
+			Weapon:     e.Weapon,
+			Date:       e.Date,
+			Time:       e.Time,
+			MatchID:    e.MatchID,
+			Round:      e.Round,
+			Map:        e.Map,
+		})
+	}
+	return kills
 }
 
 // isValidSteamID проверяет, является ли SteamID валидным.
@@ -33,10 +90,11 @@ func isValidSteamID(sid string) bool {
 func (p *Processor) Process(parseResult *logparser.ParseResult) *StatsData {
 	// Заполняем игроков
 	players := make(map[string]Player)
+	killEvents := p.killEvents(parseResult)
 
 	// Сортируем события убийств по дате, чтобы получить самые свежие ники
-	sortedKillEvents := make([]logparser.KillEvent, len(parseResult.KillEvents))
-	copy(sortedKillEvents, parseResult.KillEvents)
+	sortedKillEvents := make([]logparser.KillEvent, len(killEvents))
+	copy(sortedKillEvents, killEvents)
 	sort.Slice(sortedKillEvents, func(i, j int) bool {
 		return sortedKillEvents[i].Date < sortedKillEvents[j].Date
 	})
@@ -90,6 +148,16 @@ func (p *Processor) Process(parseResult *logparser.ParseResult) *StatsData {
 		}
 	}
 
+	// Игроки, которые только позорились, тоже попадают в список
+	for _, event := range parseResult.ShameEvents {
+		for _, ref := range [][2]string{{event.PlayerName, event.PlayerSID}, {event.VictimName, event.VictimSID}} {
+			if _, ok := players[ref[1]]; !ok && isValidSteamID(ref[1]) {
+				key, title := logparser.KeyAndTitle(ref[0], ref[1])
+				players[key] = Player{Key: key, Title: title}
+			}
+		}
+	}
+
 	// Создаем упорядоченный список игроков
 	playerList := make([]Player, 0, len(players))
 	for _, player := range players {
@@ -104,6 +172,12 @@ func (p *Processor) Process(parseResult *logparser.ParseResult) *StatsData {
 	for weapon := range parseResult.WeaponSet {
 		weapons = append(weapons, weapon)
 	}
+	// Оружие тимкиллов не входит в WeaponSet
+	for _, event := range killEvents[len(parseResult.KillEvents):] {
+		if _, ok := parseResult.WeaponSet[event.Weapon]; !ok && event.Weapon != "" && !slices.Contains(weapons, event.Weapon) {
+			weapons = append(weapons, event.Weapon)
+		}
+	}
 	sort.Slice(weapons, func(i, j int) bool {
 		return strings.ToLower(weapons[i]) < strings.ToLower(weapons[j])
 	})
@@ -118,19 +192,9 @@ func (p *Processor) Process(parseResult *logparser.ParseResult) *StatsData {
 		weaponIndex[weapon] = i
 	}
 
-	// Формируем строку периода данных
-	dateRange := ""
-	if parseResult.StartDate != "" && parseResult.EndDate != "" {
-		if parseResult.StartDate == parseResult.EndDate {
-			dateRange = parseResult.StartDate
-		} else {
-			dateRange = parseResult.StartDate + " — " + parseResult.EndDate
-		}
-	}
-
 	// Группируем события по датам
 	dailyKills := make(map[string][]logparser.KillEvent)
-	for _, e := range parseResult.KillEvents {
+	for _, e := range killEvents {
 		if e.Date != "" {
 			dailyKills[e.Date] = append(dailyKills[e.Date], e)
 		}
@@ -143,6 +207,39 @@ func (p *Processor) Process(parseResult *logparser.ParseResult) *StatsData {
 		}
 	}
 
+	damageHits := markDamageHits(parseResult.DamageEvents)
+	roundGroups := 0 // Раундов урона — с этого номера Apply продолжает RoundGroup
+	for _, hit := range damageHits {
+		roundGroups = max(roundGroups, hit.RoundGroup)
+	}
+	dailyDamage := make(map[string][]DamageHit)
+	for _, e := range damageHits {
+		if e.Date != "" {
+			dailyDamage[e.Date] = append(dailyDamage[e.Date], e)
+		}
+	}
+
+	dailyThrows := make(map[string][]logparser.GrenadeEvent)
+	for _, e := range parseResult.GrenadeEvents {
+		if e.Date != "" {
+			dailyThrows[e.Date] = append(dailyThrows[e.Date], e)
+		}
+	}
+
+	dailyBomb := make(map[string][]logparser.BombEvent)
+	for _, e := range parseResult.BombEvents {
+		if e.Date != "" {
+			dailyBomb[e.Date] = append(dailyBomb[e.Date], e)
+		}
+	}
+
+	dailyHostage := make(map[string][]logparser.HostageEvent)
+	for _, e := range parseResult.HostageEvents {
+		if e.Date != "" {
+			dailyHostage[e.Date] = append(dailyHostage[e.Date], e)
+		}
+	}
+
 	dailyDefuse := make(map[string][]logparser.DefuseEvent)
 	for _, e := range parseResult.DefuseEvents {
 		if e.Date != "" {
@@ -150,6 +247,22 @@ func (p *Processor) Process(parseResult *logparser.ParseResult) *StatsData {
 		}
 	}
 
+	dailyShame := make(map[string][]logparser.ShameEvent)
+	for _, e := range parseResult.ShameEvents {
+		if e.Date != "" {
+			dailyShame[e.Date] = append(dailyShame[e.Date], e)
+		}
+	}
+
+	// Размены считаем по убийствам в порядке лога: тимкиллы в них не участвуют
+	trades := detectTrades(parseResult.KillEvents, p.tradeWindow)
+	dailyTrades := make(map[string][]TradeEvent)
+	for _, e := range trades {
+		if e.Date != "" {
+			dailyTrades[e.Date] = append(dailyTrades[e.Date], e)
+		}
+	}
+
 	dailyRounds := make(map[string][]logparser.RoundStats)
 	for _, r := range parseResult.RoundStats {
 		if r.Date != "" {
@@ -157,20 +270,13 @@ func (p *Processor) Process(parseResult *logparser.ParseResult) *StatsData {
 		}
 	}
 
-	// Строим рейтинги
-	playerRatings := p.buildPlayerRatings(parseResult.RoundStats, parseResult.KillEvents, parseResult.FlashEvents, parseResult.DefuseEvents)
+	// Первые дуэли раундов
+	openingDuels := findOpeningDuels(parseResult.RoundStats, parseResult.KillEvents)
 
-	// Вычисляем средний EPI (μ) из реальных данных
-	var totalEPI float64
-	var totalRounds int
-	for _, rating := range playerRatings {
-		totalEPI += rating.TotalEPI
-		totalRounds += rating.RoundsPlayed
-	}
-	averageMu := 0.6 // Дефолтное значение
-	if totalRounds > 0 {
-		averageMu = totalEPI / float64(totalRounds)
-	}
+	// Строим рейтинги
+	playerRatings := p.buildPlayerRatings(parseResult.RoundStats, killEvents, parseResult.FlashEvents, parseResult.DefuseEvents)
+	applyTradeStats(playerRatings, killEvents, trades)
+	applyKASTStats(playerRatings, parseResult.RoundStats, trades)
 
 	// Обновляем имена в playerList из playerRatings (актуальные ники)
 	ratingNameMap := make(map[string]string) // Key -> Name
@@ -186,26 +292,96 @@ func (p *Processor) Process(parseResult *logparser.ParseResult) *StatsData {
 		}
 	}
 
+	// Рейтинги всех моделей
+	modelRatings, averageMu := rateModels(RatingInput{Rounds: parseResult.RoundStats, Kills: killEvents, Trades: trades}, playerRatings)
+
+	// Срезы событий обрезаны по длине: Apply дописывает в них новые матчи, не затирая то, что дописывается в parseResult
 	return &StatsData{
 		Players:            playerList,
 		Weapons:            weapons,
-		KillMatrix:         p.buildKillMatrix(parseResult.KillEvents, playerList, playerIndex),
-		WeaponData:         p.buildWeaponData(parseResult.KillEvents, playerList, weapons, playerIndex, weaponIndex),
+		KillMatrix:         p.buildKillMatrix(killEvents, playerList, playerIndex),
+		WeaponData:         p.buildWeaponData(killEvents, playerList, weapons, playerIndex, weaponIndex),
+		KillModifierData:   p.buildKillModifierData(killEvents, playerList, weapons, playerIndex, weaponIndex),
 		FlashData:          p.buildFlashData(parseResult.FlashEvents, playerList, playerIndex),
+		DamageData:         p.buildDamageData(damageHits, playerList, playerIndex),
+		BombData:           p.buildBombData(parseResult.RoundStats, parseResult.BombEvents, playerList, playerIndex),
+		HostageData:        p.buildHostageData(parseResult.RoundStats, parseResult.HostageEvents, playerList, playerIndex),
+		EconomyData:        p.buildEconomyData(parseResult.RoundStats, playerList, playerIndex),
+		UtilityData:        p.buildUtilityData(parseResult, playerList, playerIndex),
 		DefuseData:         p.buildDefuseData(parseResult.DefuseEvents, playerList, playerIndex),
-		DateRange:          dateRange,
-		MinRoundsForRating: 100.0,     // Константа K для байесовского рейтинга
+		ShameData:          p.buildShameData(parseResult.ShameEvents, playerList, playerIndex),
+		ClutchData:         p.buildClutchData(parseResult.RoundStats, playerList, playerIndex),
+		OpeningStats:       p.buildOpeningStats(openingDuels),
+		DateRange:          formatDateRange(parseResult.StartDate, parseResult.EndDate),
+		MinRoundsForRating: bayesianK, // Константа K для байесовского рейтинга
 		AverageMu:          averageMu, // Средний EPI всех игроков
-		KillEvents:         parseResult.KillEvents,
-		FlashEvents:        parseResult.FlashEvents,
-		DefuseEvents:       parseResult.DefuseEvents,
-		RoundStats:         parseResult.RoundStats,
+		TradeWindow:        p.tradeWindow,
+		KillEvents:         slices.Clip(killEvents),
+		FlashEvents:        slices.Clip(parseResult.FlashEvents),
+		DamageEvents:       slices.Clip(parseResult.DamageEvents),
+		GrenadeEvents:      slices.Clip(parseResult.GrenadeEvents),
+		BombEvents:         slices.Clip(parseResult.BombEvents),
+		HostageEvents:      slices.Clip(parseResult.HostageEvents),
+		DefuseEvents:       slices.Clip(parseResult.DefuseEvents),
+		ShameEvents:        slices.Clip(parseResult.ShameEvents),
+		TradeEvents:        trades,
+		OpeningDuels:       openingDuels,
+		RoundStats:         slices.Clip(parseResult.RoundStats),
+		Matches:            slices.Clip(parseResult.Matches),
 		PlayerRatings:      playerRatings,
+		RatingModel:        p.ratingModel,
+		ModelRatings:       modelRatings,
 		DailyKills:         dailyKills,
 		DailyFlash:         dailyFlash,
+		DailyDamage:        dailyDamage,
+		DailyThrows:        dailyThrows,
+		DailyBomb:          dailyBomb,
+		DailyHostage:       dailyHostage,
 		DailyDefuse:        dailyDefuse,
+		DailyShame:         dailyShame,
+		DailyTrades:        dailyTrades,
 		DailyRounds:        dailyRounds,
+		startDate:          parseResult.StartDate,
+		endDate:            parseResult.EndDate,
+		roundGroups:        roundGroups,
+		logKills:           len(parseResult.KillEvents),
+		purchases:          len(parseResult.PurchaseEvents),
+		customs:            len(parseResult.CustomEvents),
+	}
+}
+
+// formatDateRange формирует строку периода данных (StatsData.DateRange)
+func formatDateRange(start, end string) string {
+	if start == "" || end == "" {
+		return ""
 	}
+	if start == end {
+		return start
+	}
+	return start + " — " + end
+}
+
+// rateModels считает рейтинги всех моделей; имена игроков берутся из ratings.
+// Возвращает также средний EPI (μ) — Mean модели по умолчанию.
+func rateModels(input RatingInput, ratings []PlayerRating) ([]ModelRatings, float64) {
+	ratingNames := make(map[int64]string, len(ratings))
+	for _, rating := range ratings {
+		ratingNames[rating.AccountID] = rating.Name
+	}
+	models := RatingModels()
+	modelRatings := make([]ModelRatings, 0, len(models))
+	var averageMu float64
+	for _, model := range models {
+		result := model.Rate(input)
+		for i := range result.Ratings {
+			result.Ratings[i].Name = ratingNames[result.Ratings[i].AccountID]
+		}
+		if result.Model == DefaultRatingModel {
+			averageMu = result.Mean
+		}
+		modelRatings = append(modelRatings, result)
+	}
+	return modelRatings, averageMu
 }
 
 // buildKillMatrix создает матрицу убийств
@@ -251,11 +427,20 @@ func (p *Processor) buildWeaponData(events []logparser.KillEvent, players []Play
 
 	killerMax, victimMax := 0, 0
 
+	// Сумма дистанций и количество убийств с известными позициями по оружию
+	distanceSum := make([]float64, len(weapons))
+	distanceCount := make([]int, len(weapons))
+
 	for _, event := range events {
 		if event.Weapon == "" {
 			continue
 		}
 
+		if wIdx, ok := weaponIndex[event.Weapon]; ok && event.Distance > 0 {
+			distanceSum[wIdx] += event.Distance
+			distanceCount[wIdx]++
+		}
+
 		kKey, _ := logparser.KeyAndTitle(event.KillerName, event.KillerSID)
 		vKey, _ := logparser.KeyAndTitle(event.VictimName, event.VictimSID)
 
@@ -292,8 +477,63 @@ func (p *Processor) buildWeaponData(events []logparser.KillEvent, players []Play
 		KillerWeaponMatrix: killerWeaponMatrix,
 		VictimWeaponMatrix: victimWeaponMatrix,
 		WeaponKillsMatrix:  weaponKillsMatrix,
+		AvgDistance:        averageDistances(distanceSum, distanceCount),
 		KillerMax:          killerMax,
 		VictimMax:          victimMax,
+		distanceSum:        distanceSum,
+		distanceCount:      distanceCount,
+	}
+}
+
+// averageDistances делит суммы дистанций на количество убийств с известными позициями
+func averageDistances(distanceSum []float64, distanceCount []int) []float64 {
+	avgDistance := make([]float64, len(distanceSum))
+	for i := range distanceSum {
+		if distanceCount[i] > 0 {
+			avgDistance[i] = distanceSum[i] / float64(distanceCount[i])
+		}
+	}
+	return avgDistance
+}
+
+// buildKillModifierData считает хедшоты, прострелы и прочие модификаторы по убийцам и по оружию
+func (p *Processor) buildKillModifierData(events []logparser.KillEvent, players []Player, weapons []string, playerIndex, weaponIndex map[string]int) KillModifierData {
+	byPlayer := make([]KillModifiers, len(players))
+	byWeapon := make([]KillModifiers, len(weapons))
+
+	for _, event := range events {
+		kKey, _ := logparser.KeyAndTitle(event.KillerName, event.KillerSID)
+		if kIdx, ok := playerIndex[kKey]; ok {
+			byPlayer[kIdx].add(event)
+		}
+		if wIdx, ok := weaponIndex[event.Weapon]; ok {
+			byWeapon[wIdx].add(event)
+		}
+	}
+
+	return KillModifierData{
+		ByPlayer: byPlayer,
+		ByWeapon: byWeapon,
+	}
+}
+
+// add учитывает одно убийство
+func (m *KillModifiers) add(event logparser.KillEvent) {
+	m.Kills++
+	if event.Headshot {
+		m.Headshots++
+	}
+	if event.Penetrated {
+		m.Wallbangs++
+	}
+	if event.NoScope {
+		m.NoScopes++
+	}
+	if event.ThroughSmoke {
+		m.ThroughSmoke++
+	}
+	if event.AttackerBlind {
+		m.AttackerBlind++
 	}
 }
 
@@ -343,6 +583,476 @@ func (p *Processor) buildFlashData(events []logparser.FlashEvent, players []Play
 	}
 }
 
+// unknownRoundGap — пауза между попаданиями, после которой попадания без номера раунда относятся к следующему раунду:
+// между последним попаданием раунда и первым попаданием следующего проходят задержка конца раунда, фризтайм и выход на позиции
+const unknownRoundGap = 20 * time.Second
+
+// friendlyDamage — урон по себе или по союзнику (команды известны и совпадают)
+func friendlyDamage(event logparser.DamageEvent) bool {
+	return event.AttackerSID == event.VictimSID || (event.AttackerTeam != "" && event.AttackerTeam == event.VictimTeam)
+}
+
+// markDamageHits размечает попадания раундами и уроном по своим. Раунд — MatchID и номер раунда;
+// попадания без номера (Round == 0: нет JSON блока) делятся на раунды внутри матча по паузам дольше
+// unknownRoundGap и по попаданию в игрока, уже убитого в текущем раунде.
+func markDamageHits(events []logparser.DamageEvent) []DamageHit {
+	type roundKey struct {
+		matchID string
+		round   int
+	}
+	type unknownRound struct {
+		group int
+		last  time.Time
+		dead  map[string]bool
+	}
+
+	hits := make([]DamageHit, len(events))
+	groups := make(map[roundKey]int)
+	unknown := make(map[string]*unknownRound) // MatchID -> текущий раунд без номера
+	lastGroup := 0
+	for i, event := range events {
+		hit := DamageHit{
+			DamageEvent: event,
+			Friendly:    friendlyDamage(event),
+		}
+		if event.Round != 0 {
+			key := roundKey{event.MatchID, event.Round}
+			if groups[key] == 0 {
+				lastGroup++
+				groups[key] = lastGroup
+			}
+			hit.RoundGroup = groups[key]
+		} else {
+			round := unknown[event.MatchID]
+			if round == nil || round.dead[event.VictimSID] || event.Time.Sub(round.last) > unknownRoundGap {
+				lastGroup++
+				round = &unknownRound{group: lastGroup, dead: make(map[string]bool)}
+				unknown[event.MatchID] = round
+			}
+			round.last = event.Time
+			if event.Health == 0 {
+				round.dead[event.VictimSID] = true
+			}
+			hit.RoundGroup = round.group
+		}
+		hits[i] = hit
+	}
+	return hits
+}
+
+// buildDamageData создает матрицу урона и распределение попаданий по частям тела.
+// Урон по союзникам и себе считается отдельно (TeamDamage) и в остальные поля не входит.
+func (p *Processor) buildDamageData(events []DamageHit, players []Player, playerIndex map[string]int) DamageData {
+	matrix := make([][]int, len(players))
+	for i := range matrix {
+		matrix[i] = make([]int, len(players))
+	}
+	given := make([]int, len(players))
+	received := make([]int, len(players))
+	withoutKill := make([]int, len(players))
+	teamDamage := make([]int, len(players))
+
+	// Собираем списки оружия и частей тела
+	weaponSet := make(map[string]struct{})
+	hitgroupSet := make(map[string]struct{})
+	for _, event := range events {
+		if event.Friendly {
+			continue
+		}
+		weaponSet[event.Weapon] = struct{}{}
+		hitgroupSet[event.Hitgroup] = struct{}{}
+	}
+	weapons := sortedKeys(weaponSet)
+	hitgroups := sortedKeys(hitgroupSet)
+	weaponIndex := indexOf(weapons)
+	hitgroupIndex := indexOf(hitgroups)
+
+	weaponMatrix := make([][]int, len(players))
+	hitgroupMatrix := make([][]int, len(players))
+	for i := range players {
+		weaponMatrix[i] = make([]int, len(weapons))
+		hitgroupMatrix[i] = make([]int, len(hitgroups))
+	}
+
+	maxDamage := 0
+	for _, event := range events {
+		aIdx, aOK := playerIndex[event.AttackerSID]
+		vIdx, vOK := playerIndex[event.VictimSID]
+
+		if event.Friendly {
+			if aOK {
+				teamDamage[aIdx] += event.HealthDamage
+			}
+			continue
+		}
+		if aOK {
+			given[aIdx] += event.HealthDamage
+			weaponMatrix[aIdx][weaponIndex[event.Weapon]] += event.HealthDamage
+			hitgroupMatrix[aIdx][hitgroupIndex[event.Hitgroup]]++
+		}
+		if vOK {
+			received[vIdx] += event.HealthDamage
+		}
+		if aOK && vOK {
+			matrix[aIdx][vIdx] += event.HealthDamage
+			if matrix[aIdx][vIdx] > maxDamage {
+				maxDamage = matrix[aIdx][vIdx]
+			}
+		}
+	}
+
+	// Урон без убийства: по каждому раунду (RoundGroup) суммируем урон по паре атакующий-жертва
+	// и засчитываем его, если смертельное попадание по жертве сделал не этот игрок
+	type pair struct {
+		round            int
+		attacker, victim string
+	}
+	damage := make(map[pair]int)
+	killed := make(map[pair]bool)
+	for _, event := range events {
+		if event.Friendly {
+			continue
+		}
+		key := pair{event.RoundGroup, event.AttackerSID, event.VictimSID}
+		damage[key] += event.HealthDamage
+		if event.Health == 0 {
+			killed[key] = true
+		}
+	}
+	for key, dmg := range damage {
+		if aIdx, ok := playerIndex[key.attacker]; ok && !killed[key] {
+			withoutKill[aIdx] += dmg
+		}
+	}
+
+	if maxDamage == 0 {
+		maxDamage = 1
+	}
+
+	return DamageData{
+		Matrix:         matrix,
+		Given:          given,
+		Received:       received,
+		WithoutKill:    withoutKill,
+		TeamDamage:     teamDamage,
+		Weapons:        weapons,
+		WeaponMatrix:   weaponMatrix,
+		Hitgroups:      hitgroups,
+		HitgroupMatrix: hitgroupMatrix,
+		Max:            maxDamage,
+	}
+}
+
+// buildBombData создает статистику закладок по картам и игрокам и разбивку условий победы
+func (p *Processor) buildBombData(rounds []logparser.RoundStats, events []logparser.BombEvent, players []Player, playerIndex map[string]int) BombData {
+	plants := make([]int, len(players))
+	plantsA := make([]int, len(players))
+	plantsB := make([]int, len(players))
+	postPlantWins := make([]int, len(players))
+	drops := make([]int, len(players))
+
+	mapStats := make(map[string]*BombMapStats)
+	for _, round := range rounds {
+		ms, ok := mapStats[round.Map]
+		if !ok {
+			ms = &BombMapStats{Map: round.Map, WinConditions: make(map[string]int)}
+			mapStats[round.Map] = ms
+		}
+		ms.Rounds++
+		if round.WinCondition != "" {
+			ms.WinConditions[round.WinCondition]++
+		}
+
+		if round.BombSite == "" {
+			continue
+		}
+		switch round.BombSite {
+		case "A":
+			ms.PlantsA++
+		case "B":
+			ms.PlantsB++
+		}
+		if round.Winner == 2 {
+			ms.PostPlantWins++
+		}
+
+		if pIdx, ok := playerIndex[round.PlanterSID]; ok {
+			plants[pIdx]++
+			switch round.BombSite {
+			case "A":
+				plantsA[pIdx]++
+			case "B":
+				plantsB[pIdx]++
+			}
+			if round.Winner == 2 {
+				postPlantWins[pIdx]++
+			}
+		}
+	}
+
+	for _, event := range events {
+		if event.EventType != "drop" {
+			continue
+		}
+		if pIdx, ok := playerIndex[event.PlayerSID]; ok {
+			drops[pIdx]++
+		}
+	}
+
+	maps := make([]BombMapStats, 0, len(mapStats))
+	for _, ms := range mapStats {
+		maps = append(maps, *ms)
+	}
+	sort.Slice(maps, func(i, j int) bool {
+		return maps[i].Map < maps[j].Map
+	})
+
+	return BombData{
+		Maps:          maps,
+		Plants:        plants,
+		PlantsA:       plantsA,
+		PlantsB:       plantsB,
+		PostPlantWins: postPlantWins,
+		Drops:         drops,
+	}
+}
+
+// buildHostageData создает статистику заложников: спасения, подборы, убийства и победы спасением
+func (p *Processor) buildHostageData(rounds []logparser.RoundStats, events []logparser.HostageEvent, players []Player, playerIndex map[string]int) HostageData {
+	data := HostageData{
+		Rescues:    make([]int, len(players)),
+		Pickups:    make([]int, len(players)),
+		Kills:      make([]int, len(players)),
+		Rounds:     make([]int, len(players)),
+		RescueWins: make([]int, len(players)),
+	}
+
+	for _, event := range events {
+		pIdx, ok := playerIndex[event.PlayerSID]
+		if !ok {
+			continue
+		}
+		switch event.EventType {
+		case "rescue":
+			data.Rescues[pIdx]++
+		case "pickup":
+			data.Pickups[pIdx]++
+		case "kill":
+			data.Kills[pIdx]++
+		}
+	}
+
+	for _, round := range rounds {
+		if !isHostageMap(round.Map) {
+			continue
+		}
+		for _, ps := range round.Players {
+			pIdx, ok := playerIndex[fmt.Sprintf("[U:1:%d]", ps.AccountID)]
+			if !ok {
+				continue
+			}
+			data.Rounds[pIdx]++
+			if ps.Team == 3 && round.Winner == 3 && round.WinCondition == logparser.WinHostageRescued {
+				data.RescueWins[pIdx]++
+			}
+		}
+	}
+
+	return data
+}
+
+// isHostageMap проверяет, что карта — сценарий с заложниками (cs_office, cs_italy, ...)
+func isHostageMap(name string) bool {
+	return strings.HasPrefix(name, "cs_")
+}
+
+// buildUtilityData создает статистику гранат: броски, урон по видам гранат и эффективность флешек
+func (p *Processor) buildUtilityData(parseResult *logparser.ParseResult, players []Player, playerIndex map[string]int) UtilityData {
+	grenadeIndex := indexOf(UtilityGrenades)
+
+	throws := make([][]int, len(players))
+	damage := make([][]int, len(players))
+	for i := range players {
+		throws[i] = make([]int, len(UtilityGrenades))
+		damage[i] = make([]int, len(UtilityGrenades))
+	}
+	rounds := make([]int, len(players))
+	enemiesBlinded := make([]int, len(players))
+	teamBlinded := make([]int, len(players))
+	reportedUD := make([]int, len(players))
+
+	for _, event := range parseResult.GrenadeEvents {
+		gIdx, ok := grenadeIndex[utilityGrenade(event.Grenade)]
+		if pIdx, found := playerIndex[event.ThrowerSID]; found && ok {
+			throws[pIdx][gIdx]++
+		}
+	}
+
+	// Урон по своим и себе в урон гранатами не входит, как и в урон в DamageData
+	for _, event := range parseResult.DamageEvents {
+		if friendlyDamage(event) {
+			continue
+		}
+		gIdx, ok := grenadeIndex[utilityGrenade(event.Weapon)]
+		if pIdx, found := playerIndex[event.AttackerSID]; found && ok {
+			damage[pIdx][gIdx] += event.HealthDamage
+		}
+	}
+
+	for _, event := range parseResult.FlashEvents {
+		fIdx, ok := playerIndex[event.FlasherSID]
+		if !ok {
+			continue
+		}
+		if event.TeamFlash {
+			teamBlinded[fIdx]++
+		} else {
+			enemiesBlinded[fIdx]++
+		}
+	}
+
+	for _, round := range parseResult.RoundStats {
+		for _, ps := range round.Players {
+			if pIdx, ok := playerIndex[fmt.Sprintf("[U:1:%d]", ps.AccountID)]; ok {
+				rounds[pIdx]++
+				reportedUD[pIdx] += ps.UD
+			}
+		}
+	}
+
+	data := UtilityData{
+		Grenades:       UtilityGrenades,
+		Throws:         throws,
+		Damage:         damage,
+		Rounds:         rounds,
+		EnemiesBlinded: enemiesBlinded,
+		TeamBlinded:    teamBlinded,
+		ReportedUD:     reportedUD,
+	}
+	data.finish()
+	return data
+}
+
+// finish считает броски за раунд, урон за гранату и ослепления за флешку по счётчикам
+func (u *UtilityData) finish() {
+	grenadeIndex := indexOf(UtilityGrenades)
+	heIdx, molotovIdx, flashIdx := grenadeIndex["hegrenade"], grenadeIndex["molotov"], grenadeIndex["flashbang"]
+	u.ThrowsPerRound = make([]float64, len(u.Throws))
+	u.DamagePerNade = make([]float64, len(u.Throws))
+	u.BlindsPerFlash = make([]float64, len(u.Throws))
+	for i, throws := range u.Throws {
+		total := 0
+		for _, n := range throws {
+			total += n
+		}
+		u.ThrowsPerRound[i] = ratio(total, u.Rounds[i])
+		u.DamagePerNade[i] = ratio(u.Damage[i][heIdx]+u.Damage[i][molotovIdx], throws[heIdx]+throws[molotovIdx])
+		u.BlindsPerFlash[i] = ratio(u.EnemiesBlinded[i], throws[flashIdx])
+	}
+}
+
+// utilityGrenade приводит название гранаты или оружия урона к виду из UtilityGrenades.
+// Зажигательная граната считается молотовым, урон от огня приходит как "inferno".
+func utilityGrenade(name string) string {
+	switch name {
+	case "molotov", "incgrenade", "inferno":
+		return "molotov"
+	default:
+		return name
+	}
+}
+
+// ratio возвращает a/b или 0, если b == 0
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// sortedKeys возвращает отсортированные ключи множества
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// indexOf строит индекс значение -> позиция
+func indexOf(values []string) map[string]int {
+	index := make(map[string]int, len(values))
+	for i, v := range values {
+		index[v] = i
+	}
+	return index
+}
+
+// buildShameData считает позорные смерти по игрокам
+func (p *Processor) buildShameData(events []logparser.ShameEvent, players []Player, playerIndex map[string]int) ShameData {
+	n := len(players)
+	data := ShameData{
+		TeamKills:   make([]int, n),
+		TeamKilled:  make([]int, n),
+		Suicides:    make([]int, n),
+		WorldDeaths: make([]int, n),
+		BombDeaths:  make([]int, n),
+	}
+
+	for _, e := range events {
+		playerIdx, ok := playerIndex[e.PlayerSID]
+		if !ok {
+			continue
+		}
+		switch e.Kind {
+		case logparser.ShameTeamKill:
+			data.TeamKills[playerIdx]++
+			if victimIdx, ok := playerIndex[e.VictimSID]; ok {
+				data.TeamKilled[victimIdx]++
+			}
+		case logparser.ShameSuicide:
+			data.Suicides[playerIdx]++
+		case logparser.ShameWorld:
+			data.WorldDeaths[playerIdx]++
+		case logparser.ShameBomb:
+			data.BombDeaths[playerIdx]++
+		}
+	}
+
+	return data
+}
+
+// buildClutchData собирает клатчи 1vN по PlayerStats.ClutchVs и победителю раунда
+func (p *Processor) buildClutchData(rounds []logparser.RoundStats, players []Player, playerIndex map[string]int) ClutchData {
+	data := ClutchData{
+		Attempts: make([][]int, len(players)),
+		Wins:     make([][]int, len(players)),
+	}
+	for i := range players {
+		data.Attempts[i] = make([]int, logparser.MaxClutchEnemies)
+		data.Wins[i] = make([]int, logparser.MaxClutchEnemies)
+	}
+
+	for _, round := range rounds {
+		for _, ps := range round.Players {
+			if ps.ClutchVs < 1 || ps.ClutchVs > logparser.MaxClutchEnemies {
+				continue
+			}
+			pIdx, ok := playerIndex[fmt.Sprintf("[U:1:%d]", ps.AccountID)]
+			if !ok {
+				continue
+			}
+			data.Attempts[pIdx][ps.ClutchVs-1]++
+			if round.Winner == ps.Team {
+				data.Wins[pIdx][ps.ClutchVs-1]++
+			}
+		}
+	}
+
+	return data
+}
+
 // buildDefuseData создает данные по дефьюзу
 func (p *Processor) buildDefuseData(events []logparser.DefuseEvent, players []Player, playerIndex map[string]int) DefuseData {
 	attempts := make([]int, len(players))
@@ -444,9 +1154,6 @@ func transposeMatrix(matrix [][]int) [][]int {
 
 // buildPlayerRatings строит агрегированные рейтинги игроков
 func (p *Processor) buildPlayerRatings(roundStats []logparser.RoundStats, killEvents []logparser.KillEvent, flashEvents []logparser.FlashEvent, _ []logparser.DefuseEvent) []PlayerRating {
-	// Константы для байесовского рейтинга
-	const K = 100.0 // Минимальное количество раундов для "достоверности"
-
 	// Создаем маппинг AccountID -> имя игрока
 	playerNames := make(map[int64]string)
 
@@ -459,17 +1166,11 @@ func (p *Processor) buildPlayerRatings(roundStats []logparser.RoundStats, killEv
 
 	// Собираем имена из событий убийств (последний ник будет актуальным)
 	for _, event := range sortedKills {
-		if len(event.KillerSID) > 6 {
-			var accountID int64
-			if n, _ := fmt.Sscanf(event.KillerSID[5:len(event.KillerSID)-1], "%d", &accountID); n > 0 && accountID > 0 {
-				playerNames[accountID] = event.KillerName
-			}
+		if accountID, ok := logparser.AccountIDFromSID(event.KillerSID); ok {
+			playerNames[accountID] = event.KillerName
 		}
-		if len(event.VictimSID) > 6 {
-			var accountID int64
-			if n, _ := fmt.Sscanf(event.VictimSID[5:len(event.VictimSID)-1], "%d", &accountID); n > 0 && accountID > 0 {
-				playerNames[accountID] = event.VictimName
-			}
+		if accountID, ok := logparser.AccountIDFromSID(event.VictimSID); ok {
+			playerNames[accountID] = event.VictimName
 		}
 	}
 
@@ -482,17 +1183,11 @@ func (p *Processor) buildPlayerRatings(roundStats []logparser.RoundStats, killEv
 
 	// Собираем имена из событий флешек (последний ник будет актуальным)
 	for _, event := range sortedFlash {
-		if len(event.FlasherSID) > 6 {
-			var accountID int64
-			if n, _ := fmt.Sscanf(event.FlasherSID[5:len(event.FlasherSID)-1], "%d", &accountID); n > 0 && accountID > 0 {
-				playerNames[accountID] = event.FlasherName
-			}
+		if accountID, ok := logparser.AccountIDFromSID(event.FlasherSID); ok {
+			playerNames[accountID] = event.FlasherName
 		}
-		if len(event.VictimSID) > 6 {
-			var accountID int64
-			if n, _ := fmt.Sscanf(event.VictimSID[5:len(event.VictimSID)-1], "%d", &accountID); n > 0 && accountID > 0 {
-				playerNames[accountID] = event.VictimName
-			}
+		if accountID, ok := logparser.AccountIDFromSID(event.VictimSID); ok {
+			playerNames[accountID] = event.VictimName
 		}
 	}
 
@@ -542,43 +1237,34 @@ func (p *Processor) buildPlayerRatings(roundStats []logparser.RoundStats, killEv
 		}
 	}
 
-	// Рассчитываем среднее EPI по всем игрокам для использования как μ (мю)
-	var totalEPI float64
-	var totalRounds int
-	for _, rating := range playerData {
-		totalEPI += rating.TotalEPI
-		totalRounds += rating.RoundsPlayed
-	}
+	// Байесовский рейтинг и порядок игроков даёт модель по умолчанию DefaultRatingModel (EPI + Байес)
+	epi := epiModel{}.Rate(RatingInput{Rounds: roundStats, Kills: killEvents})
 
-	mu := 0.6 // Дефолтное значение если нет данных
-	if totalRounds > 0 {
-		mu = totalEPI / float64(totalRounds)
-	}
+	ratings := make([]PlayerRating, 0, len(epi.Ratings))
+	for _, modelRating := range epi.Ratings {
+		rating := playerData[modelRating.AccountID]
+		if rating == nil {
+			continue
+		}
+		rating.BayesianEPI = modelRating.Score
 
-	// Рассчитываем финальные рейтинги
-	ratings := make([]PlayerRating, 0, len(playerData))
-	for _, rating := range playerData {
 		// Простое среднее
 		if rating.RoundsPlayed > 0 {
 			rating.AverageEPI = rating.TotalEPI / float64(rating.RoundsPlayed)
 		}
 
-		// Байесовский рейтинг с регуляризацией
-		// BayesianEPI = (TotalEPI + K * μ) / (RoundsPlayed + K)
-		rating.BayesianEPI = (rating.TotalEPI + K*mu) / (float64(rating.RoundsPlayed) + K)
-
 		// Если нет имени, используем AccountID
 		if rating.Name == "" {
-			rating.Name = fmt.Sprintf("Player_%d", rating.AccountID)
+			rating.Name = fallbackPlayerName(rating.AccountID)
 		}
 
 		ratings = append(ratings, *rating)
 	}
 
-	// Сортируем по байесовскому рейтингу (по убыванию)
-	sort.Slice(ratings, func(i, j int) bool {
-		return ratings[i].BayesianEPI > ratings[j].BayesianEPI
-	})
-
 	return ratings
 }
+
+// fallbackPlayerName — имя игрока, ник которого не встретился ни в одном убийстве и ни в одной флешке
+func fallbackPlayerName(accountID int64) string {
+	return fmt.Sprintf("Player_%d", accountID)
+}
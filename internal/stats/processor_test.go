@@ -3,6 +3,7 @@ package stats
 import (
 	"math"
 	"testing"
+	"time"
 
 	"oldfartscounter/internal/logparser"
 )
@@ -585,3 +586,332 @@ func TestBuildPlayerRatings_AggregationAccuracy(t *testing.T) {
 		t.Errorf("Expected average EPI %.3f, got %.3f", expectedAverageEPI, player.AverageEPI)
 	}
 }
+
+// TestBuildKillModifierData_Counts tests headshot, wallbang and noscope counts per player and weapon
+func TestBuildKillModifierData_Counts(t *testing.T) {
+	processor := New()
+
+	players := []Player{{Key: "[U:1:1]", Title: "Alice"}, {Key: "[U:1:2]", Title: "Bob"}}
+	weapons := []string{"ak47", "awp"}
+	playerIndex := map[string]int{"[U:1:1]": 0, "[U:1:2]": 1}
+	weaponIndex := map[string]int{"ak47": 0, "awp": 1}
+
+	events := []logparser.KillEvent{
+		{KillerSID: "[U:1:1]", VictimSID: "[U:1:2]", Weapon: "ak47", Headshot: true},
+		{KillerSID: "[U:1:1]", VictimSID: "[U:1:2]", Weapon: "ak47", Headshot: true, Penetrated: true},
+		{KillerSID: "[U:1:1]", VictimSID: "[U:1:2]", Weapon: "awp", NoScope: true},
+		{KillerSID: "[U:1:2]", VictimSID: "[U:1:1]", Weapon: "awp", ThroughSmoke: true, AttackerBlind: true},
+	}
+
+	data := processor.buildKillModifierData(events, players, weapons, playerIndex, weaponIndex)
+
+	alice := data.ByPlayer[0]
+	if alice.Kills != 3 || alice.Headshots != 2 || alice.Wallbangs != 1 || alice.NoScopes != 1 {
+		t.Errorf("Unexpected modifiers for Alice: %+v", alice)
+	}
+
+	bob := data.ByPlayer[1]
+	if bob.Kills != 1 || bob.ThroughSmoke != 1 || bob.AttackerBlind != 1 || bob.Headshots != 0 {
+		t.Errorf("Unexpected modifiers for Bob: %+v", bob)
+	}
+
+	awp := data.ByWeapon[1]
+	if awp.Kills != 2 || awp.NoScopes != 1 || awp.ThroughSmoke != 1 {
+		t.Errorf("Unexpected modifiers for awp: %+v", awp)
+	}
+}
+
+// TestBuildWeaponData_AvgDistance tests that average distance ignores kills without positions
+func TestBuildWeaponData_AvgDistance(t *testing.T) {
+	processor := New()
+
+	players := []Player{{Key: "[U:1:1]", Title: "Alice"}}
+	weapons := []string{"awp", "knife"}
+	playerIndex := map[string]int{"[U:1:1]": 0}
+	weaponIndex := map[string]int{"awp": 0, "knife": 1}
+
+	events := []logparser.KillEvent{
+		{KillerSID: "[U:1:1]", Weapon: "awp", Distance: 2000},
+		{KillerSID: "[U:1:1]", Weapon: "awp", Distance: 1000},
+		{KillerSID: "[U:1:1]", Weapon: "awp"}, // no positions in log
+		{KillerSID: "[U:1:1]", Weapon: "knife"},
+	}
+
+	data := processor.buildWeaponData(events, players, weapons, playerIndex, weaponIndex)
+
+	if data.AvgDistance[0] != 1500 {
+		t.Errorf("Expected awp average distance 1500, got %v", data.AvgDistance[0])
+	}
+	if data.AvgDistance[1] != 0 {
+		t.Errorf("Expected knife average distance 0, got %v", data.AvgDistance[1])
+	}
+}
+
+// TestBuildDamageData_WithoutKill tests damage matrix and damage dealt without finishing the victim
+func TestBuildDamageData_WithoutKill(t *testing.T) {
+	processor := New()
+
+	players := []Player{{Key: "[U:1:1]", Title: "Alice"}, {Key: "[U:1:2]", Title: "Bob"}, {Key: "[U:1:3]", Title: "Carl"}}
+	playerIndex := map[string]int{"[U:1:1]": 0, "[U:1:2]": 1, "[U:1:3]": 2}
+
+	events := []logparser.DamageEvent{
+		// Round 1: Alice softens Bob, Carl finishes him
+		{AttackerSID: "[U:1:1]", VictimSID: "[U:1:2]", Weapon: "ak47", HealthDamage: 60, Health: 40, Hitgroup: "chest", Date: "2025-09-05", Round: 1},
+		{AttackerSID: "[U:1:3]", VictimSID: "[U:1:2]", Weapon: "awp", HealthDamage: 40, Health: 0, Hitgroup: "head", Date: "2025-09-05", Round: 1},
+		// Round 2: Alice kills Bob herself
+		{AttackerSID: "[U:1:1]", VictimSID: "[U:1:2]", Weapon: "ak47", HealthDamage: 30, Health: 70, Hitgroup: "chest", Date: "2025-09-05", Round: 2},
+		{AttackerSID: "[U:1:1]", VictimSID: "[U:1:2]", Weapon: "hegrenade", HealthDamage: 70, Health: 0, Hitgroup: "generic", Date: "2025-09-05", Round: 2},
+	}
+
+	data := processor.buildDamageData(markDamageHits(events), players, playerIndex)
+
+	if data.Matrix[0][1] != 160 || data.Matrix[2][1] != 40 || data.Max != 160 {
+		t.Errorf("Unexpected damage matrix: %v (max %d)", data.Matrix, data.Max)
+	}
+	if data.Given[0] != 160 || data.Received[1] != 200 {
+		t.Errorf("Unexpected given/received: %v / %v", data.Given, data.Received)
+	}
+	if data.WithoutKill[0] != 60 || data.WithoutKill[2] != 0 {
+		t.Errorf("Expected only round 1 damage of Alice without kill, got %v", data.WithoutKill)
+	}
+	if len(data.Weapons) != 3 || data.Weapons[1] != "awp" {
+		t.Errorf("Unexpected weapons: %v", data.Weapons)
+	}
+	if len(data.Hitgroups) != 3 || data.HitgroupMatrix[0][0] != 2 {
+		t.Errorf("Unexpected hitgroups: %v %v", data.Hitgroups, data.HitgroupMatrix)
+	}
+}
+
+// TestBuildDamageData_UnknownRounds tests that rounds without a number are split by match, pauses and repeated deaths,
+// and that team and self damage is kept out of damage given
+func TestBuildDamageData_UnknownRounds(t *testing.T) {
+	processor := New()
+
+	players := []Player{{Key: "[U:1:1]", Title: "Alice"}, {Key: "[U:1:2]", Title: "Bob"}, {Key: "[U:1:3]", Title: "Carl"}}
+	playerIndex := map[string]int{"[U:1:1]": 0, "[U:1:2]": 1, "[U:1:3]": 2}
+
+	start := time.Date(2025, 9, 5, 18, 0, 0, 0, time.UTC)
+	hit := func(sec int, matchID, attacker, attackerTeam, victim, victimTeam string, damage, health int) logparser.DamageEvent {
+		return logparser.DamageEvent{
+			AttackerSID: attacker, AttackerTeam: attackerTeam, VictimSID: victim, VictimTeam: victimTeam,
+			Weapon: "ak47", Hitgroup: "chest", HealthDamage: damage, Health: health,
+			Date: "2025-09-05", MatchID: matchID, Time: start.Add(time.Duration(sec) * time.Second),
+		}
+	}
+	events := []logparser.DamageEvent{
+		// Unknown round A: Alice hits Bob, Bob survives
+		hit(0, "m1", "[U:1:1]", "CT", "[U:1:2]", "TERRORIST", 40, 60),
+		// Unknown round B after a pause: Alice kills Bob — round A damage is still without a kill
+		hit(60, "m1", "[U:1:1]", "CT", "[U:1:2]", "TERRORIST", 100, 0),
+		// Round C right away: Bob is hit again, so a new round started; Alice only damages him
+		hit(65, "m1", "[U:1:1]", "CT", "[U:1:2]", "TERRORIST", 30, 70),
+		// Another match at the same time is a separate round: Carl kills Bob there
+		hit(65, "m2", "[U:1:3]", "CT", "[U:1:2]", "TERRORIST", 100, 0),
+		// Team and self damage
+		hit(66, "m1", "[U:1:1]", "CT", "[U:1:3]", "CT", 25, 75),
+		hit(67, "m1", "[U:1:3]", "CT", "[U:1:3]", "CT", 10, 65),
+	}
+
+	hits := markDamageHits(events)
+	if hits[0].RoundGroup == hits[1].RoundGroup || hits[1].RoundGroup == hits[2].RoundGroup || hits[2].RoundGroup == hits[3].RoundGroup {
+		t.Errorf("Expected separate rounds, got groups %d %d %d %d", hits[0].RoundGroup, hits[1].RoundGroup, hits[2].RoundGroup, hits[3].RoundGroup)
+	}
+	if !hits[4].Friendly || !hits[5].Friendly || hits[0].Friendly {
+		t.Errorf("Unexpected friendly marks: %+v", hits)
+	}
+
+	data := processor.buildDamageData(hits, players, playerIndex)
+	if data.WithoutKill[0] != 70 || data.WithoutKill[2] != 0 {
+		t.Errorf("Expected 40+30 damage of Alice without kill, got %v", data.WithoutKill)
+	}
+	if data.Given[0] != 170 || data.Given[2] != 100 || data.Received[2] != 0 || data.Matrix[0][2] != 0 {
+		t.Errorf("Team damage leaked into given/received: %v / %v", data.Given, data.Received)
+	}
+	if data.TeamDamage[0] != 25 || data.TeamDamage[2] != 10 || data.WeaponMatrix[0][0] != 170 || data.HitgroupMatrix[0][0] != 3 {
+		t.Errorf("Unexpected team damage %v or weapon/hitgroup totals %v %v", data.TeamDamage, data.WeaponMatrix, data.HitgroupMatrix)
+	}
+}
+
+// TestBuildUtilityData_Breakdown tests throws, grenade damage and flash efficiency
+func TestBuildUtilityData_Breakdown(t *testing.T) {
+	processor := New()
+
+	players := []Player{{Key: "[U:1:1]", Title: "Alice"}, {Key: "[U:1:2]", Title: "Bob"}}
+	playerIndex := map[string]int{"[U:1:1]": 0, "[U:1:2]": 1}
+
+	parseResult := &logparser.ParseResult{
+		GrenadeEvents: []logparser.GrenadeEvent{
+			{ThrowerSID: "[U:1:1]", Grenade: "hegrenade"},
+			{ThrowerSID: "[U:1:1]", Grenade: "incgrenade"},
+			{ThrowerSID: "[U:1:1]", Grenade: "flashbang"},
+			{ThrowerSID: "[U:1:1]", Grenade: "flashbang"},
+		},
+		DamageEvents: []logparser.DamageEvent{
+			{AttackerSID: "[U:1:1]", VictimSID: "[U:1:2]", Weapon: "hegrenade", HealthDamage: 50},
+			{AttackerSID: "[U:1:1]", VictimSID: "[U:1:2]", Weapon: "inferno", HealthDamage: 20},
+			{AttackerSID: "[U:1:1]", VictimSID: "[U:1:2]", Weapon: "ak47", HealthDamage: 30},
+			// Self and team molotov damage is not utility damage
+			{AttackerSID: "[U:1:1]", VictimSID: "[U:1:1]", Weapon: "inferno", HealthDamage: 15},
+			{AttackerSID: "[U:1:1]", AttackerTeam: "CT", VictimSID: "[U:1:3]", VictimTeam: "CT", Weapon: "inferno", HealthDamage: 25},
+		},
+		FlashEvents: []logparser.FlashEvent{
+			{FlasherSID: "[U:1:1]", VictimSID: "[U:1:2]"},
+			{FlasherSID: "[U:1:1]", VictimSID: "[U:1:2]"},
+			{FlasherSID: "[U:1:1]", VictimSID: "[U:1:1]", TeamFlash: true},
+		},
+		RoundStats: []logparser.RoundStats{
+			{Players: []logparser.PlayerStats{{AccountID: 1, UD: 70}, {AccountID: 2}}},
+			{Players: []logparser.PlayerStats{{AccountID: 1}, {AccountID: 2}}},
+		},
+	}
+
+	data := processor.buildUtilityData(parseResult, players, playerIndex)
+
+	// hegrenade, molotov, flashbang, smokegrenade, decoy
+	if data.Throws[0][0] != 1 || data.Throws[0][1] != 1 || data.Throws[0][2] != 2 {
+		t.Errorf("Unexpected throws: %v", data.Throws[0])
+	}
+	if data.Damage[0][0] != 50 || data.Damage[0][1] != 20 {
+		t.Errorf("Unexpected grenade damage: %v", data.Damage[0])
+	}
+	if data.ThrowsPerRound[0] != 2 || data.DamagePerNade[0] != 35 {
+		t.Errorf("Unexpected per-round/per-nade: %v %v", data.ThrowsPerRound[0], data.DamagePerNade[0])
+	}
+	if data.EnemiesBlinded[0] != 2 || data.TeamBlinded[0] != 1 || data.BlindsPerFlash[0] != 1 {
+		t.Errorf("Unexpected flash efficiency: %d %d %v", data.EnemiesBlinded[0], data.TeamBlinded[0], data.BlindsPerFlash[0])
+	}
+	if data.ReportedUD[0] != 70 || data.Rounds[1] != 2 {
+		t.Errorf("Unexpected reported UD or rounds: %v %v", data.ReportedUD, data.Rounds)
+	}
+}
+
+// TestBuildBombData_PlantsAndWinConditions tests per-map plant rates, planter stats and win conditions
+func TestBuildBombData_PlantsAndWinConditions(t *testing.T) {
+	processor := New()
+
+	players := []Player{{Key: "[U:1:1]", Title: "Alice"}, {Key: "[U:1:2]", Title: "Bob"}}
+	playerIndex := map[string]int{"[U:1:1]": 0, "[U:1:2]": 1}
+
+	rounds := []logparser.RoundStats{
+		{Map: "de_inferno", BombSite: "A", PlanterSID: "[U:1:1]", Winner: 2, WinCondition: logparser.WinBombExploded},
+		{Map: "de_inferno", BombSite: "B", PlanterSID: "[U:1:1]", Winner: 3, WinCondition: logparser.WinBombDefused},
+		{Map: "de_inferno", Winner: 3, WinCondition: logparser.WinElimination},
+		{Map: "de_dust2", BombSite: "B", PlanterSID: "[U:1:2]", Winner: 2, WinCondition: logparser.WinElimination},
+	}
+	events := []logparser.BombEvent{
+		{PlayerSID: "[U:1:2]", EventType: "drop"},
+		{PlayerSID: "[U:1:2]", EventType: "pickup"},
+	}
+
+	data := processor.buildBombData(rounds, events, players, playerIndex)
+
+	if len(data.Maps) != 2 || data.Maps[0].Map != "de_dust2" {
+		t.Fatalf("Expected maps sorted by name, got %+v", data.Maps)
+	}
+	inferno := data.Maps[1]
+	if inferno.Rounds != 3 || inferno.PlantsA != 1 || inferno.PlantsB != 1 || inferno.PostPlantWins != 1 {
+		t.Errorf("Unexpected inferno stats: %+v", inferno)
+	}
+	if inferno.WinConditions[logparser.WinBombDefused] != 1 || inferno.WinConditions[logparser.WinElimination] != 1 {
+		t.Errorf("Unexpected win conditions: %v", inferno.WinConditions)
+	}
+	if data.Plants[0] != 2 || data.PlantsA[0] != 1 || data.PostPlantWins[0] != 1 {
+		t.Errorf("Unexpected planter stats for Alice: %d %d %d", data.Plants[0], data.PlantsA[0], data.PostPlantWins[0])
+	}
+	if data.Drops[1] != 1 || data.PlantsB[1] != 1 {
+		t.Errorf("Unexpected stats for Bob: drops %d, plants B %d", data.Drops[1], data.PlantsB[1])
+	}
+}
+
+// TestBuildHostageData_Leaderboard tests hostage rescues, kills and rescue wins on hostage maps
+func TestBuildHostageData_Leaderboard(t *testing.T) {
+	processor := New()
+
+	players := []Player{{Key: "[U:1:1]", Title: "Alice"}, {Key: "[U:1:2]", Title: "Bob"}}
+	playerIndex := map[string]int{"[U:1:1]": 0, "[U:1:2]": 1}
+
+	rounds := []logparser.RoundStats{
+		{Map: "cs_office", Winner: 3, WinCondition: logparser.WinHostageRescued, Players: []logparser.PlayerStats{
+			{AccountID: 1, Team: 3}, {AccountID: 2, Team: 2},
+		}},
+		{Map: "de_dust2", Winner: 3, WinCondition: logparser.WinElimination, Players: []logparser.PlayerStats{
+			{AccountID: 1, Team: 3}, {AccountID: 2, Team: 2},
+		}},
+	}
+	events := []logparser.HostageEvent{
+		{PlayerSID: "[U:1:1]", EventType: "pickup"},
+		{PlayerSID: "[U:1:1]", EventType: "rescue"},
+		{PlayerSID: "[U:1:1]", EventType: "rescue"},
+		{PlayerSID: "[U:1:2]", EventType: "kill"},
+	}
+
+	data := processor.buildHostageData(rounds, events, players, playerIndex)
+
+	if data.Rescues[0] != 2 || data.Pickups[0] != 1 || data.Kills[1] != 1 {
+		t.Errorf("Unexpected hostage events: rescues %v, pickups %v, kills %v", data.Rescues, data.Pickups, data.Kills)
+	}
+	if data.Rounds[0] != 1 || data.Rounds[1] != 1 {
+		t.Errorf("Expected only cs_office rounds to be counted, got %v", data.Rounds)
+	}
+	if data.RescueWins[0] != 1 || data.RescueWins[1] != 0 {
+		t.Errorf("Unexpected rescue wins: %v", data.RescueWins)
+	}
+}
+
+// TestProcess_ShameDeaths tests that team kills stay out of kill stats unless SetCountTeamKills is on
+func TestProcess_ShameDeaths(t *testing.T) {
+	const alice, bob, carol = "[U:1:100001]", "[U:1:100002]", "[U:1:100003]"
+	parseResult := &logparser.ParseResult{
+		KillEvents: []logparser.KillEvent{
+			{KillerName: "Alice", KillerSID: alice, VictimName: "Carol", VictimSID: carol, Weapon: "ak47", Date: "2025-09-05"},
+		},
+		ShameEvents: []logparser.ShameEvent{
+			{Kind: logparser.ShameTeamKill, PlayerName: "Alice", PlayerSID: alice, VictimName: "Bob", VictimSID: bob, Weapon: "hegrenade", Date: "2025-09-05"},
+			{Kind: logparser.ShameWorld, PlayerName: "Bob", PlayerSID: bob, VictimName: "Bob", VictimSID: bob, Weapon: "world", Date: "2025-09-05"},
+			{Kind: logparser.ShameSuicide, PlayerName: "Carol", PlayerSID: carol, VictimName: "Carol", VictimSID: carol, Weapon: "molotov", Date: "2025-09-06"},
+			{Kind: logparser.ShameBomb, PlayerName: "Carol", PlayerSID: carol, VictimName: "Carol", VictimSID: carol, Date: "2025-09-06"},
+		},
+		WeaponSet: map[string]struct{}{"ak47": {}},
+	}
+
+	data := New().Process(parseResult)
+	if len(data.Players) != 3 || data.Players[1].Title != "Bob" {
+		t.Fatalf("Expected players who only appear in shame events, got %+v", data.Players)
+	}
+	shame := data.ShameData
+	if shame.TeamKills[0] != 1 || shame.TeamKilled[1] != 1 || shame.WorldDeaths[1] != 1 || shame.Suicides[2] != 1 || shame.BombDeaths[2] != 1 {
+		t.Errorf("Unexpected shame data: %+v", shame)
+	}
+	if data.KillMatrix.Matrix[0][1] != 0 || data.KillMatrix.Matrix[0][2] != 1 || len(data.KillEvents) != 1 {
+		t.Errorf("Team kills must not count as kills by default: %v", data.KillMatrix.Matrix)
+	}
+	if len(data.DailyShame["2025-09-05"]) != 2 || len(data.DailyShame["2025-09-06"]) != 2 {
+		t.Errorf("Unexpected daily shame: %+v", data.DailyShame)
+	}
+
+	processor := New()
+	processor.SetCountTeamKills(true)
+	data = processor.Process(parseResult)
+	if data.KillMatrix.Matrix[0][1] != 1 || len(data.KillEvents) != 2 || len(data.Weapons) != 2 {
+		t.Errorf("Expected the team kill counted as a kill, got matrix %v, weapons %v", data.KillMatrix.Matrix, data.Weapons)
+	}
+}
+
+// TestBuildClutchData tests clutch attempts and wins by the number of enemies
+func TestBuildClutchData(t *testing.T) {
+	players := []Player{{Key: "[U:1:100001]", Title: "Alice"}, {Key: "[U:1:100002]", Title: "Bob"}}
+	playerIndex := map[string]int{"[U:1:100001]": 0, "[U:1:100002]": 1}
+	rounds := []logparser.RoundStats{
+		{Winner: 3, Players: []logparser.PlayerStats{{AccountID: 100001, Team: 3, ClutchVs: 2}, {AccountID: 100002, Team: 2, ClutchVs: 1}}},
+		{Winner: 2, Players: []logparser.PlayerStats{{AccountID: 100001, Team: 3, ClutchVs: 2}, {AccountID: 100002, Team: 2}}},
+		{Winner: 3, Players: []logparser.PlayerStats{{AccountID: 100001, Team: 3, ClutchVs: 5}}},
+	}
+
+	data := New().buildClutchData(rounds, players, playerIndex)
+	if data.Attempts[0][1] != 2 || data.Wins[0][1] != 1 || data.Attempts[0][4] != 1 || data.Wins[0][4] != 1 {
+		t.Errorf("Unexpected Alice clutches: %v / %v", data.Attempts[0], data.Wins[0])
+	}
+	if data.Attempts[1][0] != 1 || data.Wins[1][0] != 0 {
+		t.Errorf("Unexpected Bob clutches: %v / %v", data.Attempts[1], data.Wins[1])
+	}
+}
@@ -0,0 +1,121 @@
+package stats
+
+import (
+	"time"
+
+	"oldfartscounter/internal/logparser"
+)
+
+// DefaultTradeWindow — за сколько после смерти союзника нужно убить его убийцу, чтобы это считалось разменом
+const DefaultTradeWindow = 5 * time.Second
+
+// TradeEvent — размен: игрок убил убийцу союзника в пределах окна после его смерти
+type TradeEvent struct {
+	PlayerName string  // Кто разменял
+	PlayerSID  string  // SteamID разменявшего
+	VictimName string  // Чья смерть разменяна (союзник)
+	VictimSID  string  // SteamID союзника
+	KillerSID  string  // SteamID разменянного противника
+	Seconds    float64 // Время от смерти союзника до размена
+	Round      int     // Номер раунда (как у KillEvent)
+	MatchID    string  // ID матча
+	Date       string  // Дата в формате YYYY-MM-DD
+}
+
+// detectTrades находит размены в упорядоченном потоке убийств. Убийства одного раунда идут подряд
+// (как в ParseResult.KillEvents); смерть разменяна, если её убийцу убил союзник жертвы не позже window.
+// Каждая смерть разменивается не больше одного раза; тимкиллы в размены не входят.
+func detectTrades(kills []logparser.KillEvent, window time.Duration) []TradeEvent {
+	type death struct {
+		event  logparser.KillEvent
+		traded bool
+	}
+
+	var trades []TradeEvent
+	var pending []death
+	matchID, round := "", -1
+	for _, kill := range kills {
+		if kill.MatchID != matchID || kill.Round != round {
+			matchID, round = kill.MatchID, kill.Round
+			pending = pending[:0]
+		}
+		if kill.KillerTeam != "" && kill.KillerTeam == kill.VictimTeam {
+			continue
+		}
+
+		for i := range pending {
+			d := &pending[i]
+			if d.traded || d.event.KillerSID != kill.VictimSID || d.event.VictimSID == kill.KillerSID {
+				continue
+			}
+			// Разменять может только союзник погибшего; без команд в логе — любой, кроме самого убийцы
+			if d.event.VictimTeam != "" && kill.KillerTeam != d.event.VictimTeam {
+				continue
+			}
+			elapsed := kill.Time.Sub(d.event.Time)
+			if elapsed < 0 || elapsed > window {
+				continue
+			}
+			d.traded = true
+			trades = append(trades, TradeEvent{
+				PlayerName: kill.KillerName,
+				PlayerSID:  kill.KillerSID,
+				VictimName: d.event.VictimName,
+				VictimSID:  d.event.VictimSID,
+				KillerSID:  kill.VictimSID,
+				Seconds:    elapsed.Seconds(),
+				Round:      kill.Round,
+				MatchID:    kill.MatchID,
+				Date:       kill.Date,
+			})
+		}
+		pending = append(pending, death{event: kill})
+	}
+	return trades
+}
+
+// applyTradeStats дописывает в рейтинги размены: сделанные, разменянные и неразменянные смерти, среднее время.
+// Смерти считаются по kills — тем же убийствам, что и в остальной статистике (с тимкиллами, если они включены).
+func applyTradeStats(ratings []PlayerRating, kills []logparser.KillEvent, trades []TradeEvent) {
+	index := make(map[int64]int, len(ratings))
+	for i, rating := range ratings {
+		index[rating.AccountID] = i
+	}
+	lookup := func(sid string) (*PlayerRating, bool) {
+		id, ok := logparser.AccountIDFromSID(sid)
+		if !ok {
+			return nil, false
+		}
+		i, ok := index[id]
+		if !ok {
+			return nil, false
+		}
+		return &ratings[i], true
+	}
+
+	deaths := make(map[int64]int)
+	for _, kill := range kills {
+		if rating, ok := lookup(kill.VictimSID); ok {
+			deaths[rating.AccountID]++
+		}
+	}
+
+	tradeTime := make(map[int64]float64)
+	for _, trade := range trades {
+		if rating, ok := lookup(trade.PlayerSID); ok {
+			rating.TradesMade++
+			tradeTime[rating.AccountID] += trade.Seconds
+		}
+		if rating, ok := lookup(trade.VictimSID); ok {
+			rating.TradedDeaths++
+		}
+	}
+
+	for i := range ratings {
+		rating := &ratings[i]
+		rating.UntradedDeaths = deaths[rating.AccountID] - rating.TradedDeaths
+		if rating.TradesMade > 0 {
+			rating.AvgTradeTime = tradeTime[rating.AccountID] / float64(rating.TradesMade)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"oldfartscounter/internal/logparser"
+)
+
+// TestDetectTrades checks the window boundary, the teammate requirement and round separation
+func TestDetectTrades(t *testing.T) {
+	const alice, bob, carol, dave, eve = "[U:1:100001]", "[U:1:100002]", "[U:1:100003]", "[U:1:100004]", "[U:1:100005]"
+	start := time.Date(2025, 9, 5, 18, 0, 0, 0, time.UTC)
+	kill := func(sec int, round int, killer, killerTeam, victim, victimTeam string) logparser.KillEvent {
+		return logparser.KillEvent{
+			KillerName: killer, KillerSID: killer, KillerTeam: killerTeam,
+			VictimName: victim, VictimSID: victim, VictimTeam: victimTeam,
+			Time: start.Add(time.Duration(sec) * time.Second), Date: "2025-09-05", MatchID: "m1", Round: round,
+		}
+	}
+
+	kills := []logparser.KillEvent{
+		// Round 1: Dave kills Alice, Bob trades him after 3s; Eve kills Carol and is never traded
+		kill(0, 1, dave, "TERRORIST", alice, "CT"),
+		kill(3, 1, bob, "CT", dave, "TERRORIST"),
+		kill(4, 1, eve, "TERRORIST", carol, "CT"),
+		// Round 2: Carol trades Alice exactly at the window, but kills Dave too late for Bob's death
+		kill(20, 2, eve, "TERRORIST", alice, "CT"),
+		kill(22, 2, dave, "TERRORIST", bob, "CT"),
+		kill(25, 2, carol, "CT", eve, "TERRORIST"),
+		kill(30, 2, carol, "CT", dave, "TERRORIST"),
+		// Round 3: the killer's own teammate kills him — a team kill, not a trade
+		kill(40, 3, dave, "TERRORIST", alice, "CT"),
+		kill(41, 3, eve, "TERRORIST", dave, "TERRORIST"),
+		// Round 4: Bob's kill on Dave comes from a new round and does not trade Alice's round 3 death
+		kill(42, 4, bob, "CT", dave, "TERRORIST"),
+	}
+
+	want := []struct {
+		player, victim string
+		seconds        float64
+	}{{bob, alice, 3}, {carol, alice, 5}, {carol, bob, 8}}
+
+	trades := detectTrades(kills, 5*time.Second)
+	if len(trades) != 2 {
+		t.Fatalf("Expected 2 trades, got %d: %+v", len(trades), trades)
+	}
+	for i, w := range want[:2] {
+		if trades[i].PlayerSID != w.player || trades[i].VictimSID != w.victim || trades[i].Seconds != w.seconds {
+			t.Errorf("trade %d = %+v, want %+v", i, trades[i], w)
+		}
+	}
+
+	// Carol's second kill trades Bob only with a wider window
+	trades = detectTrades(kills, 10*time.Second)
+	if len(trades) != 3 || trades[2].PlayerSID != want[2].player || trades[2].VictimSID != want[2].victim {
+		t.Errorf("Expected Bob's death traded with a 10s window, got %+v", trades)
+	}
+}
+
+// TestProcess_TradeStats checks trade counters in player ratings
+func TestProcess_TradeStats(t *testing.T) {
+	const alice, bob, dave = "[U:1:100001]", "[U:1:100002]", "[U:1:100004]"
+	start := time.Date(2025, 9, 5, 18, 0, 0, 0, time.UTC)
+	players := []logparser.PlayerStats{{AccountID: 100001, Team: 3}, {AccountID: 100002, Team: 3}, {AccountID: 100004, Team: 2}}
+	parseResult := &logparser.ParseResult{
+		KillEvents: []logparser.KillEvent{
+			{KillerName: "Dave", KillerSID: dave, KillerTeam: "TERRORIST", VictimName: "Alice", VictimSID: alice, VictimTeam: "CT", Time: start, Date: "2025-09-05", Round: 1},
+			{KillerName: "Bob", KillerSID: bob, KillerTeam: "CT", VictimName: "Dave", VictimSID: dave, VictimTeam: "TERRORIST", Time: start.Add(2 * time.Second), Date: "2025-09-05", Round: 1},
+			{KillerName: "Dave", KillerSID: dave, KillerTeam: "TERRORIST", VictimName: "Alice", VictimSID: alice, VictimTeam: "CT", Time: start.Add(time.Minute), Date: "2025-09-05", Round: 2},
+		},
+		RoundStats: []logparser.RoundStats{
+			{RoundNumber: 1, Date: "2025-09-05", Players: players},
+			{RoundNumber: 2, Date: "2025-09-05", Players: players},
+		},
+	}
+
+	data := New().Process(parseResult)
+	if len(data.TradeEvents) != 1 || len(data.DailyTrades["2025-09-05"]) != 1 || data.TradeWindow != DefaultTradeWindow {
+		t.Fatalf("Expected one trade, got %+v", data.TradeEvents)
+	}
+	ratings := make(map[int64]PlayerRating)
+	for _, r := range data.PlayerRatings {
+		ratings[r.AccountID] = r
+	}
+	if r := ratings[100001]; r.TradedDeaths != 1 || r.UntradedDeaths != 1 {
+		t.Errorf("Unexpected Alice trade stats: %+v", r)
+	}
+	if r := ratings[100002]; r.TradesMade != 1 || r.AvgTradeTime != 2 {
+		t.Errorf("Unexpected Bob trade stats: %+v", r)
+	}
+	if r := ratings[100004]; r.TradesMade != 0 || r.UntradedDeaths != 1 {
+		t.Errorf("Unexpected Dave trade stats: %+v", r)
+	}
+}
@@ -1,6 +1,10 @@
 package stats
 
-import "oldfartscounter/internal/logparser"
+import (
+	"time"
+
+	"oldfartscounter/internal/logparser"
+)
 
 // StatsData содержит обработанные статистики
 type StatsData struct {
@@ -8,22 +12,54 @@ type StatsData struct {
 	Weapons            []string
 	KillMatrix         KillMatrix
 	WeaponData         WeaponData
+	KillModifierData   KillModifierData
 	FlashData          FlashData
+	DamageData         DamageData
+	UtilityData        UtilityData
 	DefuseData         DefuseData
-	DateRange          string  // Период данных в формате "DD-MM-YYYY - DD-MM-YYYY"
-	HighlightedPlayer  string  // Игрок для золотой подсветки в табе "Сорян, Братан"
-	MinRoundsForRating float64 // Минимальное количество раундов для достоверного рейтинга (K)
-	AverageMu          float64 // Средний EPI всех игроков (μ) - рассчитывается из реальных данных
+	BombData           BombData
+	HostageData        HostageData
+	EconomyData        EconomyData
+	ShameData          ShameData
+	ClutchData         ClutchData
+	OpeningStats       []PlayerOpeningStats // Первые дуэли раундов по игрокам, сторонам и картам
+	DateRange          string               // Период данных в формате "DD-MM-YYYY - DD-MM-YYYY"
+	HighlightedPlayer  string               // Игрок для золотой подсветки в табе "Сорян, Братан"
+	MinRoundsForRating float64              // Минимальное количество раундов для достоверного рейтинга (K)
+	AverageMu          float64              // Средний EPI всех игроков (μ) - рассчитывается из реальных данных
+	TradeWindow        time.Duration        // Окно размена, с которым посчитаны TradeEvents
 	KillEvents         []logparser.KillEvent
 	FlashEvents        []logparser.FlashEvent
+	DamageEvents       []logparser.DamageEvent
+	GrenadeEvents      []logparser.GrenadeEvent
 	DefuseEvents       []logparser.DefuseEvent
+	BombEvents         []logparser.BombEvent
+	HostageEvents      []logparser.HostageEvent
+	ShameEvents        []logparser.ShameEvent // Тимкиллы, самоубийства, падения и смерти от бомбы
+	TradeEvents        []TradeEvent           // Размены: убийства убийц союзников в пределах окна
+	OpeningDuels       []OpeningDuel          // Первое убийство каждого раунда
 	RoundStats         []logparser.RoundStats // Статистика раундов
+	Matches            []logparser.Match      // Завершённые матчи (диапазоны указывают в срезы событий выше)
 	PlayerRatings      []PlayerRating         // Агрегированные рейтинги игроков
+	RatingModel        string                 // Модель рейтинга, выбранная по умолчанию (Processor.SetRatingModel)
+	ModelRatings       []ModelRatings         // Рейтинги всех моделей в порядке RatingModels
 	// Агрегированные данные по датам для оптимизации
-	DailyKills  map[string][]logparser.KillEvent   // дата -> события
-	DailyFlash  map[string][]logparser.FlashEvent  // дата -> события
-	DailyDefuse map[string][]logparser.DefuseEvent // дата -> события
-	DailyRounds map[string][]logparser.RoundStats  // дата -> раунды
+	DailyKills   map[string][]logparser.KillEvent    // дата -> события
+	DailyFlash   map[string][]logparser.FlashEvent   // дата -> события
+	DailyDamage  map[string][]DamageHit              // дата -> события
+	DailyThrows  map[string][]logparser.GrenadeEvent // дата -> события
+	DailyBomb    map[string][]logparser.BombEvent    // дата -> события
+	DailyHostage map[string][]logparser.HostageEvent // дата -> события
+	DailyDefuse  map[string][]logparser.DefuseEvent  // дата -> события
+	DailyShame   map[string][]logparser.ShameEvent   // дата -> события
+	DailyTrades  map[string][]TradeEvent             // дата -> размены
+	DailyRounds  map[string][]logparser.RoundStats   // дата -> раунды
+
+	// Состояние для Processor.Apply
+	startDate, endDate string // Период данных в формате ParseResult.StartDate
+	roundGroups        int    // Последний DamageHit.RoundGroup
+	logKills           int    // Убийства из ParseResult.KillEvents: тимкиллы (SetCountTeamKills) идут в KillEvents после них
+	purchases, customs int    // Длины ParseResult.PurchaseEvents и CustomEvents — для диапазонов Matches
 }
 
 // Player представляет игрока
@@ -40,11 +76,30 @@ type KillMatrix struct {
 
 // WeaponData содержит данные по оружию
 type WeaponData struct {
-	KillerWeaponMatrix [][]int // Players × Weapons
-	VictimWeaponMatrix [][]int // Players × Weapons
-	WeaponKillsMatrix  [][]int // Weapons × Players (транспонированная)
+	KillerWeaponMatrix [][]int   // Players × Weapons
+	VictimWeaponMatrix [][]int   // Players × Weapons
+	WeaponKillsMatrix  [][]int   // Weapons × Players (транспонированная)
+	AvgDistance        []float64 // Средняя дистанция убийства по оружию в юнитах (индекс как в Weapons)
 	KillerMax          int
 	VictimMax          int
+	distanceSum        []float64 // Сумма дистанций по оружию — для AvgDistance в Processor.Apply
+	distanceCount      []int     // Убийства с известными позициями по оружию
+}
+
+// KillModifiers содержит счетчики модификаторов убийств
+type KillModifiers struct {
+	Kills         int // Всего убийств
+	Headshots     int // Убийства в голову
+	Wallbangs     int // Убийства прострелом (penetrated)
+	NoScopes      int // Убийства без прицела
+	ThroughSmoke  int // Убийства сквозь дым
+	AttackerBlind int // Убийства вслепую
+}
+
+// KillModifierData содержит модификаторы убийств по игрокам-убийцам и по оружию
+type KillModifierData struct {
+	ByPlayer []KillModifiers // индекс как в Players
+	ByWeapon []KillModifiers // индекс как в Weapons
 }
 
 // FlashData содержит данные по флешкам
@@ -55,6 +110,102 @@ type FlashData struct {
 	SecondsMax    float64
 }
 
+// DamageHit — попадание для таба урона: событие лога, раунд, к которому оно отнесено, и урон по своим
+type DamageHit struct {
+	logparser.DamageEvent
+	RoundGroup int  // Раунд попадания (см. markDamageHits): одинаковый у попаданий одного раунда одного матча
+	Friendly   bool // Урон по союзнику или себе
+}
+
+// DamageData содержит матрицу урона: кто кому, чем и куда
+type DamageData struct {
+	Matrix         [][]int  // Attacker × Victim: снятое здоровье
+	Given          []int    // Нанесённый урон по игрокам
+	Received       []int    // Полученный урон по игрокам
+	WithoutKill    []int    // Урон по жертвам, которых игрок не добил в том же раунде
+	TeamDamage     []int    // Урон по союзникам и себе: не входит в остальные поля и матрицы
+	Weapons        []string // Оружие, которым наносили урон (включая гранаты)
+	WeaponMatrix   [][]int  // Players × Weapons: нанесённый урон
+	Hitgroups      []string // Части тела
+	HitgroupMatrix [][]int  // Players × Hitgroups: количество попаданий
+	Max            int      // Максимум в Matrix
+}
+
+// UtilityData содержит статистику гранат: броски, урон и ослепления по игрокам
+type UtilityData struct {
+	Grenades       []string  // Виды гранат (см. UtilityGrenades)
+	Throws         [][]int   // Players × Grenades: количество бросков
+	Damage         [][]int   // Players × Grenades: снятое гранатами здоровье
+	Rounds         []int     // Сыгранные раунды
+	ThrowsPerRound []float64 // Все броски / раунды
+	DamagePerNade  []float64 // Урон HE и молотовых / их броски
+	EnemiesBlinded []int     // Ослеплённые противники
+	TeamBlinded    []int     // Ослеплённые союзники (включая себя)
+	BlindsPerFlash []float64 // Ослеплённые противники / брошенные флешки
+	ReportedUD     []int     // Сумма UD из статистики раундов — для сверки с Damage
+}
+
+// UtilityGrenades — виды гранат в порядке отображения
+var UtilityGrenades = []string{"hegrenade", "molotov", "flashbang", "smokegrenade", "decoy"}
+
+// BombData содержит статистику закладок бомбы и условий победы в раундах
+type BombData struct {
+	Maps          []BombMapStats // По картам, отсортировано по названию
+	Plants        []int          // Закладки по игрокам
+	PlantsA       []int          // Закладки на A по игрокам
+	PlantsB       []int          // Закладки на B по игрокам
+	PostPlantWins []int          // Раунды, выигранные после закладки игрока
+	Drops         []int          // Выбросы бомбы по игрокам
+}
+
+// BombMapStats содержит статистику бомбы и исходов раундов на одной карте
+type BombMapStats struct {
+	Map           string
+	Rounds        int
+	PlantsA       int
+	PlantsB       int
+	PostPlantWins int            // Раунды с закладкой, выигранные T
+	WinConditions map[string]int // Условие победы -> количество раундов
+}
+
+// HostageData содержит статистику заложников по игрокам (карты cs_*)
+type HostageData struct {
+	Rescues    []int // Спасённые заложники
+	Pickups    []int // Подобранные заложники
+	Kills      []int // Убитые заложники — штраф
+	Rounds     []int // Сыгранные раунды на картах с заложниками
+	RescueWins []int // Раунды за CT, выигранные спасением заложников
+}
+
+// EconomyData содержит классификацию закупок и экономическое поведение игроков
+type EconomyData struct {
+	BuyTypes     []string  // Типы закупки (см. BuyTypes)
+	TeamRounds   []int     // Раунды команд по типу закупки
+	TeamWins     []int     // Победы команд по типу закупки
+	AvgSpend     []float64 // Средние траты игрока за раунд
+	PlayerRounds [][]int   // Players × BuyTypes: раунды по собственной закупке игрока
+	PlayerWins   [][]int   // Players × BuyTypes: победы по собственной закупке игрока
+	ForceLosses  []int     // Проигранные раунды на форсе
+	Saves        []int     // Проигранные раунды, в которых игрок выжил
+	SavedValue   []int     // Стоимость оружия, перенесённого в следующие раунды
+	spent        []int     // Траты игроков по раундам с покупками — для AvgSpend
+}
+
+// ShameData содержит позорные смерти по игрокам (индекс как в Players)
+type ShameData struct {
+	TeamKills   []int // Убитые союзники
+	TeamKilled  []int // Смерти от рук союзников
+	Suicides    []int // Самоубийства своей гранатой, молотовым или командой kill
+	WorldDeaths []int // Падения и урон от карты
+	BombDeaths  []int // Смерти от взрыва бомбы
+}
+
+// ClutchData содержит клатчи игроков (индекс как в Players) по числу противников: [0] — 1v1, ..., [4] — 1v5
+type ClutchData struct {
+	Attempts [][]int // Players × MaxClutchEnemies: остался последним живым в команде против N противников
+	Wins     [][]int // Players × MaxClutchEnemies: и команда выиграла раунд
+}
+
 // DefuseData содержит данные по дефьюзу
 type DefuseData struct {
 	Attempts          []int // общее количество попыток дефьюза по игрокам
@@ -81,4 +232,15 @@ type PlayerRating struct {
 	TotalAssists int     // Общие ассисты
 	WinRounds    int     // Выигранные раунды
 	LastPlayed   string  // Дата последней игры
+	// Размены (см. TradeEvent); тимкиллы не учитываются
+	TradesMade     int     // Сколько смертей союзников разменял
+	TradedDeaths   int     // Сколько собственных смертей разменяли союзники
+	UntradedDeaths int     // Смерти, которые никто не разменял
+	AvgTradeTime   float64 // Среднее время размена в секундах
+	// KAST и выживаемость (см. applyKASTStats)
+	KASTRounds     int     // Раунды с убийством, ассистом, выживанием или разменянной смертью
+	KAST           float64 // KASTRounds / RoundsPlayed, %
+	SurvivedRounds int     // Раунды, до конца которых игрок дожил
+	SurvivalRate   float64 // SurvivedRounds / RoundsPlayed, %
+	DeathsPerRound float64 // TotalDeaths / RoundsPlayed
 }
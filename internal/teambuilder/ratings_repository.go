@@ -0,0 +1,73 @@
+package teambuilder
+
+import (
+	"fmt"
+
+	"oldfartscounter/internal/logparser"
+	"oldfartscounter/internal/stats"
+)
+
+// ratingsRepository — репозиторий с рейтингами, посчитанными по логам одной из моделей stats.RatingModels
+type ratingsRepository struct {
+	data      []Player
+	averageMu float64 // Средний рейтинг модели — от него считаются категории
+}
+
+// NewRatingsRepository создает репозиторий из рейтингов модели. Порядок игроков сохраняется:
+// stats.ModelRatings уже отсортированы по убыванию рейтинга.
+func NewRatingsRepository(ratings stats.ModelRatings) PlayerRepository {
+	repo := &ratingsRepository{
+		data:      make([]Player, 0, len(ratings.Ratings)),
+		averageMu: ratings.Mean,
+	}
+	for _, r := range ratings.Ratings {
+		repo.data = append(repo.data, Player{NickName: r.Name, Score: r.Score})
+	}
+	return repo
+}
+
+// LoadRatingsRepository парсит логи (директории, файлы, архивы) и строит репозиторий по рейтингам модели model
+func LoadRatingsRepository(paths []string, model string) (PlayerRepository, error) {
+	processor := stats.New()
+	if err := processor.SetRatingModel(model); err != nil {
+		return nil, err
+	}
+	parseResult, err := logparser.New().ParsePaths(paths, "")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка парсинга логов: %w", err)
+	}
+	for _, ratings := range processor.Process(parseResult).ModelRatings {
+		if ratings.Model == model {
+			return NewRatingsRepository(ratings), nil
+		}
+	}
+	return nil, fmt.Errorf("модель рейтинга %q не посчитана", model)
+}
+
+// GetAll — возвращает всех игроков
+func (r *ratingsRepository) GetAll() []Player {
+	return r.data
+}
+
+// GetTop — возвращает n лучших игроков
+func (r *ratingsRepository) GetTop(n int) []Player {
+	if n > len(r.data) {
+		n = len(r.data)
+	}
+	return r.data[:n]
+}
+
+// FindByName — поиск игрока по нику
+func (r *ratingsRepository) FindByName(nick string) *Player {
+	for _, p := range r.data {
+		if p.NickName == nick {
+			return &p
+		}
+	}
+	return nil
+}
+
+// GetAverageMu — возвращает средний рейтинг модели для расчета категорий
+func (r *ratingsRepository) GetAverageMu() float64 {
+	return r.averageMu
+}
@@ -0,0 +1,38 @@
+package teambuilder
+
+import (
+	"testing"
+
+	"oldfartscounter/internal/stats"
+)
+
+// TestRatingsRepository проверяет, что рейтинги модели становятся счётом игроков для билдера
+func TestRatingsRepository(t *testing.T) {
+	repo := NewRatingsRepository(stats.ModelRatings{
+		Model: "hltv2",
+		Mean:  1.05,
+		Ratings: []stats.ModelRating{
+			{AccountID: 1, Name: "Looka", Rounds: 300, Score: 1.31},
+			{AccountID: 2, Name: "jojo", Rounds: 250, Score: 0.94},
+		},
+	})
+
+	if top := repo.GetTop(5); len(top) != 2 || top[0].NickName != "Looka" {
+		t.Errorf("Неожиданный топ: %+v", top)
+	}
+	if p := repo.FindByName("jojo"); p == nil || p.Score != 0.94 {
+		t.Errorf("Неожиданный игрок jojo: %+v", p)
+	}
+	if repo.FindByName("Station77") != nil {
+		t.Error("Игрок без раундов в логах не должен находиться")
+	}
+	if repo.GetAverageMu() != 1.05 {
+		t.Errorf("Ожидался μ модели 1.05, получено %v", repo.GetAverageMu())
+	}
+
+	// Билдер берёт счёт игроков без Score из репозитория
+	players := NewTeamBuilder(repo).getPlayersScore(Team{{NickName: "Looka"}, {NickName: "jojo", Score: 2}})
+	if players[0].Score != 1.31 || players[1].Score != 2 {
+		t.Errorf("Неожиданный счёт игроков: %+v", players)
+	}
+}